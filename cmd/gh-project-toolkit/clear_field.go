@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/tools/set_field"
+)
+
+var clearFieldCmd = &cobra.Command{
+	Use:          "clear-field",
+	Short:        "Clear a field of an issue in a GitHub project",
+	SilenceUsage: true,
+	RunE:         withTimeout(runClearField),
+}
+
+var (
+	clearFieldProjectURL string
+	clearFieldIssueURL   string
+	clearFieldName       string
+	clearFieldDryRun     bool
+)
+
+func init() {
+	rootCmd.AddCommand(clearFieldCmd)
+
+	clearFieldCmd.Flags().StringVar(&clearFieldProjectURL, "project", "", "Project URL (e.g., https://github.com/orgs/org/projects/123)")
+	clearFieldCmd.Flags().StringVar(&clearFieldIssueURL, "issue", "", "GitHub issue URL")
+	clearFieldCmd.Flags().StringVar(&clearFieldName, "field", "", "Name of the field to clear")
+	clearFieldCmd.Flags().BoolVar(&clearFieldDryRun, "dry-run", false, "Run in dry run mode (no mutations will be performed)")
+
+	for _, name := range []string{"project", "issue", "field"} {
+		if err := clearFieldCmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark flag %s as required: %v", name, err))
+		}
+	}
+}
+
+func runClearField(cmd *cobra.Command, args []string) error {
+	client, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	service := set_field.NewService(client, clearFieldDryRun)
+	if err := service.ClearField(cmd.Context(), clearFieldProjectURL, clearFieldIssueURL, clearFieldName); err != nil {
+		return fmt.Errorf("failed to clear field: %w", err)
+	}
+
+	if clearFieldDryRun {
+		slog.Info("dry run completed successfully")
+	} else {
+		slog.Info("field cleared successfully", "field", clearFieldName, "issue", clearFieldIssueURL)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/tools/diff_fields"
+)
+
+var diffFieldsCmd = &cobra.Command{
+	Use:          "diff-fields",
+	Short:        "Compare the fields of two GitHub projects",
+	SilenceUsage: true,
+	RunE:         withTimeout(runDiffFields),
+}
+
+var (
+	diffFieldsProjectURLs []string
+)
+
+func init() {
+	rootCmd.AddCommand(diffFieldsCmd)
+
+	diffFieldsCmd.Flags().StringArrayVar(&diffFieldsProjectURLs, "project", nil, "Project URL, given twice for the source and target project (e.g., https://github.com/orgs/org/projects/123)")
+
+	if err := diffFieldsCmd.MarkFlagRequired("project"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag project as required: %v", err))
+	}
+}
+
+func runDiffFields(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+	if len(diffFieldsProjectURLs) != 2 {
+		return fmt.Errorf("--project must be given twice, for the source and target project")
+	}
+
+	client, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	service := diff_fields.NewService(client)
+
+	diff, err := service.DiffFields(cmd.Context(), diffFieldsProjectURLs[0], diffFieldsProjectURLs[1])
+	if err != nil {
+		return fmt.Errorf("failed to diff fields: %w", err)
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), diff)
+	}
+
+	if !diff.HasDifferences() {
+		fmt.Fprintln(cmd.OutOrStdout(), "The fields of both projects match")
+		return nil
+	}
+
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "FIELD\tSOURCE\tTARGET")
+	for _, field := range diff.SourceOnly {
+		fmt.Fprintf(w, "%s\t%s\t-\n", field.Name, field.DataType)
+	}
+	for _, field := range diff.TargetOnly {
+		fmt.Fprintf(w, "%s\t-\t%s\n", field.Name, field.DataType)
+	}
+	for _, mismatch := range diff.Mismatches {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", mismatch.Name, mismatch.SourceType, mismatch.TargetType)
+		for _, option := range mismatch.SourceOnlyOptions {
+			fmt.Fprintf(w, "  - %s\toption\t-\n", option)
+		}
+		for _, option := range mismatch.TargetOnlyOptions {
+			fmt.Fprintf(w, "  - %s\t-\toption\n", option)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/tools/list_fields"
+)
+
+var listFieldsCmd = &cobra.Command{
+	Use:          "list-fields",
+	Short:        "List the fields of a GitHub project",
+	SilenceUsage: true,
+	RunE:         withTimeout(runListFields),
+}
+
+var (
+	listFieldsProjectURL string
+)
+
+func init() {
+	rootCmd.AddCommand(listFieldsCmd)
+
+	listFieldsCmd.Flags().StringVar(&listFieldsProjectURL, "project", "", "Project URL (e.g., https://github.com/orgs/org/projects/123)")
+
+	if err := listFieldsCmd.MarkFlagRequired("project"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag project as required: %v", err))
+	}
+}
+
+func runListFields(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+
+	client, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	service := list_fields.NewService(client)
+
+	fields, err := service.ListFields(cmd.Context(), listFieldsProjectURL)
+	if err != nil {
+		return fmt.Errorf("failed to list fields: %w", err)
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), fields)
+	}
+
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "NAME\tTYPE\tID")
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", field.Name, field.DataType, field.ID)
+		for _, option := range field.Options {
+			fmt.Fprintf(w, "  - %s\t\t%s\n", option.Name, option.ID)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/tools/list_issues"
+)
+
+var listIssuesCmd = &cobra.Command{
+	Use:          "list-issues",
+	Short:        "List the issues of a GitHub project",
+	SilenceUsage: true,
+	RunE:         withTimeout(runListIssues),
+}
+
+var (
+	listIssuesProjectURL string
+	listIssuesLimit      int
+)
+
+func init() {
+	rootCmd.AddCommand(listIssuesCmd)
+
+	listIssuesCmd.Flags().StringVar(&listIssuesProjectURL, "project", "", "Project URL (e.g., https://github.com/orgs/org/projects/123)")
+	listIssuesCmd.Flags().IntVar(&listIssuesLimit, "limit", 0, "Maximum number of issues to list (0 lists all issues)")
+
+	if err := listIssuesCmd.MarkFlagRequired("project"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag project as required: %v", err))
+	}
+}
+
+func runListIssues(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+	if listIssuesLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	client, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	service := list_issues.NewService(client)
+
+	issues, err := service.ListIssues(cmd.Context(), listIssuesProjectURL, listIssuesLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), issues)
+	}
+
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "ISSUE\tTITLE")
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s\t%s\n", issue.URL, issue.Title)
+	}
+	return w.Flush()
+}
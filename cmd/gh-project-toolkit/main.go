@@ -2,22 +2,81 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/oauth2"
 
+	"github.com/naag/gh-project-toolkit/internal/config"
+	"github.com/naag/gh-project-toolkit/internal/github"
 	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/github/util"
 	"github.com/naag/gh-project-toolkit/internal/tools/sync_fields"
+	"github.com/naag/gh-project-toolkit/toolkit"
 )
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodePendingChanges is the exit code of a dry run with --exit-code that found changes
+const exitCodePendingChanges = 2
+
+// exitCodeError is returned by commands that exit with a specific code
+type exitCodeError struct {
+	code int
+	msg  string
+}
+
+func (e *exitCodeError) Error() string {
+	return e.msg
+}
+
+// autoDetectValue is the value of --auto-detect-issues, which enables detecting common issues
+// like a boolean flag, or takes a project filter expression restricting the detected issues
+type autoDetectValue struct {
+	enabled bool
+	filter  string
+}
+
+func (v *autoDetectValue) String() string {
+	if v.filter != "" {
+		return v.filter
+	}
+	return strconv.FormatBool(v.enabled)
+}
+
+func (v *autoDetectValue) Set(value string) error {
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		v.enabled, v.filter = enabled, ""
+		return nil
+	}
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("expected true, false or a filter expression")
+	}
+	v.enabled, v.filter = true, value
+	return nil
+}
+
+func (v *autoDetectValue) Type() string {
+	return "filter"
+}
+
 var rootCmd = &cobra.Command{
 	Use:          "gh-project-toolkit",
 	Short:        "GitHub Project Toolkit - Tools for managing GitHub projects",
@@ -39,59 +98,726 @@ var syncFieldsCmd = &cobra.Command{
 	Use:          "sync-fields",
 	Short:        "Sync fields between GitHub project boards",
 	SilenceUsage: true,
-	RunE:         runSyncFields,
+	PreRunE:      loadSyncFieldsConfig,
+	RunE:         withTimeout(runSyncFields),
 }
 
 var (
-	sourceProjectURL string
-	targetProjectURL string
-	issues           []string
-	fieldMappings    []string
-	verboseLevel     int
-	autoDetectIssues bool
-	dryRun           bool
+	sourceProjectURL   string
+	targetProjectURLs  []string
+	issues             []string
+	fieldMappings      []string
+	verboseLevel       int
+	autoDetectIssues   autoDetectValue
+	dryRun             bool
+	maxRetries         int
+	retryBaseDelay     time.Duration
+	respectRateLimit   bool
+	minRequestInterval time.Duration
+	logCost            bool
+	maxAPICalls        int
+	tokenFile          string
+	dryRunReport       string
+	pageSize           int
+	sourcePageSize     int
+	targetPageSize     int
+	concurrency        int
+	noCache            bool
+	failFast           bool
+	allowSameProject   bool
+	pruneTargetItems   bool
+	confirmPrune       bool
+	exitCode           bool
+	serverFilter       string
+	createOptions      bool
+	normalizeSelect    bool
+	mappingFromDiff    bool
+	githubHost         string
+	logStyle           string
+	outputFormat       string
+	summaryJSON        string
+	metricsFile        string
+	filterLabels       []string
+	labelMatch         string
+	issueState         string
+	timeout            time.Duration
+	strictMappings     bool
+	syncMilestone      string
+	syncLabels         string
+	createLabels       bool
+	onDuplicate        string
+	includeDrafts      bool
+	includePRs         bool
+	issuesFile         string
+	preview            bool
+	repos              []string
+	configFile         string
+	mappingFile        string
+	since              string
+	maxIssues          int
+	requireValue       bool
+	addMissing         bool
+	createFields       bool
+	onlyFillEmpty      bool
+	reportOrphans      bool
+	failOnOrphans      bool
+	skipArchived       bool
+	includeArchived    bool
+	journalPath        string
+	appID              int64
+	installationID     int64
+	privateKeyFile     string
 )
 
 func init() {
+	rootCmd.PersistentFlags().CountVarP(&verboseLevel, "verbose", "v", "Verbosity level (-v for debug logs, -vv for debug logs and HTTP traffic)")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", client.DefaultMaxRetries, "Maximum number of retries for transient GitHub API errors")
+	rootCmd.PersistentFlags().DurationVar(&retryBaseDelay, "retry-base-delay", client.DefaultRetryBaseDelay, "Delay before the first retry, doubled on every further retry")
+	rootCmd.PersistentFlags().BoolVar(&respectRateLimit, "respect-rate-limit", true, "Pause until the GitHub rate limit resets when the remaining budget runs low")
+	rootCmd.PersistentFlags().DurationVar(&minRequestInterval, "min-request-interval", 0, "Minimum time between two mutations (e.g., 200ms), with up to half of it added as random jitter, to avoid secondary rate limits")
+	rootCmd.PersistentFlags().IntVar(&maxAPICalls, "max-api-calls", 0, "Stop after this many GitHub API calls, including retries, listing the issues left unprocessed in the summary (0 for no limit)")
+	rootCmd.PersistentFlags().BoolVar(&logCost, "log-cost", false, "Log the rate limit cost of every GraphQL query at debug level and the total cost at the end of a sync")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Always fetch fresh project data instead of using cached data")
+	rootCmd.PersistentFlags().StringVar(&githubHost, "github-host", defaultGitHubHost(), "GitHub Enterprise Server host (defaults to the GITHUB_HOST environment variable or github.com)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "Output format (text or json), json prints the result of a command as a single JSON document on stdout")
+	rootCmd.PersistentFlags().StringVar(&logStyle, "log-style", client.LogStyleStructured, "Format of field update logs (structured or compact)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Abort the command after this duration (e.g., 10m), no limit by default")
+	rootCmd.PersistentFlags().StringVar(&onDuplicate, "on-duplicate", client.OnDuplicateFirst, "How to handle issues that appear more than once in a project (error, first or last)")
+	rootCmd.PersistentFlags().IntVar(&pageSize, "page-size", client.DefaultPageSize, fmt.Sprintf("Number of project items fetched per page (at most %d), lower it if queries of projects with many field values fail", client.MaxPageSize))
+	rootCmd.PersistentFlags().StringVar(&journalPath, "journal", "", "Append every field change to this file as a JSON line, so that it can be reverted with the undo command")
+	rootCmd.PersistentFlags().Int64Var(&appID, "app-id", 0, "Authenticate as this GitHub App instead of with a token (requires --installation-id and --private-key-file)")
+	rootCmd.PersistentFlags().Int64Var(&installationID, "installation-id", 0, "ID of the installation of the GitHub App given by --app-id")
+	rootCmd.PersistentFlags().StringVar(&privateKeyFile, "private-key-file", "", "PEM file with the private key of the GitHub App given by --app-id")
+	rootCmd.PersistentFlags().StringVar(&tokenFile, "token-file", "", "Read the GitHub token from this file instead of the GITHUB_TOKEN environment variable")
+
 	rootCmd.AddCommand(syncFieldsCmd)
 
-	syncFieldsCmd.Flags().StringVar(&sourceProjectURL, "source", "", "Source project URL (e.g., https://github.com/orgs/org/projects/123)")
-	syncFieldsCmd.Flags().StringVar(&targetProjectURL, "target", "", "Target project URL (e.g., https://github.com/users/user/projects/456)")
+	syncFieldsCmd.Flags().SetNormalizeFunc(projectFlagAliases)
+	syncFieldsCmd.Flags().StringVar(&configFile, "config", "", "Read flags from this YAML config file instead of the repository config file")
+	syncFieldsCmd.Flags().StringVar(&sourceProjectURL, "source-project", "", "Source project URL, the owner may differ from the target (e.g., https://github.com/orgs/org/projects/123)")
+	syncFieldsCmd.Flags().StringArrayVar(&targetProjectURLs, "target-project", nil, "Target project URL (e.g., https://github.com/users/user/projects/456), can be specified multiple times to sync the same fields to several projects")
 	syncFieldsCmd.Flags().StringArrayVar(&issues, "issue", nil, "GitHub issue URL (can be specified multiple times)")
-	syncFieldsCmd.Flags().StringArrayVar(&fieldMappings, "field-mapping", nil, "Field mapping in the format 'source=target' (can be specified multiple times)")
-	syncFieldsCmd.Flags().CountVarP(&verboseLevel, "verbose", "v", "Verbosity level (-v for debug logs, -vv for debug logs and HTTP traffic)")
-	syncFieldsCmd.Flags().BoolVar(&autoDetectIssues, "auto-detect-issues", false, "Automatically detect and sync all issues present in both projects")
+	syncFieldsCmd.Flags().StringVar(&issuesFile, "issues-file", "", "Read issue URLs from this file, one per line (blank lines and lines starting with # are ignored)")
+	syncFieldsCmd.Flags().StringArrayVar(&fieldMappings, "field-mapping", nil, "Field mapping in the format 'source=target', optionally with a priority as in 'source=target@1' or a declared target field type as in 'start=Start:date', or 'source<-target' to copy the target field to the source project (can be specified multiple times)")
+	syncFieldsCmd.Flags().StringVar(&mappingFile, "field-mapping-file", "", "Read field mappings from this file, one per line in the format of --field-mapping (blank lines and lines starting with # are ignored)")
+	syncFieldsCmd.Flags().Var(&autoDetectIssues, "auto-detect-issues", "Automatically detect and sync all issues present in both projects, optionally only those matching a filter as in --auto-detect-issues='status:Todo label:bug'")
+	syncFieldsCmd.Flags().Lookup("auto-detect-issues").NoOptDefVal = "true"
 	syncFieldsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Run in dry run mode (no mutations will be performed)")
+	syncFieldsCmd.Flags().BoolVar(&includeDrafts, "include-drafts", false, "Also sync draft issues, matched across projects by their title")
+	syncFieldsCmd.Flags().BoolVar(&includePRs, "include-prs", false, "Also sync pull requests, matched across projects by their URL")
+	syncFieldsCmd.Flags().BoolVar(&skipArchived, "skip-archived", true, "Skip archived project items when detecting the issues to sync")
+	syncFieldsCmd.Flags().BoolVar(&includeArchived, "include-archived", false, "Also sync archived project items (shorthand for --skip-archived=false)")
+	syncFieldsCmd.Flags().BoolVar(&preview, "preview", false, "Print the current and new value of every mapped field without updating anything, which only needs read access")
+	syncFieldsCmd.Flags().BoolVar(&allowSameProject, "allow-same-project", false, "Allow the source and target to be the same project")
+	syncFieldsCmd.Flags().BoolVar(&pruneTargetItems, "prune-target-items", false, "Remove target project items whose issue is not in the source project (requires --confirm-prune)")
+	syncFieldsCmd.Flags().BoolVar(&confirmPrune, "confirm-prune", false, "Confirm that --prune-target-items may delete items from the target project")
+	syncFieldsCmd.Flags().BoolVar(&createOptions, "create-missing-options", false, "Create single select options that are missing in the target field instead of failing")
+	syncFieldsCmd.Flags().BoolVar(&createLabels, "create-missing-labels", false, "Create labels that are missing in the repository of an issue instead of skipping them")
+	syncFieldsCmd.Flags().BoolVar(&createFields, "create-missing-fields", false, "Create mapped target fields that are missing in the target project, like their source field")
+	syncFieldsCmd.Flags().BoolVar(&normalizeSelect, "normalize-select", false, "Match single select values ignoring leading emoji and differences in whitespace")
+	syncFieldsCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Abort on the first issue that fails to sync instead of continuing with the rest")
+	syncFieldsCmd.Flags().IntVar(&concurrency, "concurrency", sync_fields.DefaultConcurrency, "Number of issues processed in parallel")
+	syncFieldsCmd.Flags().IntVar(&sourcePageSize, "source-page-size", 0, "Number of items fetched per page from the source project (defaults to --page-size)")
+	syncFieldsCmd.Flags().IntVar(&targetPageSize, "target-page-size", 0, "Number of items fetched per page from the target project (defaults to --page-size)")
+	syncFieldsCmd.Flags().StringVar(&serverFilter, "server-filter", "", "Only sync source project items matching this project filter expression (e.g., 'status:Done')")
+	syncFieldsCmd.Flags().BoolVar(&mappingFromDiff, "mapping-from-diff", false, "Print field mappings suggested from similar field names of both projects instead of syncing")
+	syncFieldsCmd.Flags().StringVar(&syncMilestone, "sync-milestone", "", "Set the milestone of each issue to the value of this source field (shorthand for --field-mapping 'FIELD=Milestone')")
+	syncFieldsCmd.Flags().StringVar(&syncLabels, "sync-labels", "", "Add the value of this source field as a label to each issue (shorthand for --field-mapping 'FIELD=Labels')")
+	syncFieldsCmd.Flags().BoolVar(&strictMappings, "strict-mappings", true, "Fail if a field mapping names a field missing in the source or target project (use --strict-mappings=false to only warn)")
+	syncFieldsCmd.Flags().StringArrayVar(&repos, "repo", nil, "Only sync issues of this repository, given as owner/name (can be specified multiple times)")
+	syncFieldsCmd.Flags().StringArrayVar(&filterLabels, "filter-label", nil, "Only sync issues carrying this label (can be specified multiple times)")
+	syncFieldsCmd.Flags().StringVar(&labelMatch, "label-match", sync_fields.LabelMatchAll, "Whether issues must carry all or any of the --filter-label labels (all or any)")
+	syncFieldsCmd.Flags().StringVar(&issueState, "issue-state", sync_fields.IssueStateAll, "Only sync issues in this state (open, closed or all)")
+	syncFieldsCmd.Flags().IntVar(&maxIssues, "max-issues", 0, "Only sync the first N issues after filtering, to try out mappings on a large project (0 for no limit)")
+	syncFieldsCmd.Flags().BoolVar(&requireValue, "require-source-value", false, "Fail issues without a value in a mapped source field instead of skipping the field")
+	syncFieldsCmd.Flags().BoolVar(&onlyFillEmpty, "only-fill-empty", false, "Only write target fields without a value, never overwriting existing target values")
+	syncFieldsCmd.Flags().BoolVar(&addMissing, "add-missing-to-target", false, "Add issues of the source project missing in the target project to it and sync their fields")
+	syncFieldsCmd.Flags().BoolVar(&reportOrphans, "report-orphans", false, "Report the issues that are in only one of the projects")
+	syncFieldsCmd.Flags().BoolVar(&failOnOrphans, "fail-on-orphans", false, "Fail after syncing if any issue is in only one of the projects (implies --report-orphans)")
+	syncFieldsCmd.Flags().StringVar(&since, "since", "", "Only sync issues updated within this duration (e.g., 24h or 7d) or since this date (e.g., 2024-01-01)")
+	syncFieldsCmd.Flags().StringVar(&metricsFile, "metrics-file", "", "Write metrics of the sync in the Prometheus text format to this file, even if the sync fails")
+	syncFieldsCmd.Flags().StringVar(&summaryJSON, "summary-json", "", "Write a JSON summary of the sync to this file, even if the sync fails")
+	syncFieldsCmd.Flags().BoolVar(&exitCode, "exit-code", false, "With --dry-run, exit with code 2 if any field would change and 0 if everything is in sync")
+	syncFieldsCmd.Flags().StringVar(&dryRunReport, "dry-run-report", "", "Print all planned changes at the end of a dry run (text or json)")
+}
+
+// loadSyncFieldsConfig fills flags not given on the command line from the config file given
+// by --config, or else from the repository-local config file, then checks that all required
+// flags are set
+func loadSyncFieldsConfig(cmd *cobra.Command, args []string) error {
+	path := configFile
+	if path == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		path, err = config.FindRepoFile(wd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if path != "" {
+		slog.Debug("loading config file", "path", path)
+		values, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+		if err := config.Apply(cmd.Flags(), values); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+
+	required := []string{"source-project", "target-project", "field-mapping"}
+	if mappingFromDiff || cmd.Flags().Changed("sync-milestone") || cmd.Flags().Changed("sync-labels") || cmd.Flags().Changed("field-mapping-file") {
+		required = []string{"source-project", "target-project"}
+	}
+
+	var missing []string
+	for _, name := range required {
+		if !cmd.Flags().Changed(name) {
+			missing = append(missing, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required flag(s) %s not set", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// projectFlagAliases keeps the former --source and --target flags working as aliases of
+// --source-project and --target-project, on the command line and in the config file
+func projectFlagAliases(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	switch name {
+	case "source":
+		name = "source-project"
+	case "target":
+		name = "target-project"
+	}
+	return pflag.NormalizedName(name)
+}
+
+// withTimeout bounds the context of a command by --timeout and reports an exceeded
+// deadline as a timeout rather than as a context error
+func withTimeout(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if timeout <= 0 {
+			return run(cmd, args)
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel()
+		cmd.SetContext(ctx)
+
+		err := run(cmd, args)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("operation timed out after %s: %w", timeout, err)
+		}
+		return err
+	}
+}
+
+// defaultGitHubHost returns the GitHub host from the GITHUB_HOST environment variable, or github.com
+func defaultGitHubHost() string {
+	if host := os.Getenv("GITHUB_HOST"); host != "" {
+		return host
+	}
+	return github.DefaultHost
+}
 
-	// Mark required flags
-	requiredFlags := []string{"source", "target", "field-mapping"}
-	for _, flag := range requiredFlags {
-		if err := syncFieldsCmd.MarkFlagRequired(flag); err != nil {
-			panic(fmt.Sprintf("failed to mark flag %s as required: %v", flag, err))
+// resolveToken returns the GitHub token from --token-file, falling back to the environment
+func resolveToken() (string, error) {
+	if tokenFile != "" {
+		return client.TokenFromFile(tokenFile)
+	}
+
+	token, err := client.TokenFromEnvironment(githubHost)
+	if err != nil {
+		return "", fmt.Errorf("%w (or use --token-file to read the token from a file)", err)
+	}
+	return token, nil
+}
+
+// resolveTokenSource returns the installation tokens of the GitHub App given by --app-id,
+// or else the token resolved by resolveToken
+func resolveTokenSource() (oauth2.TokenSource, error) {
+	if appID == 0 && installationID == 0 && privateKeyFile == "" {
+		token, err := resolveToken()
+		if err != nil {
+			return nil, err
 		}
+		return client.StaticToken(token)
 	}
+
+	if appID == 0 || installationID == 0 || privateKeyFile == "" {
+		return nil, fmt.Errorf("GitHub App authentication requires --app-id, --installation-id and --private-key-file")
+	}
+	if tokenFile != "" {
+		return nil, fmt.Errorf("--token-file cannot be combined with GitHub App authentication")
+	}
+
+	privateKey, err := os.ReadFile(privateKeyFile) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+	return client.AppTokenSource(client.AppConfig{
+		AppID:          appID,
+		InstallationID: installationID,
+		PrivateKey:     privateKey,
+		Host:           githubHost,
+	})
+}
+
+// newClient creates a GitHub client configured from the global flags. The returned function
+// closes the journal given by --journal and must be called once the command is done.
+func newClient() (*client.GraphQLClient, func(), error) {
+	tokens, err := resolveTokenSource()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var journal *client.Journal
+	closeJournal := func() {}
+	if journalPath != "" {
+		f, err := os.OpenFile(journalPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- path is provided by the user
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open journal: %w", err)
+		}
+		journal = client.NewJournal(f)
+		closeJournal = func() {
+			if err := f.Close(); err != nil {
+				slog.Error("failed to close journal", "path", journalPath, "error", err)
+			}
+		}
+	}
+
+	c, err := client.NewGraphQLClient(tokens, client.Options{
+		Verbose: verboseLevel >= 2,
+		Retry: client.RetryConfig{
+			MaxRetries: maxRetries,
+			BaseDelay:  retryBaseDelay,
+		},
+		RespectRateLimit:     respectRateLimit,
+		MinRequestInterval:   minRequestInterval,
+		LogCost:              logCost,
+		MaxAPICalls:          maxAPICalls,
+		PageSize:             pageSize,
+		SourcePageSize:       sourcePageSize,
+		TargetPageSize:       targetPageSize,
+		NoCache:              noCache,
+		ServerFilter:         serverFilter,
+		CreateMissingOptions: createOptions,
+		CreateMissingLabels:  createLabels,
+		Host:                 githubHost,
+		LogStyle:             logStyle,
+		OnDuplicate:          onDuplicate,
+		IncludeDrafts:        includeDrafts,
+		IncludePullRequests:  includePRs,
+		IncludeArchived:      includeArchived || !skipArchived,
+		Journal:              journal,
+		NormalizeSelect:      normalizeSelect,
+	})
+	if err != nil {
+		closeJournal()
+		return nil, nil, fmt.Errorf("failed to initialize GitHub client: %w", err)
+	}
+	return c, closeJournal, nil
 }
 
 func runSyncFields(cmd *cobra.Command, args []string) error {
-	client, err := client.NewGraphQLClient(verboseLevel >= 2)
+	if err := validateSyncFieldsFlags(); err != nil {
+		return err
+	}
+	updatedSince, err := loadSyncFieldsInputs()
+	if err != nil {
+		return err
+	}
+
+	if includeDrafts {
+		slog.Warn("draft issues are matched by title, drafts with changed or repeated titles may be matched with the wrong item")
+	}
+
+	client, closeClient, err := newClient()
 	if err != nil {
-		return fmt.Errorf("failed to initialize GitHub client: %w", err)
+		return err
 	}
+	defer closeClient()
 
-	service := sync_fields.NewService(client, dryRun)
+	if mappingFromDiff {
+		return suggestFieldMappings(cmd.Context(), cmd.OutOrStdout(), sync_fields.NewService(client, sync_fields.Options{}))
+	}
 
-	if len(issues) == 0 && !autoDetectIssues {
+	if len(issues) == 0 && !autoDetectIssues.enabled {
 		return fmt.Errorf("no issues specified and --auto-detect-issues not enabled")
 	}
+	if len(issues) > 0 && autoDetectIssues.filter != "" {
+		return fmt.Errorf("--auto-detect-issues with a filter cannot be combined with --issue or --issues-file")
+	}
 
-	if err := service.SyncFields(context.Background(), sourceProjectURL, targetProjectURL, issues, fieldMappings); err != nil {
+	start := time.Now()
+	service, err := toolkit.Sync(cmd.Context(), toolkit.Options{
+		Client:            client,
+		SourceProjectURL:  sourceProjectURL,
+		TargetProjectURLs: targetProjectURLs,
+		Issues:            issues,
+		FieldMappings:     syncFieldMappings(),
+		DryRun:            dryRun,
+		Sync:              syncFieldsOptions(updatedSince),
+	})
+	duration := time.Since(start)
+	if service == nil {
 		return fmt.Errorf("failed to sync fields: %w", err)
 	}
 
-	if dryRun {
+	// Report the outcome before handling errors, so that partial runs can be inspected
+	if reportErr := reportSyncRun(cmd.OutOrStdout(), client, service, duration); reportErr != nil {
+		return reportErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sync fields: %w", err)
+	}
+	return finishSyncRun(cmd.OutOrStdout(), service)
+}
+
+// validateSyncFieldsFlags checks the flags of sync-fields for invalid values and
+// combinations before anything is loaded
+func validateSyncFieldsFlags() error {
+	if err := validateSyncOutputFlags(); err != nil {
+		return err
+	}
+	if exitCode && !dryRun && !preview {
+		return fmt.Errorf("--exit-code requires --dry-run")
+	}
+	if pruneTargetItems && serverFilter != "" {
+		return fmt.Errorf("--prune-target-items cannot be combined with --server-filter, as it would remove the target items of all issues outside the filter")
+	}
+	if pruneTargetItems && !dryRun && !preview && !confirmPrune {
+		return fmt.Errorf("--prune-target-items deletes items from the target project, pass --confirm-prune to proceed or --dry-run to preview")
+	}
+
+	if maxIssues < 0 {
+		return fmt.Errorf("invalid --max-issues %d, must not be negative", maxIssues)
+	}
+
+	// Validate the project URLs before authenticating, so that typos are reported right away
+	if _, err := util.ParseProjectURL(sourceProjectURL, githubHost); err != nil {
+		return fmt.Errorf("invalid source project URL: %w", err)
+	}
+	for _, targetProjectURL := range targetProjectURLs {
+		if _, err := util.ParseProjectURL(targetProjectURL, githubHost); err != nil {
+			return fmt.Errorf("invalid target project URL: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateSyncOutputFlags checks the flags selecting what sync-fields prints
+func validateSyncOutputFlags() error {
+	switch dryRunReport {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("invalid dry run report format %q (expected text or json)", dryRunReport)
+	}
+	if dryRunReport != "" && !dryRun {
+		return fmt.Errorf("--dry-run-report requires --dry-run")
+	}
+	if len(targetProjectURLs) > 1 && (preview || dryRunReport != "" || mappingFromDiff) {
+		return fmt.Errorf("--preview, --dry-run-report and --mapping-from-diff cannot be combined with several target projects")
+	}
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+	if outputFormat == outputJSON && (dryRunReport != "" || mappingFromDiff) {
+		return fmt.Errorf("--output json cannot be combined with --dry-run-report or --mapping-from-diff")
+	}
+	return nil
+}
+
+// loadSyncFieldsInputs parses --since and adds the issues and field mappings read from
+// --issues-file and --field-mapping-file to those given on the command line
+func loadSyncFieldsInputs() (time.Time, error) {
+	var updatedSince time.Time
+	if since != "" {
+		var err error
+		if updatedSince, err = sync_fields.ParseSince(since, time.Now()); err != nil {
+			return time.Time{}, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+
+	if issuesFile != "" {
+		fileIssues, err := util.ReadIssuesFile(issuesFile, githubHost)
+		if err != nil {
+			return time.Time{}, err
+		}
+		issues = append(issues, fileIssues...)
+	}
+
+	if mappingFile != "" {
+		fileMappings, err := sync_fields.ReadFieldMappingsFile(mappingFile)
+		if err != nil {
+			return time.Time{}, err
+		}
+		fieldMappings = append(fieldMappings, fileMappings...)
+	}
+	return updatedSince, nil
+}
+
+// syncFieldMappings returns the field mappings to sync, including those given by the
+// --sync-milestone and --sync-labels shorthands
+func syncFieldMappings() []string {
+	mappings := fieldMappings
+	if syncMilestone != "" {
+		mappings = append(mappings, syncMilestone+"=Milestone")
+	}
+	if syncLabels != "" {
+		mappings = append(mappings, syncLabels+"=Labels")
+	}
+	return mappings
+}
+
+// syncFieldsOptions returns the options of the sync service configured from the flags
+func syncFieldsOptions(updatedSince time.Time) sync_fields.Options {
+	return sync_fields.Options{
+		Preview:              preview,
+		Concurrency:          concurrency,
+		FailFast:             failFast,
+		AllowSameProject:     allowSameProject,
+		PruneTargetItems:     pruneTargetItems,
+		CreateMissingOptions: createOptions,
+		LenientMappings:      !strictMappings,
+		Repos:                repos,
+		FilterLabels:         filterLabels,
+		LabelMatch:           labelMatch,
+		IssueState:           issueState,
+		IssueFilter:          autoDetectIssues.filter,
+		Since:                updatedSince,
+		MaxIssues:            maxIssues,
+		RequireSourceValue:   requireValue,
+		AddMissingToTarget:   addMissing,
+		CreateMissingFields:  createFields,
+		OnlyFillEmpty:        onlyFillEmpty,
+		ReportOrphans:        reportOrphans,
+		FailOnOrphans:        failOnOrphans,
+		NormalizeSelect:      normalizeSelect,
+		ProgressBar:          progressBarWriter(),
+	}
+}
+
+// reportSyncRun writes the summary, metrics and JSON report of a sync run and logs its
+// outcome. It is called before handling sync errors, as the report includes failed issues.
+func reportSyncRun(w io.Writer, c *client.GraphQLClient, service *sync_fields.Service, duration time.Duration) error {
+	if summaryJSON != "" {
+		if err := writeSummaryFile(summaryJSON, service.Summary()); err != nil {
+			slog.Error("failed to write summary", "path", summaryJSON, "error", err)
+		}
+	}
+
+	if metricsFile != "" {
+		metrics := sync_fields.Metrics{
+			Summary:  service.Summary(),
+			APICalls: c.APICallCount(),
+			Duration: duration,
+		}
+		if err := writeFileAtomic(metricsFile, metrics.WritePrometheus); err != nil {
+			slog.Error("failed to write metrics", "path", metricsFile, "error", err)
+		}
+	}
+
+	logSyncSummary(service.Summary(), duration)
+
+	if outputFormat == outputJSON {
+		if err := writeJSON(w, service.Report()); err != nil {
+			return fmt.Errorf("failed to write sync report: %w", err)
+		}
+	}
+
+	if logCost {
+		slog.Info("GraphQL query cost of the sync",
+			"total_cost", c.QueryCost(),
+			"api_calls", c.APICallCount(),
+		)
+	}
+
+	rateLimit := c.RateLimitStatus()
+	slog.Debug("GitHub rate limit after sync",
+		"remaining", rateLimit.Remaining,
+		"limit", rateLimit.Limit,
+		"reset_at", rateLimit.ResetAt,
+	)
+	return nil
+}
+
+// finishSyncRun prints the preview or dry run report of a successful sync run and decides
+// its exit code
+func finishSyncRun(w io.Writer, service *sync_fields.Service) error {
+	if preview && outputFormat == outputText {
+		if err := writePreview(w, service.Report()); err != nil {
+			return fmt.Errorf("failed to write preview: %w", err)
+		}
+	}
+
+	if dryRunReport != "" {
+		if err := writeDryRunReport(w, dryRunReport, service.Result()); err != nil {
+			return fmt.Errorf("failed to write dry run report: %w", err)
+		}
+	}
+
+	if preview {
+		slog.Info("preview completed successfully")
+	} else if dryRun {
 		slog.Info("dry run completed successfully")
 	} else {
 		slog.Info("sync completed successfully")
 	}
+
+	if exitCode && hasChanges(service) {
+		return &exitCodeError{code: exitCodePendingChanges, msg: "dry run found pending changes"}
+	}
+	return nil
+}
+
+// logSyncSummary logs the counters of a sync run in a single line
+func logSyncSummary(summary sync_fields.Summary, duration time.Duration) {
+	slog.Info("sync summary",
+		"dry_run", summary.DryRun,
+		"issues_processed", summary.IssuesProcessed,
+		"fields_updated", summary.FieldsUpdated,
+		"fields_skipped", summary.FieldsSkipped,
+		"fields_kept", summary.FieldsKept,
+		"fields_cleared", summary.FieldsCleared,
+		"errors", len(summary.Errors),
+		"duration", duration.Round(time.Millisecond),
+	)
+}
+
+// hasChanges reports whether the sync changed any target project, or would have in dry run mode
+func hasChanges(service *sync_fields.Service) bool {
+	targets := service.TargetResults()
+	if len(targets) == 0 {
+		return service.Result().HasChanges()
+	}
+	for _, target := range targets {
+		if target.Result.HasChanges() {
+			return true
+		}
+	}
+	return false
+}
+
+// progressBarWriter returns stderr if it is a terminal to render a progress bar on, and nil
+// otherwise so that the progress is logged
+func progressBarWriter() io.Writer {
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return os.Stderr
+}
+
+// writeSummaryFile writes the summary of a sync as JSON to the given file
+func writeSummaryFile(path string, summary sync_fields.Summary) error {
+	f, err := os.Create(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(f, summary); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeFileAtomic writes a file through a temporary file in the same directory, which is
+// renamed over the file once complete, so that readers never see a partial file
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// Temporary files are only readable by the owner, but metrics are read by other processes
+	if err := os.Chmod(f.Name(), 0o644); err != nil { // #nosec G302 -- the file holds no secrets
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// suggestFieldMappings prints suggested field mappings in the format of the repository config file
+func suggestFieldMappings(ctx context.Context, w io.Writer, service *sync_fields.Service) error {
+	suggestions, err := service.SuggestFieldMappings(ctx, sourceProjectURL, targetProjectURLs[0])
+	if err != nil {
+		return fmt.Errorf("failed to suggest field mappings: %w", err)
+	}
+
+	if len(suggestions) == 0 {
+		_, err := fmt.Fprintln(w, "# No similar date, single select or assignees fields found.")
+		return err
+	}
+
+	fmt.Fprintln(w, "# Suggested from similar field names, review before use")
+	fmt.Fprintln(w, "field-mapping:")
+	for _, suggestion := range suggestions {
+		fmt.Fprintf(w, "  - %s=%s # similarity %.2f\n", suggestion.SourceField, suggestion.TargetField, suggestion.Similarity)
+	}
+	return nil
+}
+
+// writePreview prints the current and new value of every mapped field, and whether it
+// would change, as a table grouped by issue
+func writePreview(w io.Writer, report sync_fields.Report) error {
+	tw := newTableWriter(w)
+	fmt.Fprintln(tw, "ISSUE\tFIELD\tCURRENT\tNEW\tCHANGE")
+	for _, issue := range report.Issues {
+		for _, field := range issue.Fields {
+			change := "no"
+			if field.Action == sync_fields.ActionUpdated {
+				change = "yes"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", issue.IssueURL, field.Field, field.OldValue, field.NewValue, change)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeDryRunReport prints the planned changes of a dry run in the given format
+func writeDryRunReport(w io.Writer, format string, result sync_fields.Result) error {
+	changes := result.Changes
+	if changes == nil {
+		changes = []sync_fields.FieldChange{}
+	}
+	if format == "json" {
+		return writeJSON(w, changes)
+	}
+
+	if len(changes) == 0 {
+		if _, err := fmt.Fprintln(w, "No changes planned."); err != nil {
+			return err
+		}
+	} else {
+		tw := newTableWriter(w)
+		fmt.Fprintln(tw, "ISSUE\tTITLE\tFIELD\tOLD\tNEW")
+		for _, change := range changes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", change.IssueURL, change.Title, change.Field, change.OldValue, change.NewValue)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(result.PrunedIssues) > 0 {
+		fmt.Fprintf(w, "\nItems to prune from the target project (%d):\n", len(result.PrunedIssues))
+		for _, issueURL := range result.PrunedIssues {
+			fmt.Fprintf(w, "  %s\n", issueURL)
+		}
+	}
 	return nil
 }
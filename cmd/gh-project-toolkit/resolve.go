@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/tools/resolve"
+)
+
+var resolveCmd = &cobra.Command{
+	Use:          "resolve",
+	Short:        "Print the node IDs of GitHub projects",
+	SilenceUsage: true,
+	RunE:         withTimeout(runResolve),
+}
+
+var (
+	resolveProjectURLs []string
+	resolveFields      bool
+)
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+
+	resolveCmd.Flags().StringArrayVar(&resolveProjectURLs, "project", nil, "Project URL (can be specified multiple times)")
+	resolveCmd.Flags().BoolVar(&resolveFields, "fields", false, "Also print the node IDs of the project fields")
+
+	if err := resolveCmd.MarkFlagRequired("project"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag project as required: %v", err))
+	}
+}
+
+func runResolve(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+
+	client, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	service := resolve.NewService(client)
+
+	projects, err := service.Resolve(cmd.Context(), resolveProjectURLs, resolveFields)
+	if err != nil {
+		return fmt.Errorf("failed to resolve projects: %w", err)
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), projects)
+	}
+
+	w := newTableWriter(cmd.OutOrStdout())
+	for _, project := range projects {
+		fmt.Fprintf(w, "%s\t%s\n", project.URL, project.ID)
+		for _, field := range project.Fields {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", field.Name, field.ID, field.Type)
+		}
+	}
+	return w.Flush()
+}
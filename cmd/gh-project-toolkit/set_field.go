@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/tools/set_field"
+)
+
+var setFieldCmd = &cobra.Command{
+	Use:          "set-field",
+	Short:        "Set a field of an issue in a GitHub project to a value",
+	SilenceUsage: true,
+	RunE:         withTimeout(runSetField),
+}
+
+var (
+	setFieldProjectURL string
+	setFieldIssueURL   string
+	setFieldName       string
+	setFieldValue      string
+	setFieldDryRun     bool
+)
+
+func init() {
+	rootCmd.AddCommand(setFieldCmd)
+
+	setFieldCmd.Flags().StringVar(&setFieldProjectURL, "project", "", "Project URL (e.g., https://github.com/orgs/org/projects/123)")
+	setFieldCmd.Flags().StringVar(&setFieldIssueURL, "issue", "", "GitHub issue URL")
+	setFieldCmd.Flags().StringVar(&setFieldName, "field", "", "Name of the field to set")
+	setFieldCmd.Flags().StringVar(&setFieldValue, "value", "", "Value to set: a date as YYYY-MM-DD, a number, a single select option, comma-separated logins, a milestone title or an iteration title, @current or @next")
+	setFieldCmd.Flags().BoolVar(&setFieldDryRun, "dry-run", false, "Run in dry run mode (no mutations will be performed)")
+
+	for _, name := range []string{"project", "issue", "field", "value"} {
+		if err := setFieldCmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark flag %s as required: %v", name, err))
+		}
+	}
+}
+
+func runSetField(cmd *cobra.Command, args []string) error {
+	client, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	service := set_field.NewService(client, setFieldDryRun)
+	if err := service.SetField(cmd.Context(), setFieldProjectURL, setFieldIssueURL, setFieldName, setFieldValue); err != nil {
+		return fmt.Errorf("failed to set field: %w", err)
+	}
+
+	if setFieldDryRun {
+		slog.Info("dry run completed successfully")
+	} else {
+		slog.Info("field set successfully", "field", setFieldName, "issue", setFieldIssueURL)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/tools/undo"
+)
+
+var undoCmd = &cobra.Command{
+	Use:          "undo JOURNAL",
+	Short:        "Restore the field values recorded in a --journal file",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         withTimeout(runUndo),
+}
+
+var undoDryRun bool
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().BoolVar(&undoDryRun, "dry-run", false, "Run in dry run mode (no mutations will be performed)")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0]) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	entries, err := client.ReadJournal(f)
+	if err != nil {
+		return fmt.Errorf("invalid journal %s: %w", args[0], err)
+	}
+	if len(entries) == 0 {
+		slog.Info("journal is empty, nothing to undo", "path", args[0])
+		return nil
+	}
+
+	c, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	service := undo.NewService(c, undoDryRun)
+	result, err := service.Undo(cmd.Context(), entries)
+	if err != nil {
+		return fmt.Errorf("failed to undo changes: %w", err)
+	}
+
+	slog.Info("undo completed",
+		"dry_run", undoDryRun,
+		"reverted", len(result.Reverted),
+		"skipped", len(result.Skipped),
+	)
+	if len(result.Skipped) > 0 {
+		return fmt.Errorf("%d changes were not undone, see the warnings above", len(result.Skipped))
+	}
+	return nil
+}
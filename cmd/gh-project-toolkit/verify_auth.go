@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+var verifyAuthCmd = &cobra.Command{
+	Use:          "verify-auth",
+	Short:        "Check that the GitHub token can access projects",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         withTimeout(runVerifyAuth),
+}
+
+func init() {
+	rootCmd.AddCommand(verifyAuthCmd)
+}
+
+func runVerifyAuth(cmd *cobra.Command, args []string) error {
+	c, closeClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	status, err := c.VerifyAuth(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Authenticated as %s on %s\n", status.Login, c.Host())
+	if !status.ScopesKnown() {
+		fmt.Fprintln(w, "Scopes: not reported, as for fine-grained and GitHub App tokens. Make sure the token has read and write access to projects.")
+		return nil
+	}
+
+	scopes := strings.Join(status.Scopes, ", ")
+	if scopes == "" {
+		scopes = "none"
+	}
+	fmt.Fprintf(w, "Scopes: %s\n", scopes)
+	for _, scope := range []string{client.ScopeProject, client.ScopeReadProject} {
+		state := "missing"
+		if status.HasScope(scope) {
+			state = "present"
+		}
+		fmt.Fprintf(w, "  %s: %s\n", scope, state)
+	}
+
+	switch {
+	case status.HasScope(client.ScopeProject):
+		return nil
+	case status.HasScope(client.ScopeReadProject):
+		fmt.Fprintf(w, "The token can only read projects, add the %s scope to update fields.\n", client.ScopeProject)
+		return nil
+	default:
+		return fmt.Errorf("the token lacks the %s scope, add it with 'gh auth refresh -s %s' or create a token with this scope", client.ScopeProject, client.ScopeProject)
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// RepoFileName is the name of the repository-local config file
+const RepoFileName = ".gh-project-toolkit.yaml"
+
+// Values maps flag names to their configured values
+type Values map[string]interface{}
+
+// FindRepoFile walks up from dir to the enclosing git repository root looking for
+// the repository-local config file. It returns an empty path if none was found.
+func FindRepoFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve directory: %w", err)
+	}
+
+	for {
+		path := filepath.Join(dir, RepoFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to check %s: %w", path, err)
+		}
+
+		// Stop at the repository root, like git does
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads a YAML config file whose keys are flag names
+func Load(path string) (Values, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a config file location
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var values Values
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// Apply sets every flag in values that was not explicitly set on the command line,
+// so that flags always take precedence over config files. Unknown keys and values that
+// do not fit their flag, such as lists for single value flags, are reported as errors.
+func Apply(flags *pflag.FlagSet, values Values) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var unknown []string
+	for _, name := range names {
+		if flags.Lookup(name) == nil {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(unknown) == 1 {
+		return fmt.Errorf("unknown config key %s", unknown[0])
+	}
+	if len(unknown) > 1 {
+		return fmt.Errorf("unknown config keys %s", strings.Join(unknown, ", "))
+	}
+
+	for _, name := range names {
+		flag := flags.Lookup(name)
+		if flag.Changed {
+			continue
+		}
+
+		items, err := flagItems(flag, values[name])
+		if err != nil {
+			return fmt.Errorf("invalid value for config key %q: %w", name, err)
+		}
+		for _, item := range items {
+			if err := flags.Set(name, flagValue(item)); err != nil {
+				return fmt.Errorf("invalid value for config key %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// flagItems returns the scalar values to set a flag to. Only flags that can be given more
+// than once accept lists.
+func flagItems(flag *pflag.Flag, value interface{}) ([]interface{}, error) {
+	items, isList := value.([]interface{})
+	if !isList {
+		items = []interface{}{value}
+	} else if !isListFlag(flag) {
+		return nil, fmt.Errorf("expected a single value, got a list")
+	}
+
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("expected a string, number or boolean, got %s", describe(item))
+		case nil:
+			return nil, fmt.Errorf("expected a value")
+		}
+	}
+	return items, nil
+}
+
+// flagValue formats a scalar YAML value as a flag value. Unquoted dates such as
+// 2024-01-01 are decoded as timestamps and are formatted back as dates, unless they
+// have a time of day.
+func flagValue(item interface{}) string {
+	if t, ok := item.(time.Time); ok {
+		if t.Equal(t.Truncate(24 * time.Hour)) {
+			return t.Format(time.DateOnly)
+		}
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(item)
+}
+
+// isListFlag checks if a flag can be given more than once, such as --field-mapping
+func isListFlag(flag *pflag.Flag) bool {
+	typ := flag.Value.Type()
+	return strings.HasSuffix(typ, "Slice") || strings.HasSuffix(typ, "Array")
+}
+
+// describe names the YAML type of a nested value for error messages
+func describe(value interface{}) string {
+	if _, ok := value.([]interface{}); ok {
+		return "a list"
+	}
+	return "a mapping"
+}
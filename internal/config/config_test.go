@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRepoFile(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	nested := filepath.Join(repo, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0o750))
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o750))
+
+	t.Run("not found", func(t *testing.T) {
+		path, err := FindRepoFile(nested)
+		assert.NoError(t, err)
+		assert.Empty(t, path)
+	})
+
+	t.Run("found in repository root", func(t *testing.T) {
+		want := filepath.Join(repo, RepoFileName)
+		require.NoError(t, os.WriteFile(want, []byte("dry-run: true\n"), 0o600))
+		t.Cleanup(func() { _ = os.Remove(want) })
+
+		path, err := FindRepoFile(nested)
+		assert.NoError(t, err)
+		assert.Equal(t, want, path)
+	})
+
+	t.Run("does not look above the repository root", func(t *testing.T) {
+		outside := filepath.Join(root, RepoFileName)
+		require.NoError(t, os.WriteFile(outside, []byte("dry-run: true\n"), 0o600))
+		t.Cleanup(func() { _ = os.Remove(outside) })
+
+		path, err := FindRepoFile(nested)
+		assert.NoError(t, err)
+		assert.Empty(t, path)
+	})
+}
+
+func TestApply(t *testing.T) {
+	newFlags := func() *pflag.FlagSet {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("source", "", "")
+		flags.StringArray("field-mapping", nil, "")
+		flags.Bool("dry-run", false, "")
+		return flags
+	}
+
+	path := filepath.Join(t.TempDir(), RepoFileName)
+	require.NoError(t, os.WriteFile(path, []byte(`source: https://github.com/orgs/myorg/projects/1
+field-mapping:
+  - start=Start date
+  - end=End date
+dry-run: true
+`), 0o600))
+
+	values, err := Load(path)
+	require.NoError(t, err)
+
+	t.Run("fills unset flags", func(t *testing.T) {
+		flags := newFlags()
+		require.NoError(t, Apply(flags, values))
+
+		source, _ := flags.GetString("source")
+		mappings, _ := flags.GetStringArray("field-mapping")
+		dryRun, _ := flags.GetBool("dry-run")
+		assert.Equal(t, "https://github.com/orgs/myorg/projects/1", source)
+		assert.Equal(t, []string{"start=Start date", "end=End date"}, mappings)
+		assert.True(t, dryRun)
+	})
+
+	t.Run("explicit flags take precedence", func(t *testing.T) {
+		flags := newFlags()
+		require.NoError(t, flags.Parse([]string{"--source", "https://github.com/orgs/other/projects/2", "--field-mapping", "a=b"}))
+		require.NoError(t, Apply(flags, values))
+
+		source, _ := flags.GetString("source")
+		mappings, _ := flags.GetStringArray("field-mapping")
+		assert.Equal(t, "https://github.com/orgs/other/projects/2", source)
+		assert.Equal(t, []string{"a=b"}, mappings)
+	})
+
+	t.Run("unknown keys are rejected", func(t *testing.T) {
+		err := Apply(newFlags(), Values{"sorce": "typo"})
+		assert.ErrorContains(t, err, `unknown config key "sorce"`)
+	})
+
+	t.Run("all unknown keys are reported", func(t *testing.T) {
+		err := Apply(newFlags(), Values{"sorce": "typo", "dry-rn": true, "source": "x"})
+		assert.EqualError(t, err, `unknown config keys "dry-rn", "sorce"`)
+	})
+
+	t.Run("lists are only accepted for repeatable flags", func(t *testing.T) {
+		err := Apply(newFlags(), Values{"source": []interface{}{"a", "b"}})
+		assert.EqualError(t, err, `invalid value for config key "source": expected a single value, got a list`)
+	})
+
+	t.Run("unquoted dates are kept as dates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), RepoFileName)
+		require.NoError(t, os.WriteFile(path, []byte("since: 2024-01-01\n"), 0o600))
+		values, err := Load(path)
+		require.NoError(t, err)
+
+		flags := newFlags()
+		flags.String("since", "", "")
+		require.NoError(t, Apply(flags, values))
+
+		since, _ := flags.GetString("since")
+		assert.Equal(t, "2024-01-01", since)
+	})
+
+	t.Run("nested values are rejected", func(t *testing.T) {
+		err := Apply(newFlags(), Values{"field-mapping": []interface{}{map[string]interface{}{"start": "Start"}}})
+		assert.EqualError(t, err, `invalid value for config key "field-mapping": expected a string, number or boolean, got a mapping`)
+	})
+}
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// appTokenRefreshMargin is how long before its expiry an installation token is replaced
+const appTokenRefreshMargin = 5 * time.Minute
+
+// AppConfig identifies the installation of a GitHub App to authenticate as
+type AppConfig struct {
+	// AppID is the ID of the GitHub App
+	AppID int64
+	// InstallationID is the ID of the installation of the App in the organization or account
+	InstallationID int64
+	// PrivateKey is a PEM encoded private key of the App
+	PrivateKey []byte
+	// Host is the GitHub Enterprise Server host, defaulting to github.com
+	Host string
+	// Transport sends the requests minting installation tokens, defaulting to
+	// http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// StaticToken returns a token source for a personal access token or another fixed token
+func StaticToken(token string) (oauth2.TokenSource, error) {
+	if token == "" {
+		return nil, fmt.Errorf("GitHub token is empty")
+	}
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+}
+
+// AppTokenSource returns a token source minting installation access tokens of a GitHub App.
+// Tokens are reused until shortly before they expire, and then replaced by a new one.
+func AppTokenSource(cfg AppConfig) (oauth2.TokenSource, error) {
+	if cfg.AppID <= 0 {
+		return nil, fmt.Errorf("invalid GitHub App ID %d", cfg.AppID)
+	}
+	if cfg.InstallationID <= 0 {
+		return nil, fmt.Errorf("invalid GitHub App installation ID %d", cfg.InstallationID)
+	}
+
+	key, err := parsePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	host := cfg.Host
+	if host == "" {
+		host = github.DefaultHost
+	}
+
+	src := &appTokenSource{
+		appID:          cfg.AppID,
+		installationID: cfg.InstallationID,
+		key:            key,
+		apiURL:         restAPIURL(host),
+		client:         &http.Client{Transport: cfg.Transport},
+		now:            time.Now,
+	}
+	return oauth2.ReuseTokenSourceWithExpiry(nil, src, appTokenRefreshMargin), nil
+}
+
+// restAPIURL returns the REST API endpoint of a GitHub host
+func restAPIURL(host string) string {
+	if host == github.DefaultHost {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// parsePrivateKey parses a PEM encoded RSA private key, as downloaded from the settings of
+// a GitHub App (PKCS #1) or converted to PKCS #8
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid GitHub App private key: not an RSA key")
+	}
+	return key, nil
+}
+
+// appTokenSource mints a new installation access token on every call
+type appTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	apiURL         string
+	client         *http.Client
+	now            func() time.Time
+}
+
+// Token implements oauth2.TokenSource
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.signJWT()
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.apiURL, s.installationID)
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub App installation token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App installation token: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create GitHub App installation token: %s: %s", resp.Status, body)
+	}
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App installation token: %w", err)
+	}
+	if token.Token == "" {
+		return nil, fmt.Errorf("GitHub App installation token is empty")
+	}
+	return &oauth2.Token{AccessToken: token.Token, TokenType: "token", Expiry: token.ExpiresAt}, nil
+}
+
+// signJWT creates the JSON Web Token the App authenticates with to mint installation
+// tokens. It is backdated by a minute to allow for clock drift, and valid for 9 minutes,
+// below the maximum of 10 minutes.
+func (s *appTokenSource) signJWT() (string, error) {
+	now := s.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestAppTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Each token expires after the given duration
+	var minted int
+	var lifetime time.Duration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/app/installations/42/access_tokens", r.URL.Path)
+
+		// The JWT is signed with the private key of the App
+		jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		require.True(t, ok)
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{"iat":%d,"exp":%d,"iss":"7"}`, now.Add(-time.Minute).Unix(), now.Add(9*time.Minute).Unix()), string(claims))
+
+		minted++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":      fmt.Sprintf("ghs_%d", minted),
+			"expires_at": time.Now().Add(lifetime),
+		})
+	}))
+	defer server.Close()
+
+	src := &appTokenSource{
+		appID:          7,
+		installationID: 42,
+		key:            key,
+		apiURL:         server.URL,
+		client:         server.Client(),
+		now:            func() time.Time { return now },
+	}
+	tokens := oauth2.ReuseTokenSourceWithExpiry(nil, src, appTokenRefreshMargin)
+
+	t.Run("reuses valid tokens", func(t *testing.T) {
+		lifetime = time.Hour
+		for i := 0; i < 2; i++ {
+			token, err := tokens.Token()
+			require.NoError(t, err)
+			assert.Equal(t, "ghs_1", token.AccessToken)
+		}
+	})
+
+	t.Run("refreshes tokens before they expire", func(t *testing.T) {
+		minted = 0
+		lifetime = 2 * time.Minute
+		tokens := oauth2.ReuseTokenSourceWithExpiry(nil, src, appTokenRefreshMargin)
+
+		first, err := tokens.Token()
+		require.NoError(t, err)
+		second, err := tokens.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "ghs_1", first.AccessToken)
+		assert.Equal(t, "ghs_2", second.AccessToken)
+	})
+}
+
+func TestAppTokenSourceRejectsInvalidConfig(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	pkcs1PEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+
+	_, err = AppTokenSource(AppConfig{AppID: 7, InstallationID: 42, PrivateKey: pkcs1PEM})
+	assert.NoError(t, err)
+	_, err = AppTokenSource(AppConfig{AppID: 7, InstallationID: 42, PrivateKey: pkcs8PEM})
+	assert.NoError(t, err)
+
+	_, err = AppTokenSource(AppConfig{InstallationID: 42, PrivateKey: pkcs1PEM})
+	assert.EqualError(t, err, "invalid GitHub App ID 0")
+	_, err = AppTokenSource(AppConfig{AppID: 7, PrivateKey: pkcs1PEM})
+	assert.EqualError(t, err, "invalid GitHub App installation ID 0")
+	_, err = AppTokenSource(AppConfig{AppID: 7, InstallationID: 42, PrivateKey: []byte("not a key")})
+	assert.EqualError(t, err, "invalid GitHub App private key: no PEM data found")
+}
+
+func TestRestAPIURL(t *testing.T) {
+	assert.Equal(t, "https://api.github.com", restAPIURL("github.com"))
+	assert.Equal(t, "https://github.example.com/api/v3", restAPIURL("github.example.com"))
+}
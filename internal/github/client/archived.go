@@ -0,0 +1,41 @@
+package client
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+// archivedIssues returns the canonical URLs of the issues whose item is archived in any of the projects
+func archivedIssues(projects []*ProjectV2) map[string]bool {
+	archived := make(map[string]bool)
+	for _, project := range projects {
+		for _, item := range project.Items.Nodes {
+			if item.IsArchived && item.isIssue() {
+				archived[util.CanonicalizeIssueURL(item.Content.Issue.URL)] = true
+			}
+		}
+	}
+	return archived
+}
+
+// skipArchivedIssues removes the issues archived in the source or target project from both
+// issue lists, unless archived items are included. An issue archived in only one project is
+// removed from both, so that it is neither synced nor taken for a target-only issue.
+func (c *GraphQLClient) skipArchivedIssues(sourceProject, targetProject *ProjectV2, sourceIssues, targetIssues []string) ([]string, []string) {
+	if c.includeArchived {
+		return sourceIssues, targetIssues
+	}
+
+	archived := archivedIssues(uniqueProjects(sourceProject, targetProject))
+	if len(archived) == 0 {
+		return sourceIssues, targetIssues
+	}
+
+	isArchived := func(issueURL string) bool { return archived[util.CanonicalizeIssueURL(issueURL)] }
+	sourceIssues = slices.DeleteFunc(sourceIssues, isArchived)
+	targetIssues = slices.DeleteFunc(targetIssues, isArchived)
+	slog.Info("skipped archived project items", "count", len(archived))
+	return sourceIssues, targetIssues
+}
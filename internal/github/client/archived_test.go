@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchivedItemsAreSkipped(t *testing.T) {
+	// Issue 2 is archived in the source project, issue 3 in the target project
+	source := `{"data":{"node":{"id":"source","fields":{"nodes":[]},"items":{"nodes":[
+		{"id":"item_1","isArchived":false,"fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/1"}},
+		{"id":"item_2","isArchived":true,"fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/2"}},
+		{"id":"item_3","isArchived":false,"fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/3"}}
+	],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	target := `{"data":{"node":{"id":"target","fields":{"nodes":[]},"items":{"nodes":[
+		{"id":"item_4","isArchived":false,"fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/1"}},
+		{"id":"item_5","isArchived":false,"fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/2"}},
+		{"id":"item_6","isArchived":true,"fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/3"}}
+	],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	handler := func(req GraphQLRequest) string {
+		if req.Variables["projectID"] == "source" {
+			return source
+		}
+		return target
+	}
+
+	t.Run("skipped by default", func(t *testing.T) {
+		c := newTestClient(t, handler)
+
+		_, _, sourceIssues, targetIssues, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, sourceIssues)
+		// Issue 2 is not reported as a target-only issue, as it is in the source project
+		assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, targetIssues)
+
+		// Archived items stay available when asked for explicitly
+		_, err = c.GetProjectFieldValues(context.Background(), "source", "https://github.com/org/repo/issues/2", nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("included", func(t *testing.T) {
+		c := newTestClient(t, handler)
+		c.includeArchived = true
+
+		_, _, sourceIssues, targetIssues, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+		require.NoError(t, err)
+		assert.Len(t, sourceIssues, 3)
+		assert.Len(t, targetIssues, 3)
+	})
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// logins returns the logins of the users of a user field value
+func (v *ProjectV2ItemFieldUserValue) logins() []string {
+	logins := make([]string, 0, len(v.Users.Nodes))
+	for _, user := range v.Users.Nodes {
+		logins = append(logins, user.Login)
+	}
+	return logins
+}
+
+// setLogins replaces the users of a user field value
+func (v *ProjectV2ItemFieldUserValue) setLogins(logins []string) {
+	v.Users.Nodes = v.Users.Nodes[:0]
+	for _, login := range logins {
+		v.Users.Nodes = append(v.Users.Nodes, struct{ Login string }{Login: login})
+	}
+}
+
+// updateUserField sets the users of a user field. The assignees field of a project reflects
+// the assignees of the underlying issue, so the issue's assignees are updated instead of the item.
+func (c *GraphQLClient) updateUserField(ctx context.Context, project *ProjectV2, issueURL string, currentValue *ProjectV2ItemFieldValue, field github.ProjectField, dryRun bool) error {
+	c.mu.RLock()
+	dataType := fieldDataType(project, field)
+	issueID := c.issueNodeID(project, issueURL)
+	c.mu.RUnlock()
+
+	if dataType != "ASSIGNEES" {
+		return fmt.Errorf("field %s of project does not hold assignees, user values can only be set on the assignees field", field.Name)
+	}
+	if IsDraftIssueURL(issueURL) {
+		return fmt.Errorf("assignees of draft issue %s cannot be synced", issueURL)
+	}
+
+	var current []string
+	if currentValue != nil {
+		current = currentValue.UserValue.logins()
+	}
+	add, remove := diffLogins(current, field.Value.Users)
+
+	c.logFieldUpdate(field.Name, strings.Join(current, ", "), field.Value.String(), dryRun,
+		"project_id", project.ID,
+		"issue_id", issueID,
+	)
+
+	if dryRun {
+		return nil
+	}
+	if issueID == "" {
+		return issueNotFound(issueURL)
+	}
+
+	if len(add) > 0 {
+		if err := c.addAssignees(ctx, issueID, issueURL, add); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := c.removeAssignees(ctx, issueID, remove); err != nil {
+			return err
+		}
+	}
+
+	c.updateCacheFieldValue(project, issueURL, field)
+	return nil
+}
+
+// diffLogins returns the logins to add and to remove to turn current into want
+func diffLogins(current, want []string) (add, remove []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, login := range current {
+		currentSet[strings.ToLower(login)] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, login := range want {
+		wantSet[strings.ToLower(login)] = true
+		if !currentSet[strings.ToLower(login)] {
+			add = append(add, login)
+		}
+	}
+	for _, login := range current {
+		if !wantSet[strings.ToLower(login)] {
+			remove = append(remove, login)
+		}
+	}
+	return add, remove
+}
+
+// userIDs resolves the node IDs of the given logins
+func (c *GraphQLClient) userIDs(ctx context.Context, logins []string) ([]githubv4.ID, error) {
+	ids := make([]githubv4.ID, 0, len(logins))
+	for _, login := range logins {
+		var query struct {
+			User struct {
+				ID string
+			} `graphql:"user(login: $login)"`
+		}
+		if err := c.queryWithRetry(ctx, &query, map[string]interface{}{"login": githubv4.String(login)}); err != nil {
+			return nil, fmt.Errorf("failed to look up user %s: %w", login, err)
+		}
+		ids = append(ids, githubv4.ID(query.User.ID))
+	}
+	return ids, nil
+}
+
+// assignableAssignees is the assignees of an issue or pull request
+type assignableAssignees struct {
+	Assignees struct {
+		Nodes []struct {
+			Login string
+		}
+	} `graphql:"assignees(first: 20)"`
+}
+
+// assigneesResult is the assignees of an issue or pull request returned by the assignee mutations
+type assigneesResult struct {
+	Assignable struct {
+		Issue       assignableAssignees `graphql:"... on Issue"`
+		PullRequest assignableAssignees `graphql:"... on PullRequest"`
+	}
+}
+
+// addAssignees assigns the given users to an issue. GitHub silently ignores users that
+// cannot be assigned, so the resulting assignees are checked.
+func (c *GraphQLClient) addAssignees(ctx context.Context, issueID, issueURL string, logins []string) error {
+	ids, err := c.userIDs(ctx, logins)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		AddAssigneesToAssignable assigneesResult `graphql:"addAssigneesToAssignable(input: $input)"`
+	}
+	input := githubv4.AddAssigneesToAssignableInput{
+		AssignableID: githubv4.ID(issueID),
+		AssigneeIDs:  ids,
+	}
+	if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to add assignees: %w", err)
+	}
+
+	assigned := make(map[string]bool)
+	assignable := mutation.AddAssigneesToAssignable.Assignable
+	for _, user := range append(assignable.Issue.Assignees.Nodes, assignable.PullRequest.Assignees.Nodes...) {
+		assigned[strings.ToLower(user.Login)] = true
+	}
+	var missing []string
+	for _, login := range logins {
+		if !assigned[strings.ToLower(login)] {
+			missing = append(missing, login)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("could not assign %s to %s: only collaborators of the repository can be assigned", strings.Join(missing, ", "), issueURL)
+	}
+	return nil
+}
+
+// removeAssignees unassigns the given users from an issue
+func (c *GraphQLClient) removeAssignees(ctx context.Context, issueID string, logins []string) error {
+	ids, err := c.userIDs(ctx, logins)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		RemoveAssigneesFromAssignable assigneesResult `graphql:"removeAssigneesFromAssignable(input: $input)"`
+	}
+	input := githubv4.RemoveAssigneesFromAssignableInput{
+		AssignableID: githubv4.ID(issueID),
+		AssigneeIDs:  ids,
+	}
+	if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to remove assignees: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestDiffLogins(t *testing.T) {
+	add, remove := diffLogins([]string{"alice", "Bob"}, []string{"bob", "carol"})
+	assert.Equal(t, []string{"carol"}, add)
+	assert.Equal(t, []string{"alice"}, remove)
+}
+
+// assigneesTestClient returns a client with a cached project holding an issue assigned to
+// alice, whose addAssigneesToAssignable mutation returns the given assignees
+func assigneesTestClient(t *testing.T, assigneesAfterAdd string) (*GraphQLClient, *[]string) {
+	t.Helper()
+
+	var mutations []string
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "user(login: $login)"):
+			return `{"data":{"user":{"id":"user_` + req.Variables["login"].(string) + `"}}}`
+		case strings.Contains(req.Query, "addAssigneesToAssignable("):
+			mutations = append(mutations, "add")
+			return `{"data":{"addAssigneesToAssignable":{"assignable":{"assignees":{"nodes":` + assigneesAfterAdd + `}}}}}`
+		case strings.Contains(req.Query, "removeAssigneesFromAssignable("):
+			mutations = append(mutations, "remove")
+			return `{"data":{"removeAssigneesFromAssignable":{"assignable":{"assignees":{"nodes":[]}}}}}`
+		default:
+			return `{"data":{"node":{"id":"target","fields":{"nodes":[
+				{"__typename":"ProjectV2Field","id":"field_assignees","name":"Assignees","dataType":"ASSIGNEES"}
+			]},"items":{"nodes":[
+				{"id":"item_1","fieldValues":{"nodes":[
+					{"__typename":"ProjectV2ItemFieldUserValue","field":{"__typename":"ProjectV2Field","id":"field_assignees","name":"Assignees"},"users":{"nodes":[{"login":"alice"}]}}
+				]},"content":{"__typename":"Issue","id":"issue_1","url":"https://github.com/org/repo/issues/1","title":"Issue"}}
+			],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+		}
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+	return c, &mutations
+}
+
+func TestUpdateProjectFieldSetsAssignees(t *testing.T) {
+	c, mutations := assigneesTestClient(t, `[{"login":"alice"},{"login":"bob"}]`)
+
+	err := c.UpdateProjectField(context.Background(), "target", "https://github.com/org/repo/issues/1", github.ProjectField{
+		Name:  "Assignees",
+		Value: github.ProjectFieldValue{Users: []string{"bob"}},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"add", "remove"}, *mutations)
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", "https://github.com/org/repo/issues/1", nil)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, []string{"bob"}, fields[0].Value.Users, "expected the cache to be updated")
+}
+
+func TestUpdateProjectFieldReportsUnassignableUser(t *testing.T) {
+	c, _ := assigneesTestClient(t, `[{"login":"alice"}]`)
+
+	err := c.UpdateProjectField(context.Background(), "target", "https://github.com/org/repo/issues/1", github.ProjectField{
+		Name:  "Assignees",
+		Value: github.ProjectFieldValue{Users: []string{"alice", "mallory"}},
+	}, false)
+	assert.ErrorContains(t, err, "could not assign mallory")
+	assert.ErrorContains(t, err, "only collaborators of the repository can be assigned")
+}
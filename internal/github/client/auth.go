@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// ScopeProject grants read and write access to projects
+	ScopeProject = "project"
+	// ScopeReadProject grants read access to projects
+	ScopeReadProject = "read:project"
+)
+
+// AuthStatus describes the identity and permissions of the token of a client
+type AuthStatus struct {
+	Login string `json:"login"`
+	// Scopes are the OAuth scopes of a classic personal access token, or nil if the token
+	// does not report scopes, as fine-grained and GitHub App tokens do
+	Scopes []string `json:"scopes"`
+}
+
+// ScopesKnown reports whether the token reported its scopes
+func (s AuthStatus) ScopesKnown() bool {
+	return s.Scopes != nil
+}
+
+// HasScope reports whether the token has the given scope. The project scope includes
+// read:project.
+func (s AuthStatus) HasScope(scope string) bool {
+	if slices.Contains(s.Scopes, scope) {
+		return true
+	}
+	return scope == ScopeReadProject && slices.Contains(s.Scopes, ScopeProject)
+}
+
+// scopeTransport records the OAuth scopes reported in the X-OAuth-Scopes header of the
+// last response
+type scopeTransport struct {
+	transport http.RoundTripper
+
+	mu     sync.Mutex
+	scopes []string
+}
+
+func (t *scopeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scopes = nil
+	if ok {
+		t.scopes = parseScopes(strings.Join(values, ","))
+	}
+	return resp, nil
+}
+
+// lastScopes returns the scopes of the last response, or nil if it reported none
+func (t *scopeTransport) lastScopes() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.scopes
+}
+
+// parseScopes parses a comma-separated list of scopes. An empty list yields an empty,
+// non-nil slice, as the token reported that it has no scopes.
+func parseScopes(header string) []string {
+	scopes := []string{}
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// VerifyAuth queries the login of the authenticated user and the scopes of the token
+func (c *GraphQLClient) VerifyAuth(ctx context.Context) (AuthStatus, error) {
+	var query struct {
+		Viewer struct {
+			Login string
+		}
+	}
+	if err := c.queryWithRetry(ctx, &query, nil); err != nil {
+		return AuthStatus{}, fmt.Errorf("failed to query the authenticated user: %w", err)
+	}
+
+	status := AuthStatus{Login: query.Viewer.Login}
+	if c.scopes != nil {
+		status.Scopes = c.scopes.lastScopes()
+	}
+	return status, nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestVerifyAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     []string
+		wantScopes []string
+	}{
+		{name: "classic token", header: []string{"repo, read:org, project"}, wantScopes: []string{"repo", "read:org", "project"}},
+		{name: "no scopes", header: []string{""}, wantScopes: []string{}},
+		{name: "fine-grained token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorization string
+			c, err := NewGraphQLClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), Options{
+				Transport: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+					authorization = req.Header.Get("Authorization")
+					resp := GraphQLResponse(map[string]interface{}{"viewer": map[string]string{"login": "octocat"}})
+					if tt.header != nil {
+						resp.Header["X-Oauth-Scopes"] = tt.header
+					}
+					return resp, nil
+				}),
+			})
+			require.NoError(t, err)
+
+			status, err := c.VerifyAuth(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer token", authorization)
+			assert.Equal(t, "octocat", status.Login)
+			assert.Equal(t, tt.wantScopes, status.Scopes)
+			assert.Equal(t, tt.header != nil, status.ScopesKnown())
+		})
+	}
+}
+
+func TestAuthStatusHasScope(t *testing.T) {
+	write := AuthStatus{Scopes: []string{"repo", ScopeProject}}
+	assert.True(t, write.HasScope(ScopeProject))
+	assert.True(t, write.HasScope(ScopeReadProject), "expected project to include read:project")
+
+	read := AuthStatus{Scopes: []string{ScopeReadProject}}
+	assert.False(t, read.HasScope(ScopeProject))
+	assert.True(t, read.HasScope(ScopeReadProject))
+}
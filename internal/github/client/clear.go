@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// ClearProjectField implements the Client interface
+func (c *GraphQLClient) ClearProjectField(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+	project, err := c.getProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	itemID, currentValue, itemErr := c.findProjectItem(project, issueURL, field)
+	fieldID, _, fieldErr := c.findProjectField(project, field)
+	dataType := fieldDataType(project, field)
+	c.mu.RUnlock()
+	if itemErr != nil {
+		return itemErr
+	}
+	if fieldErr != nil {
+		return fieldErr
+	}
+
+	field.ID = fieldID
+	return c.clearFieldValue(ctx, project, issueURL, itemID, field, dataType, currentValue, dryRun)
+}
+
+// clearFieldValue removes the value of a field from a project item, if it has one
+func (c *GraphQLClient) clearFieldValue(ctx context.Context, project *ProjectV2, issueURL, itemID string, field github.ProjectField, dataType string, currentValue *ProjectV2ItemFieldValue, dryRun bool) error {
+	fieldID, fieldName := field.ID, field.Name
+	// Assignees, milestones and labels reflect the issue itself and are not cleared on the item
+	if dataType == "ASSIGNEES" || dataType == "MILESTONE" || dataType == "LABELS" {
+		return fmt.Errorf("field %s reflects the issue itself and cannot be cleared", fieldName)
+	}
+
+	if currentValue == nil {
+		c.logFieldUpdate(fieldName, "", "", dryRun, "project_id", project.ID, "item_id", itemID, "field_id", fieldID, "already_empty", true)
+		return nil
+	}
+
+	oldValue, _ := c.getFieldUpdateValues(currentValue, github.ProjectField{})
+	c.logFieldUpdate(fieldName, oldValue, "", dryRun, "project_id", project.ID, "item_id", itemID, "field_id", fieldID)
+	if dryRun {
+		return nil
+	}
+
+	var mutation struct {
+		ClearProjectV2ItemFieldValue struct {
+			ClientMutationID string
+		} `graphql:"clearProjectV2ItemFieldValue(input: $input)"`
+	}
+	input := githubv4.ClearProjectV2ItemFieldValueInput{
+		ProjectID: githubv4.ID(project.ID),
+		ItemID:    githubv4.ID(itemID),
+		FieldID:   githubv4.ID(fieldID),
+	}
+	if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to clear field: %w", err)
+	}
+
+	c.removeCacheFieldValue(project, issueURL, field)
+	return nil
+}
+
+// removeCacheFieldValue removes the value of a field of an issue from the cached project
+func (c *GraphQLClient) removeCacheFieldValue(project *ProjectV2, issueURL string, field github.ProjectField) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := c.findItem(project, issueURL)
+	if item == nil {
+		return
+	}
+	values := item.Fields.Nodes[:0:0]
+	for _, fieldValue := range item.Fields.Nodes {
+		if !fieldValue.matchesField(field) {
+			values = append(values, fieldValue)
+		}
+	}
+	item.Fields.Nodes = values
+}
+
+// fieldName returns the name of the field a value belongs to
+func (v *ProjectV2ItemFieldValue) fieldName() string {
+	switch v.TypeName {
+	case "ProjectV2ItemFieldDateValue":
+		return v.DateValue.Field.DateField.Name
+	case "ProjectV2ItemFieldSingleSelectValue":
+		return v.SingleSelectValue.Field.SingleSelectField.Name
+	case "ProjectV2ItemFieldNumberValue":
+		return v.NumberValue.Field.ProjectField.Name
+	case "ProjectV2ItemFieldTextValue":
+		return v.TextValue.Field.ProjectField.Name
+	case "ProjectV2ItemFieldIterationValue":
+		return v.IterationValue.Field.IterationField.Name
+	case "ProjectV2ItemFieldUserValue":
+		return v.UserValue.Field.ProjectField.Name
+	case "ProjectV2ItemFieldMilestoneValue":
+		return v.MilestoneValue.Field.ProjectField.Name
+	case "ProjectV2ItemFieldLabelValue":
+		return v.LabelValue.Field.ProjectField.Name
+	default:
+		return ""
+	}
+}
+
+// fieldID returns the ID of the field a value belongs to
+func (v *ProjectV2ItemFieldValue) fieldID() string {
+	switch v.TypeName {
+	case "ProjectV2ItemFieldDateValue":
+		return v.DateValue.Field.DateField.ID
+	case "ProjectV2ItemFieldSingleSelectValue":
+		return v.SingleSelectValue.Field.SingleSelectField.ID
+	case "ProjectV2ItemFieldNumberValue":
+		return v.NumberValue.Field.ProjectField.ID
+	case "ProjectV2ItemFieldTextValue":
+		return v.TextValue.Field.ProjectField.ID
+	case "ProjectV2ItemFieldIterationValue":
+		return v.IterationValue.Field.IterationField.ID
+	case "ProjectV2ItemFieldUserValue":
+		return v.UserValue.Field.ProjectField.ID
+	case "ProjectV2ItemFieldMilestoneValue":
+		return v.MilestoneValue.Field.ProjectField.ID
+	case "ProjectV2ItemFieldLabelValue":
+		return v.LabelValue.Field.ProjectField.ID
+	default:
+		return ""
+	}
+}
+
+// matchesField checks if a value belongs to a field, matched by its ID if given and else
+// by its name
+func (v *ProjectV2ItemFieldValue) matchesField(field github.ProjectField) bool {
+	if v.fieldName() == "" {
+		// Values of other field types are not read
+		return false
+	}
+	return matchesField(field, v.fieldID(), v.fieldName())
+}
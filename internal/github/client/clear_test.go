@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestClearProjectField(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var mutations []map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "clearProjectV2ItemFieldValue(") {
+			mutations = append(mutations, req.Variables["input"].(map[string]interface{}))
+			return `{"data":{"clearProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		return `{"data":{"node":{"id":"project","fields":{"nodes":[
+			{"__typename":"ProjectV2Field","id":"field_start","name":"Start","dataType":"DATE"},
+			{"__typename":"ProjectV2Field","id":"field_end","name":"End","dataType":"DATE"},
+			{"__typename":"ProjectV2Field","id":"field_assignees","name":"Assignees","dataType":"ASSIGNEES"}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldDateValue","field":{"__typename":"ProjectV2Field","id":"field_start","name":"Start"},"date":"2024-03-01"}
+			]},"content":{"__typename":"Issue","id":"issue_1","url":"https://github.com/org/repo/issues/1","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+	require.NoError(t, err)
+
+	// Dry run mode does not clear anything
+	require.NoError(t, c.ClearProjectField(context.Background(), "project", issueURL, github.ProjectField{Name: "Start"}, true))
+	assert.Empty(t, mutations)
+
+	require.NoError(t, c.ClearProjectField(context.Background(), "project", issueURL, github.ProjectField{Name: "Start"}, false))
+	require.Len(t, mutations, 1)
+	assert.Equal(t, map[string]interface{}{"projectId": "project", "itemId": "item_1", "fieldId": "field_start"}, mutations[0])
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "project", issueURL, nil)
+	require.NoError(t, err)
+	assert.Empty(t, fields, "expected the value to be removed from the cache")
+
+	// Fields without a value are left alone
+	require.NoError(t, c.ClearProjectField(context.Background(), "project", issueURL, github.ProjectField{Name: "End"}, false))
+	assert.Len(t, mutations, 1)
+
+	err = c.ClearProjectField(context.Background(), "project", "https://github.com/org/repo/issues/2", github.ProjectField{Name: "Start"}, false)
+	assert.ErrorIs(t, err, ErrIssueNotFound)
+
+	err = c.ClearProjectField(context.Background(), "project", issueURL, github.ProjectField{Name: "Priority"}, false)
+	assert.ErrorIs(t, err, ErrFieldNotFound)
+
+	err = c.ClearProjectField(context.Background(), "project", issueURL, github.ProjectField{Name: "Assignees"}, false)
+	assert.EqualError(t, err, "field Assignees reflects the issue itself and cannot be cleared")
+}
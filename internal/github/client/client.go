@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/naag/gh-project-toolkit/internal/github"
 )
@@ -9,15 +10,79 @@ import (
 type Client interface {
 	GetProjectID(ctx context.Context, projectInfo *github.ProjectInfo) (string, error)
 
+	// GetProjectIDs returns the IDs of a source and target project, resolving them in as
+	// few queries as possible
+	GetProjectIDs(ctx context.Context, sourceProject, targetProject *github.ProjectInfo) (sourceID, targetID string, err error)
+
 	GetProjectFields(ctx context.Context, projectID string, issueURL string) ([]github.ProjectField, error)
 
 	UpdateProjectField(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error
 
 	GetProjectIssues(ctx context.Context, projectID string) ([]string, error)
 
+	// GetProjectIssuesFiltered returns the issues of a project whose items match a project
+	// filter expression of field:value and label: qualifiers
+	GetProjectIssuesFiltered(ctx context.Context, projectID string, filter string) ([]string, error)
+
 	GetProjectFieldConfigsAndIssues(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error)
 
+	GetProjectFieldConfigs(ctx context.Context, projectID string) ([]github.ProjectFieldConfig, error)
+
 	GetProjectFieldValues(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error)
 
 	GetIssueTitle(ctx context.Context, issueURL string) (string, error)
+
+	GetIssueTitles(ctx context.Context, issueURLs []string) (map[string]string, error)
+
+	GetIssueLabels(ctx context.Context, issueURL string) ([]string, error)
+
+	// SetIssueLabels replaces the labels of an issue or pull request. Labels missing in its
+	// repository are skipped with a warning, or created if enabled. In dry run mode, the
+	// labels are only looked up.
+	SetIssueLabels(ctx context.Context, issueURL string, labels []string, dryRun bool) error
+
+	GetIssueUpdatedAt(ctx context.Context, issueURL string) (time.Time, error)
+
+	// GetIssueState returns the state of an issue, IssueStateOpen or IssueStateClosed, or
+	// IssueStateMerged for pull requests
+	GetIssueState(ctx context.Context, issueURL string) (string, error)
+
+	// ClearProjectField removes the value of a field of an issue, matched by its ID if given
+	// and else by its name
+	ClearProjectField(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error
+
+	DeleteProjectItem(ctx context.Context, projectID string, issueURL string) error
+
+	AddProjectItem(ctx context.Context, projectID string, issueURL string) (string, error)
+
+	// AddIssueToProject adds an issue to a project and returns the ID of the new item. In
+	// dry run mode, the issue is only looked up and no item is added.
+	AddIssueToProject(ctx context.Context, projectID string, issueURL string, dryRun bool) (string, error)
+
+	CreateSingleSelectOption(ctx context.Context, projectID, fieldID, optionName string) error
+
+	// CreateProjectField creates a field like the given configuration in a project and returns
+	// the configuration of the new field. Single select fields get the options of the
+	// configuration.
+	CreateProjectField(ctx context.Context, projectID string, cfg github.ProjectFieldConfig) (github.ProjectFieldConfig, error)
+
+	RateLimitStatus() github.RateLimitStatus
+
+	// DuplicateIssueCount returns the number of duplicate issue items dropped while loading
+	// the last projects
+	DuplicateIssueCount() int
+
+	// APICallCount returns the number of GraphQL queries and mutations sent so far,
+	// including retries
+	APICallCount() int
+
+	// QueryCost returns the summed up rate limit cost of the queries sent so far, which is
+	// only tracked if the client logs query costs
+	QueryCost() int
+
+	Host() string
+
+	// ServerFilter returns the project filter expression the items of source projects are
+	// fetched with, or an empty string if all items are fetched
+	ServerFilter() string
 }
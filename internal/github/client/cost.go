@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// queryCostAlias is the alias of the rate limit selection added to queries by costTransport,
+// chosen so that it does not clash with fields of the queries themselves
+const queryCostAlias = "queryCost"
+
+// queryCost is the cost of a single query as reported by the rate limit selection
+type queryCost struct {
+	Cost      int
+	NodeCount int
+}
+
+// costTracker sums up the cost of all queries of a run
+type costTracker struct {
+	mu    sync.Mutex
+	total int
+}
+
+// add records the cost of a query
+func (t *costTracker) add(cost int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total += cost
+}
+
+// Total returns the summed up cost of all queries so far. A nil tracker has no cost.
+func (t *costTracker) Total() int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// costTransport selects the cost and node count of every query by adding an aliased
+// rateLimit selection, logs them at debug level and sums up the cost. The selection is
+// removed from the response again, so that the queries decode as usual. Mutations have
+// no rateLimit field and are passed through.
+type costTransport struct {
+	transport http.RoundTripper
+	tracker   *costTracker
+}
+
+func (t *costTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if req.Body == nil {
+		return transport.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var in struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables,omitempty"`
+	}
+	if err := json.Unmarshal(body, &in); err != nil || strings.HasPrefix(in.Query, "mutation") || !strings.Contains(in.Query, "{") {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return transport.RoundTrip(req)
+	}
+
+	i := strings.Index(in.Query, "{") + 1
+	in.Query = in.Query[:i] + queryCostAlias + ":rateLimit{cost,nodeCount}," + in.Query[i:]
+	if body, err = json.Marshal(in); err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(t.stripCost(respBody)))
+	resp.ContentLength = -1
+	return resp, nil
+}
+
+// stripCost records the cost selected by the transport and removes it from a response.
+// Responses that cannot be decoded are returned unchanged.
+func (t *costTransport) stripCost(body []byte) []byte {
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(body, &out); err != nil {
+		return body
+	}
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(out["data"], &data); err != nil || data == nil {
+		return body
+	}
+
+	var cost queryCost
+	if err := json.Unmarshal(data[queryCostAlias], &cost); err == nil {
+		t.tracker.add(cost.Cost)
+		slog.Debug("GraphQL query cost",
+			"cost", cost.Cost,
+			"node_count", cost.NodeCount,
+			"total_cost", t.tracker.Total(),
+		)
+	}
+
+	delete(data, queryCostAlias)
+	stripped, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+	out["data"] = stripped
+	result, err := json.Marshal(out)
+	if err != nil {
+		return body
+	}
+	return result
+}
+
+// QueryCost implements the Client interface
+func (c *GraphQLClient) QueryCost() int {
+	return c.costs.Total()
+}
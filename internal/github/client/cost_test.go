@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestLogCostSumsUpQueryCosts(t *testing.T) {
+	var queries []string
+	c, err := NewGraphQLClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), Options{
+		LogCost: true,
+		Transport: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := DecodeGraphQLRequest(req)
+			require.NoError(t, err)
+			queries = append(queries, body.Query)
+
+			if strings.Contains(body.Query, "queryCost:rateLimit{cost,nodeCount}") {
+				return GraphQLResponse(map[string]interface{}{
+					"queryCost":    map[string]interface{}{"cost": 3, "nodeCount": 250},
+					"organization": map[string]interface{}{"projectV2": map[string]interface{}{"id": "project_" + body.Variables["login"].(string)}},
+				}), nil
+			}
+			return GraphQLResponse(map[string]interface{}{
+				"organization": map[string]interface{}{"projectV2": map[string]interface{}{"id": "project"}},
+			}), nil
+		}),
+	})
+	require.NoError(t, err)
+
+	id, err := c.GetProjectID(context.Background(), &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "a", ProjectNumber: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "project_a", id, "expected the query to decode without the cost selection")
+	_, err = c.GetProjectID(context.Background(), &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "b", ProjectNumber: 1})
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	assert.Equal(t, 6, c.QueryCost())
+}
+
+func TestCostTransportPassesMutationsThrough(t *testing.T) {
+	var query string
+	transport := &costTransport{
+		tracker: &costTracker{},
+		transport: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := DecodeGraphQLRequest(req)
+			require.NoError(t, err)
+			query = body.Query
+			return GraphQLResponse(map[string]interface{}{}), nil
+		}),
+	}
+
+	mutation := `mutation($input:AddProjectV2ItemByIdInput!){addProjectV2ItemById(input: $input){item{id}}}`
+	req, err := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query":"`+strings.ReplaceAll(mutation, `"`, `\"`)+`"}`))
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, mutation, query)
+	assert.Zero(t, transport.tracker.Total())
+}
@@ -1,22 +1,35 @@
 package client
 
 import (
+	"bytes"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
 )
 
-// debugTransport wraps an HTTP transport and logs requests/responses
+// redactedHeaders are the headers whose values are masked in dumps, as they carry credentials
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// debugTransport wraps an HTTP transport and logs requests/responses at debug level, with
+// the full dump as the dump attribute
 type debugTransport struct {
 	transport http.RoundTripper
+	// logger receives the dumps, defaulting to the default logger
+	logger *slog.Logger
 }
 
 func (d *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := d.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	reqDump, err := httputil.DumpRequestOut(req, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dump request: %w", err)
 	}
-	fmt.Printf(">>> Request:\n%s\n", string(reqDump))
+	logger.Debug("HTTP request", "method", req.Method, "url", req.URL.String(), "dump", string(redactDump(reqDump)))
 
 	resp, err := d.transport.RoundTrip(req)
 	if err != nil {
@@ -27,7 +40,34 @@ func (d *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to dump response: %w", err)
 	}
-	fmt.Printf("<<< Response:\n%s\n", string(respDump))
+	logger.Debug("HTTP response", "status", resp.StatusCode, "dump", string(redactDump(respDump)))
 
 	return resp, nil
 }
+
+// redactDump masks the values of credential headers in the header section of a request or
+// response dump, leaving the rest of the dump intact
+func redactDump(dump []byte) []byte {
+	header, body, found := bytes.Cut(dump, []byte("\r\n\r\n"))
+
+	lines := bytes.Split(header, []byte("\r\n"))
+	for i, line := range lines {
+		name, _, ok := bytes.Cut(line, []byte(":"))
+		if !ok {
+			continue
+		}
+		for _, redacted := range redactedHeaders {
+			if bytes.EqualFold(bytes.TrimSpace(name), []byte(redacted)) {
+				lines[i] = append(append([]byte{}, name...), []byte(": [REDACTED]")...)
+				break
+			}
+		}
+	}
+
+	redacted := bytes.Join(lines, []byte("\r\n"))
+	if found {
+		redacted = append(redacted, []byte("\r\n\r\n")...)
+		redacted = append(redacted, body...)
+	}
+	return redacted
+}
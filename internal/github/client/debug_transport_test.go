@@ -0,0 +1,61 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugTransportRedactsCredentials(t *testing.T) {
+	var out bytes.Buffer
+	transport := &debugTransport{
+		transport: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Set-Cookie": []string{"session=secret-session"}, "Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"data":{}}`)),
+				Request:    req,
+			}, nil
+		}),
+		logger: slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query":"{viewer{login}}"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "bearer secret-token")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Both dumps are logged as an attribute of their own record
+	var dumps []string
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var record struct {
+			Msg  string
+			Dump string
+		}
+		require.NoError(t, decoder.Decode(&record))
+		dumps = append(dumps, record.Dump)
+	}
+	require.Len(t, dumps, 2)
+	dump := strings.Join(dumps, "\n")
+	assert.NotContains(t, dump, "secret-token")
+	assert.NotContains(t, dump, "secret-session")
+	assert.Contains(t, dump, "Authorization: [REDACTED]")
+	assert.Contains(t, dump, "Set-Cookie: [REDACTED]")
+
+	// The rest of the dump is left intact
+	assert.Contains(t, dump, "Content-Type: application/json")
+	assert.Contains(t, dump, `{"query":"{viewer{login}}"}`)
+	assert.Contains(t, dump, `{"data":{}}`)
+}
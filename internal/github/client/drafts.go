@@ -0,0 +1,61 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+// DraftIssuePrefix starts the synthetic URLs given to draft issues, which have no URL of
+// their own
+const DraftIssuePrefix = "draft:"
+
+// DraftIssueURL returns the synthetic URL of a draft issue. Draft issues are matched across
+// projects by this URL, and so by their title.
+func DraftIssueURL(title string) string {
+	return DraftIssuePrefix + strings.TrimSpace(title)
+}
+
+// IsDraftIssueURL reports whether a URL is the synthetic URL of a draft issue
+func IsDraftIssueURL(issueURL string) bool {
+	return strings.HasPrefix(issueURL, DraftIssuePrefix)
+}
+
+// isIssue reports whether an item is an issue, a draft issue given a synthetic URL by
+// includeDraftIssues, or a pull request kept by includePullRequests
+func (item *ProjectV2Item) isIssue() bool {
+	switch item.Content.TypeName {
+	case "Issue":
+		return true
+	case "DraftIssue", "PullRequest":
+		return item.Content.Issue.URL != ""
+	default:
+		return false
+	}
+}
+
+// hasIssueURL reports whether an item is the issue with the given URL. URLs are compared in
+// their canonical form, so that variants of the URL of an issue, such as with a differently
+// cased owner, match as well.
+func (item *ProjectV2Item) hasIssueURL(issueURL string) bool {
+	return item.isIssue() && (item.Content.Issue.URL == issueURL ||
+		util.CanonicalizeIssueURL(item.Content.Issue.URL) == util.CanonicalizeIssueURL(issueURL))
+}
+
+// includeDraftIssues gives draft issues a synthetic URL derived from their title if drafts
+// are included, so that they are treated like issues everywhere else
+func (c *GraphQLClient) includeDraftIssues(items []ProjectV2Item) {
+	if !c.includeDrafts {
+		return
+	}
+	for i := range items {
+		content := &items[i].Content
+		if content.TypeName == "DraftIssue" {
+			content.Issue.URL = DraftIssueURL(content.DraftIssue.Title)
+			content.Issue.Title = content.DraftIssue.Title
+			content.Issue.UpdatedAt = content.DraftIssue.UpdatedAt
+			// Draft issues cannot be closed
+			content.Issue.State = IssueStateOpen
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftIssuesAreIncludedByTitle(t *testing.T) {
+	response := `{"data":{"node":{"id":"project","fields":{"nodes":[
+		{"__typename":"ProjectV2Field","id":"field_start","name":"Start","dataType":"DATE"}
+	]},"items":{"nodes":[
+		{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"issue_1","url":"https://github.com/org/repo/issues/1","title":"Issue"}},
+		{"id":"item_2","fieldValues":{"nodes":[
+			{"__typename":"ProjectV2ItemFieldDateValue","field":{"__typename":"ProjectV2Field","id":"field_start","name":"Start"},"date":"2024-03-01"}
+		]},"content":{"__typename":"DraftIssue","title":" Plan the launch "}}
+	],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+
+	t.Run("excluded by default", func(t *testing.T) {
+		c := newTestClient(t, func(req GraphQLRequest) string { return response })
+
+		_, _, sourceIssues, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, sourceIssues)
+	})
+
+	t.Run("included", func(t *testing.T) {
+		c := newTestClient(t, func(req GraphQLRequest) string { return response })
+		c.includeDrafts = true
+
+		_, _, sourceIssues, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+		require.NoError(t, err)
+		draftURL := DraftIssueURL("Plan the launch")
+		assert.Equal(t, []string{"https://github.com/org/repo/issues/1", draftURL}, sourceIssues)
+		assert.True(t, IsDraftIssueURL(draftURL))
+
+		fields, err := c.GetProjectFieldValues(context.Background(), "project", draftURL, nil)
+		require.NoError(t, err)
+		require.Len(t, fields, 1)
+		assert.Equal(t, "2024-03-01", fields[0].Value.String())
+
+		title, err := c.GetIssueTitle(context.Background(), draftURL)
+		require.NoError(t, err)
+		assert.Equal(t, " Plan the launch ", title)
+	})
+}
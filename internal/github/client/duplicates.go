@@ -0,0 +1,90 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+const (
+	// OnDuplicateFirst keeps the first item of an issue that appears more than once in a project
+	OnDuplicateFirst = "first"
+	// OnDuplicateLast keeps the last item of an issue that appears more than once in a project
+	OnDuplicateLast = "last"
+	// OnDuplicateError fails if an issue appears more than once in a project
+	OnDuplicateError = "error"
+)
+
+// validateOnDuplicate checks that the given duplicate handling policy is supported
+func validateOnDuplicate(policy string) error {
+	switch policy {
+	case OnDuplicateFirst, OnDuplicateLast, OnDuplicateError:
+		return nil
+	default:
+		return fmt.Errorf("invalid duplicate handling %q (expected %s, %s or %s)", policy, OnDuplicateError, OnDuplicateFirst, OnDuplicateLast)
+	}
+}
+
+// dedupeProjectItems keeps a single item per issue, the first or the last depending on the
+// policy, so that all lookups of an issue agree on its item. Such duplicates can be left
+// behind when draft issues are converted. It returns the remaining items and the number of
+// items removed, or an error if the policy is OnDuplicateError and an issue appears twice.
+func dedupeProjectItems(projectID string, items []ProjectV2Item, policy string) ([]ProjectV2Item, int, error) {
+	counts := make(map[string]int)
+	var order []string
+	for _, item := range items {
+		if !item.isIssue() {
+			continue
+		}
+		issueURL := util.CanonicalizeIssueURL(item.Content.Issue.URL)
+		if counts[issueURL] == 0 {
+			order = append(order, issueURL)
+		}
+		counts[issueURL]++
+	}
+
+	duplicates := 0
+	for _, issueURL := range order {
+		if counts[issueURL] < 2 {
+			continue
+		}
+		duplicates += counts[issueURL] - 1
+		if policy == OnDuplicateError {
+			return nil, duplicates, fmt.Errorf("issue %s appears %d times in project %s (use --on-duplicate first or last to pick one of its items)", issueURL, counts[issueURL], projectID)
+		}
+		slog.Warn("issue appears more than once in project",
+			"issue", issueURL,
+			"project", projectID,
+			"count", counts[issueURL],
+			"keeping", policy,
+		)
+	}
+	if duplicates == 0 {
+		return items, 0, nil
+	}
+
+	// Keep an item if it is the first (or last) occurrence of its issue
+	seen := make(map[string]int)
+	deduped := make([]ProjectV2Item, 0, len(items)-duplicates)
+	for _, item := range items {
+		if !item.isIssue() {
+			deduped = append(deduped, item)
+			continue
+		}
+		issueURL := util.CanonicalizeIssueURL(item.Content.Issue.URL)
+		seen[issueURL]++
+		if (policy == OnDuplicateLast && seen[issueURL] == counts[issueURL]) ||
+			(policy != OnDuplicateLast && seen[issueURL] == 1) {
+			deduped = append(deduped, item)
+		}
+	}
+	return deduped, duplicates, nil
+}
+
+// DuplicateIssueCount implements the Client interface
+func (c *GraphQLClient) DuplicateIssueCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.duplicateIssues
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// projectIssueItem builds a project item with the given ID for an issue
+func projectIssueItem(id, issueURL string) ProjectV2Item {
+	var item ProjectV2Item
+	item.ID = id
+	item.Content.TypeName = "Issue"
+	item.Content.Issue.URL = issueURL
+	return item
+}
+
+func itemIDs(items []ProjectV2Item) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestDedupeProjectItems(t *testing.T) {
+	var draft ProjectV2Item
+	draft.ID = "draft"
+	draft.Content.TypeName = "DraftIssue"
+
+	items := []ProjectV2Item{
+		projectIssueItem("a1", "https://github.com/org/repo/issues/1"),
+		projectIssueItem("b", "https://github.com/org/repo/issues/2"),
+		draft,
+		projectIssueItem("a2", "https://github.com/org/repo/issues/1"),
+		draft,
+		projectIssueItem("a3", "https://github.com/org/repo/issues/1"),
+	}
+
+	t.Run("first", func(t *testing.T) {
+		deduped, duplicates, err := dedupeProjectItems("project", items, OnDuplicateFirst)
+		require.NoError(t, err)
+		assert.Equal(t, 2, duplicates)
+		assert.Equal(t, []string{"a1", "b", "draft", "draft"}, itemIDs(deduped))
+	})
+
+	t.Run("last", func(t *testing.T) {
+		deduped, duplicates, err := dedupeProjectItems("project", items, OnDuplicateLast)
+		require.NoError(t, err)
+		assert.Equal(t, 2, duplicates)
+		assert.Equal(t, []string{"b", "draft", "draft", "a3"}, itemIDs(deduped))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := dedupeProjectItems("project", items, OnDuplicateError)
+		assert.ErrorContains(t, err, "issue https://github.com/org/repo/issues/1 appears 3 times in project project")
+	})
+
+	t.Run("no duplicates", func(t *testing.T) {
+		deduped, duplicates, err := dedupeProjectItems("project", items[:3], OnDuplicateError)
+		require.NoError(t, err)
+		assert.Zero(t, duplicates)
+		assert.Equal(t, []string{"a1", "b", "draft"}, itemIDs(deduped))
+	})
+}
+
+func TestNewGraphQLClientRejectsInvalidOnDuplicate(t *testing.T) {
+	_, err := NewGraphQLClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), Options{OnDuplicate: "random"})
+	assert.EqualError(t, err, `invalid duplicate handling "random" (expected error, first or last)`)
+}
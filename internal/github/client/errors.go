@@ -0,0 +1,59 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinels for project data that does not exist, matched with errors.Is. The errors
+// returned are NotFoundErrors naming what is missing.
+var (
+	ErrIssueNotFound     = errors.New("issue not found")
+	ErrFieldNotFound     = errors.New("field not found")
+	ErrOptionNotFound    = errors.New("option not found")
+	ErrIterationNotFound = errors.New("iteration not found")
+	ErrProjectNotFound   = errors.New("project not found")
+)
+
+// ErrAPIBudgetExhausted is returned for requests beyond the maximum number of API calls of a
+// client, matched with errors.Is
+var ErrAPIBudgetExhausted = errors.New("API call budget exhausted")
+
+// NotFoundError reports an issue, field, single select option, iteration or project that
+// does not exist
+type NotFoundError struct {
+	// Kind is the sentinel the error matches, such as ErrIssueNotFound
+	Kind error
+	// Name is the issue URL, field name, option name, iteration title or project that was
+	// looked up
+	Name string
+	// Field is the field of a missing option or iteration
+	Field string
+}
+
+func (e *NotFoundError) Error() string {
+	switch e.Kind {
+	case ErrIssueNotFound:
+		return fmt.Sprintf("issue %s not found in project", e.Name)
+	case ErrFieldNotFound:
+		return fmt.Sprintf("field %s not found in project", e.Name)
+	case ErrOptionNotFound:
+		return fmt.Sprintf("single select option %q not found in target field %q", e.Name, e.Field)
+	case ErrIterationNotFound:
+		return fmt.Sprintf("iteration %q not found in target field %q", e.Name, e.Field)
+	case ErrProjectNotFound:
+		return fmt.Sprintf("project %s not found", e.Name)
+	default:
+		return fmt.Sprintf("%s not found", e.Name)
+	}
+}
+
+// Unwrap returns the sentinel of the error
+func (e *NotFoundError) Unwrap() error {
+	return e.Kind
+}
+
+// issueNotFound returns the error for an issue missing in a project
+func issueNotFound(issueURL string) error {
+	return &NotFoundError{Kind: ErrIssueNotFound, Name: issueURL}
+}
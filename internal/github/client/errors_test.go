@@ -0,0 +1,36 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestNotFoundErrors(t *testing.T) {
+	c := &GraphQLClient{}
+	project := &ProjectV2{ID: "project"}
+	project.Fields.Nodes = []ProjectV2FieldConfiguration{singleSelectField("field_status", "Status", map[string]string{"Done": "option_done"})}
+	project.Items.Nodes = []ProjectV2Item{projectIssueItem("item_1", "https://github.com/org/repo/issues/1")}
+
+	_, _, err := c.findProjectItem(project, "https://github.com/org/repo/issues/2", github.ProjectField{Name: "Status"})
+	assert.ErrorIs(t, err, ErrIssueNotFound)
+	assert.EqualError(t, err, "issue https://github.com/org/repo/issues/2 not found in project")
+
+	_, _, err = c.findProjectField(project, github.ProjectField{Name: "Priority"})
+	assert.ErrorIs(t, err, ErrFieldNotFound)
+	assert.NotErrorIs(t, err, ErrIssueNotFound)
+
+	value := "Blocked"
+	_, err = c.constructMutationInput(project, "item_1", "field_status", github.ProjectField{Name: "Status", Value: github.ProjectFieldValue{Text: &value}}, false)
+	require.ErrorIs(t, err, ErrOptionNotFound)
+
+	var notFound *NotFoundError
+	require.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "Blocked", notFound.Name)
+	assert.Equal(t, "Status", notFound.Field)
+	assert.EqualError(t, err, `single select option "Blocked" not found in target field "Status"`)
+}
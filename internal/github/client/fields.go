@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// creatableFieldTypes maps the data types of the fields the toolkit can create to their
+// field types in the API
+var creatableFieldTypes = map[string]githubv4.ProjectV2CustomFieldType{
+	"DATE":          githubv4.ProjectV2CustomFieldTypeDate,
+	"NUMBER":        githubv4.ProjectV2CustomFieldTypeNumber,
+	"TEXT":          githubv4.ProjectV2CustomFieldTypeText,
+	"SINGLE_SELECT": githubv4.ProjectV2CustomFieldTypeSingleSelect,
+}
+
+// CreateProjectField implements the Client interface
+func (c *GraphQLClient) CreateProjectField(ctx context.Context, projectID string, cfg github.ProjectFieldConfig) (github.ProjectFieldConfig, error) {
+	dataType, ok := creatableFieldTypes[cfg.DataType]
+	if !ok {
+		return github.ProjectFieldConfig{}, fmt.Errorf("cannot create field %q of type %s (expected DATE, NUMBER, TEXT or SINGLE_SELECT)", cfg.Name, cfg.DataType)
+	}
+
+	input := githubv4.CreateProjectV2FieldInput{
+		ProjectID: githubv4.ID(projectID),
+		DataType:  dataType,
+		Name:      githubv4.String(cfg.Name),
+	}
+	if dataType == githubv4.ProjectV2CustomFieldTypeSingleSelect {
+		options := make([]githubv4.ProjectV2SingleSelectFieldOptionInput, 0, len(cfg.Options))
+		for _, option := range cfg.Options {
+			options = append(options, githubv4.ProjectV2SingleSelectFieldOptionInput{
+				Name:  githubv4.String(option.Name),
+				Color: defaultOptionColor,
+			})
+		}
+		input.SingleSelectOptions = &options
+	}
+
+	var mutation struct {
+		CreateProjectV2Field struct {
+			ProjectV2Field ProjectV2FieldConfiguration
+		} `graphql:"createProjectV2Field(input: $input)"`
+	}
+
+	if err := c.mutateOnce(ctx, &mutation, input, nil); err != nil {
+		return github.ProjectFieldConfig{}, fmt.Errorf("failed to create field %q: %w", cfg.Name, err)
+	}
+
+	field := mutation.CreateProjectV2Field.ProjectV2Field
+	config := toFieldConfig(field)
+	slog.Info("created project field",
+		"project_id", projectID,
+		"field", config.Name,
+		"field_id", config.ID,
+		"data_type", config.DataType,
+	)
+
+	c.addCachedField(projectID, field)
+	return config, nil
+}
+
+// addCachedField adds a created field to the cached project and rebuilds the option index
+// of the target project
+func (c *GraphQLClient) addCachedField(projectID string, field ProjectV2FieldConfiguration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, project := range uniqueProjects(c.cache.sourceProject, c.cache.targetProject) {
+		if project != nil && project.ID == projectID {
+			project.Fields.Nodes = append(project.Fields.Nodes, field)
+		}
+	}
+
+	if c.cache.targetProject != nil && c.cache.targetProject.ID == projectID {
+		c.cache.targetOptions = buildOptionIndex(c.cache.targetProject)
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestCreateProjectFieldSeedsSingleSelectOptions(t *testing.T) {
+	var input map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "createProjectV2Field(") {
+			input, _ = req.Variables["input"].(map[string]interface{})
+			return `{"data":{"createProjectV2Field":{"projectV2Field":{
+				"__typename":"ProjectV2SingleSelectField","id":"field_phase","name":"Phase","options":[
+					{"id":"opt_plan","name":"Plan"},{"id":"opt_build","name":"Build"}
+				]
+			}}}}`
+		}
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[]},"items":{"nodes":[],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+
+	config, err := c.CreateProjectField(context.Background(), "target", github.ProjectFieldConfig{
+		ID:       "field_source",
+		Name:     "Phase",
+		DataType: "SINGLE_SELECT",
+		Options:  []github.ProjectFieldOption{{ID: "opt_1", Name: "Plan"}, {ID: "opt_2", Name: "Build"}},
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, input, "expected the field to be created")
+	assert.Equal(t, "target", input["projectId"])
+	assert.Equal(t, "SINGLE_SELECT", input["dataType"])
+	assert.Equal(t, "Phase", input["name"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "Plan", "color": "GRAY", "description": ""},
+		map[string]interface{}{"name": "Build", "color": "GRAY", "description": ""},
+	}, input["singleSelectOptions"])
+
+	assert.Equal(t, "field_phase", config.ID)
+	assert.Equal(t, []github.ProjectFieldOption{{ID: "opt_plan", Name: "Plan"}, {ID: "opt_build", Name: "Build"}}, config.Options)
+	assert.Equal(t, "opt_build", c.optionsFor(c.cache.targetProject).lookup("field_phase", "Build", false), "expected the cache to know the new field")
+}
+
+func TestCreateProjectFieldIsNotRetried(t *testing.T) {
+	mutations := 0
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		mutations++
+		return `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`
+	})
+	c.retry = RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	_, err := c.CreateProjectField(context.Background(), "target", github.ProjectFieldConfig{Name: "Start", DataType: "DATE"})
+	assert.ErrorContains(t, err, `failed to create field "Start"`)
+	assert.Equal(t, 1, mutations, "expected the creation not to be retried, as it may have succeeded")
+}
+
+func TestCreateProjectFieldRejectsUnsupportedTypes(t *testing.T) {
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		t.Errorf("unexpected request: %s", req.Query)
+		return `{"data":{}}`
+	})
+
+	_, err := c.CreateProjectField(context.Background(), "target", github.ProjectFieldConfig{Name: "Sprint", DataType: "ITERATION"})
+	assert.ErrorContains(t, err, `cannot create field "Sprint" of type ITERATION`)
+}
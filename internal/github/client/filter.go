@@ -0,0 +1,157 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// itemFilter is the client-side form of a project filter expression, used when
+// the GitHub API does not support filtering project items server-side
+type itemFilter []filterTerm
+
+// filterTerm is a single "field:value" qualifier of a filter expression
+type filterTerm struct {
+	field  string
+	values []string
+	negate bool
+}
+
+// unsupportedQualifiers are the qualifiers of GitHub's filter syntax that do not name a
+// project field, and that the client-side filter cannot evaluate
+var unsupportedQualifiers = map[string]bool{
+	"is": true, "no": true, "has": true, "reason": true, "repo": true, "type": true,
+	"created": true, "updated": true, "closed": true,
+}
+
+// parseItemFilter parses a filter expression made of space-separated "field:value" qualifiers.
+// Values may be quoted and may list alternatives separated by commas, and a leading "-"
+// negates a qualifier, e.g. `status:Todo,"In progress" -iteration:"Sprint 1"`. The label
+// qualifier matches the labels of the issue, e.g. `label:bug -label:wontfix`. Qualifiers that
+// cannot be evaluated on the client, such as `is:open` or `estimate:>3`, are rejected.
+func parseItemFilter(expr string) (itemFilter, error) {
+	var filter itemFilter
+	for _, token := range splitFilterTokens(expr) {
+		term := filterTerm{}
+		if strings.HasPrefix(token, "-") {
+			term.negate = true
+			token = token[1:]
+		}
+
+		field, value, ok := strings.Cut(token, ":")
+		if !ok || field == "" || value == "" {
+			return nil, fmt.Errorf("invalid filter qualifier %q (expected field:value)", token)
+		}
+
+		term.field = strings.Trim(field, `"`)
+		for _, v := range strings.Split(value, ",") {
+			term.values = append(term.values, strings.Trim(v, `"`))
+		}
+		if err := term.check(); err != nil {
+			return nil, err
+		}
+		filter = append(filter, term)
+	}
+	return filter, nil
+}
+
+// check returns an error if the qualifier cannot be evaluated on the client, which only
+// compares values for equality
+func (t filterTerm) check() error {
+	if unsupportedQualifiers[strings.ToLower(t.field)] {
+		return fmt.Errorf("filter qualifier %q is not supported (expected a field or label)", t.field)
+	}
+	for _, value := range t.values {
+		if strings.HasPrefix(value, ">") || strings.HasPrefix(value, "<") ||
+			strings.HasPrefix(value, "@") || strings.Contains(value, "..") {
+			return fmt.Errorf("filter value %q of %s is not supported (expected a plain value)", value, t.field)
+		}
+	}
+	return nil
+}
+
+// splitFilterTokens splits a filter expression on whitespace outside of double quotes
+func splitFilterTokens(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// matches reports whether the item satisfies all qualifiers of the filter
+func (f itemFilter) matches(item ProjectV2Item) bool {
+	for _, term := range f {
+		var values []string
+		if strings.EqualFold(term.field, "label") {
+			for _, label := range item.Content.Issue.Labels.Nodes {
+				values = append(values, label.Name)
+			}
+		} else {
+			values = itemFieldValues(item, term.field)
+		}
+
+		matched := false
+		for _, want := range term.values {
+			for _, value := range values {
+				if strings.EqualFold(value, want) {
+					matched = true
+				}
+			}
+		}
+		if matched == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the items of the project that match the filter
+func (f itemFilter) apply(items []ProjectV2Item) []ProjectV2Item {
+	var matching []ProjectV2Item
+	for _, item := range items {
+		if f.matches(item) {
+			matching = append(matching, item)
+		}
+	}
+	return matching
+}
+
+// itemFieldValues returns the values of the named field of an item as strings, matching
+// the field name case-insensitively. Users and labels fields hold a value per user or
+// label, other fields a single value, formatted like github.ProjectFieldValue.String
+// does. A field without a value holds an empty string.
+func itemFieldValues(item ProjectV2Item, fieldName string) []string {
+	for i := range item.Fields.Nodes {
+		fieldValue := &item.Fields.Nodes[i]
+		if !strings.EqualFold(fieldValue.fieldName(), fieldName) {
+			continue
+		}
+		value, _ := fieldValue.value()
+		switch {
+		case value.Users != nil:
+			return value.Users
+		case value.Labels != nil:
+			return value.Labels
+		default:
+			return []string{value.String()}
+		}
+	}
+	return []string{""}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filterTestItem builds a project item with a status, a start date, an iteration and an assignee
+func filterTestItem(status string, start time.Time) ProjectV2Item {
+	var statusValue ProjectV2ItemFieldValue
+	statusValue.TypeName = "ProjectV2ItemFieldSingleSelectValue"
+	statusValue.SingleSelectValue.Field.SingleSelectField.Name = "Status"
+	statusValue.SingleSelectValue.Name = &status
+
+	var dateValue ProjectV2ItemFieldValue
+	dateValue.TypeName = "ProjectV2ItemFieldDateValue"
+	dateValue.DateValue.Field.DateField.Name = "Start date"
+	dateValue.DateValue.Date = &GithubDate{Time: start}
+
+	var iterationValue ProjectV2ItemFieldValue
+	iterationValue.TypeName = "ProjectV2ItemFieldIterationValue"
+	iterationValue.IterationValue.Field.IterationField.Name = "Sprint"
+	iterationValue.IterationValue.Title = "Sprint 2"
+
+	var userValue ProjectV2ItemFieldValue
+	userValue.TypeName = "ProjectV2ItemFieldUserValue"
+	userValue.UserValue.Field.ProjectField.Name = "Assignees"
+	userValue.UserValue.setLogins([]string{"alice", "bob"})
+
+	var item ProjectV2Item
+	item.Fields.Nodes = []ProjectV2ItemFieldValue{statusValue, dateValue, iterationValue, userValue}
+	item.Content.Issue.Labels.Nodes = []struct{ Name string }{{Name: "bug"}, {Name: "frontend"}}
+	return item
+}
+
+func TestParseItemFilter(t *testing.T) {
+	filter, err := parseItemFilter(`status:Todo,"In progress"  -"start date":2024-01-01`)
+	require.NoError(t, err)
+	assert.Equal(t, itemFilter{
+		{field: "status", values: []string{"Todo", "In progress"}},
+		{field: "start date", values: []string{"2024-01-01"}, negate: true},
+	}, filter)
+
+	for _, expr := range []string{"free text", "is:open", "-no:assignee", "estimate:>3", `"start date":2024-01-01..2024-02-01`, "sprint:@current"} {
+		_, err = parseItemFilter(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestItemFilterMatches(t *testing.T) {
+	item := filterTestItem("In progress", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{filter: `status:"in progress"`, want: true},
+		{filter: `status:"In progress" "start date":2024-01-01`, want: true},
+		{filter: `status:Todo,"In progress"`, want: true},
+		{filter: `status:Done`, want: false},
+		{filter: `-status:Done`, want: true},
+		{filter: `status:"In progress" "Start date":2024-01-02`, want: false},
+		{filter: `iteration:"Sprint 1"`, want: false},
+		{filter: `sprint:"sprint 2"`, want: true},
+		{filter: `-sprint:"Sprint 1"`, want: true},
+		{filter: `assignees:bob`, want: true},
+		{filter: `assignees:carol`, want: false},
+		{filter: `label:Bug`, want: true},
+		{filter: `label:docs,frontend`, want: true},
+		{filter: `label:docs`, want: false},
+		{filter: `status:"In progress" -label:wontfix`, want: true},
+		{filter: `-label:bug`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := parseItemFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, filter.matches(item))
+		})
+	}
+}
+
+func TestGetProjectIssuesFiltered(t *testing.T) {
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		return `{"data":{"node":{"id":"project","fields":{"nodes":[]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status"},"name":"Todo"}
+			]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/1","title":"One","labels":{"nodes":[{"name":"bug"}]}}},
+			{"id":"item_2","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status"},"name":"Todo"}
+			]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/2","title":"Two","labels":{"nodes":[]}}},
+			{"id":"item_3","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/3","title":"Three","labels":{"nodes":[{"name":"bug"}]}}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	issues, err := c.GetProjectIssuesFiltered(context.Background(), "project", "status:Todo label:bug")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, issues)
+
+	_, err = c.GetProjectIssuesFiltered(context.Background(), "project", "priority:high")
+	assert.NoError(t, err, "expected qualifiers of unknown fields to be accepted")
+
+	_, err = c.GetProjectIssuesFiltered(context.Background(), "project", "is:open")
+	assert.EqualError(t, err, `invalid filter: filter qualifier "is" is not supported (expected a field or label)`)
+
+	_, err = c.GetProjectIssuesFiltered(context.Background(), "project", "bug")
+	assert.EqualError(t, err, `invalid filter: invalid filter qualifier "bug" (expected field:value)`)
+}
@@ -2,23 +2,71 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/util"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 )
 
 type GraphQLClient struct {
-	client *githubv4.Client
-	cache  struct {
+	client           *githubv4.Client
+	host             string
+	retry            RetryConfig
+	respectRateLimit bool
+	rateLimit        github.RateLimitStatus
+	pageSize         int
+	sourcePageSize   int
+	targetPageSize   int
+	noCache          bool
+	serverFilter     string
+	createOptions    bool
+	createLabels     bool
+	itemPollInterval time.Duration
+	logStyle         string
+	itemWaitTimeout  time.Duration
+	onDuplicate      string
+	duplicateIssues  int
+	apiCalls         int
+	maxAPICalls      int
+	includeDrafts    bool
+	includePRs       bool
+	includeArchived  bool
+	journal          *Journal
+	normalizeSelect  bool
+	scopes           *scopeTransport
+	limiter          *mutationLimiter
+	costs            *costTracker
+
+	// optionsMu serializes the creation of single select options
+	optionsMu sync.Mutex
+
+	// mu guards the cache, rate limit status, duplicate count and API call count, which are shared between concurrent calls
+	mu    sync.RWMutex
+	cache struct {
 		sourceProject *ProjectV2
 		targetProject *ProjectV2
-		sourceNumber  int
-		targetNumber  int
+		// sourceDuplicates counts the duplicate items dropped from the source project
+		sourceDuplicates int
+		targetOptions    optionIndex
+		// items indexes the items of the cached projects by issue URL
+		items        map[*ProjectV2]itemIndex
+		issueTitles  map[string]string
+		sourceNumber int
+		targetNumber int
+		// projectIDs maps owner type, login and number of resolved projects to their ID
+		projectIDs map[string]string
+		// repositoryLabels maps repository IDs to their labels by lowercased name
+		repositoryLabels map[string]map[string]issueLabel
 	}
 }
 
@@ -26,41 +74,200 @@ type GithubDate struct {
 	time.Time
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface
+// UnmarshalJSON implements the json.Unmarshaler interface. Dates are usually returned as
+// YYYY-MM-DD, but date-times in RFC 3339 format are accepted as well and truncated to their
+// date. A null date leaves the zero time.
 func (d *GithubDate) UnmarshalJSON(data []byte) error {
-	// Remove quotes
-	s := string(data)
-	s = s[1 : len(s)-1]
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid date %s: %w", data, err)
+	}
 
-	// Parse date in YYYY-MM-DD format
 	t, err := time.Parse("2006-01-02", s)
 	if err != nil {
-		return err
+		datetime, rfcErr := time.Parse(time.RFC3339, s)
+		if rfcErr != nil {
+			return fmt.Errorf("invalid date %q (expected YYYY-MM-DD or RFC 3339)", s)
+		}
+		t = time.Date(datetime.Year(), datetime.Month(), datetime.Day(), 0, 0, 0, 0, time.UTC)
 	}
 
 	d.Time = t
 	return nil
 }
 
-func NewGraphQLClient(verbose bool) (*GraphQLClient, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN environment variable not set")
+// Options configures a GraphQLClient
+type Options struct {
+	// Verbose enables dumping of HTTP traffic
+	Verbose bool
+	// Retry controls how transient failures are retried
+	Retry RetryConfig
+	// RespectRateLimit pauses requests until the rate limit resets once the budget runs low
+	RespectRateLimit bool
+	// PageSize is the number of items fetched per page, unless set per project below
+	PageSize int
+	// SourcePageSize is the number of items fetched per page from the source project
+	SourcePageSize int
+	// TargetPageSize is the number of items fetched per page from the target project
+	TargetPageSize int
+	// NoCache always fetches fresh project data instead of using the in-memory cache
+	NoCache bool
+	// ServerFilter is a project filter expression applied to the items of the source project
+	ServerFilter string
+	// CreateMissingOptions creates single select options missing in the target field instead of failing
+	CreateMissingOptions bool
+	// CreateMissingLabels creates labels missing in the repository of an issue instead of skipping them
+	CreateMissingLabels bool
+	// Host is the GitHub Enterprise Server host to connect to, defaulting to github.com
+	Host string
+	// LogStyle is the format of field update logs, LogStyleStructured unless set
+	LogStyle string
+	// IncludeDrafts treats draft issues like issues, identified by the synthetic URL
+	// DraftIssueURL returns for their title
+	IncludeDrafts bool
+	// IncludePullRequests treats pull requests like issues, identified by their URL
+	IncludePullRequests bool
+	// IncludeArchived keeps archived items in the issues of GetProjectFieldConfigsAndIssues,
+	// which are skipped unless set
+	IncludeArchived bool
+	// Journal records every field change made by UpdateProjectField, if set
+	Journal *Journal
+	// NormalizeSelect compares single select values and resolves their options ignoring
+	// leading emoji and differences in whitespace
+	NormalizeSelect bool
+	// OnDuplicate is how issues appearing more than once in a project are handled,
+	// OnDuplicateFirst unless set
+	OnDuplicate string
+	// MinRequestInterval is the minimum time between two mutations, with up to half of it
+	// added as jitter. Mutations are not spaced out unless set.
+	MinRequestInterval time.Duration
+	// MaxAPICalls is the maximum number of requests sent to the GitHub API, including
+	// retries. Requests beyond it fail with ErrAPIBudgetExhausted. Unlimited unless positive.
+	MaxAPICalls int
+	// LogCost selects the cost of every query, logs it at debug level and sums it up for
+	// QueryCost. Queries are sent without the extra selection unless set.
+	LogCost bool
+	// Transport sends the HTTP requests, defaulting to http.DefaultTransport. Use a
+	// RoundTripFunc to serve canned responses.
+	Transport http.RoundTripper
+}
+
+const (
+	// LogStyleStructured logs field updates with separate old and new attributes
+	LogStyleStructured = "structured"
+	// LogStyleCompact logs field updates as a single "field: old → new" message
+	LogStyleCompact = "compact"
+)
+
+// DefaultPageSize is the number of project items fetched per page unless configured otherwise
+const DefaultPageSize = 100
+
+// MaxPageSize is the largest number of items the GitHub API returns per page
+const MaxPageSize = 100
+
+// pageSizeOrDefault returns the given page size, or the fallback if unset. Page sizes above
+// the maximum of the API are capped.
+func pageSizeOrDefault(pageSize int, fallback int) int {
+	if pageSize <= 0 {
+		pageSize = fallback
+	}
+	if pageSize <= 0 {
+		return DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		slog.Warn("page size exceeds the maximum of the GitHub API, using the maximum", "page_size", pageSize, "max", MaxPageSize)
+		return MaxPageSize
+	}
+	return pageSize
+}
+
+// NewGraphQLClient creates a client authenticating with the tokens of the given source, such
+// as a StaticToken or an AppTokenSource
+func NewGraphQLClient(tokens oauth2.TokenSource, opts Options) (*GraphQLClient, error) {
+	if tokens == nil {
+		return nil, fmt.Errorf("no GitHub token source")
 	}
 
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
+	switch opts.LogStyle {
+	case "", LogStyleStructured, LogStyleCompact:
+	default:
+		return nil, fmt.Errorf("invalid log style %q (expected %s or %s)", opts.LogStyle, LogStyleStructured, LogStyleCompact)
+	}
+
+	onDuplicate := opts.OnDuplicate
+	if onDuplicate == "" {
+		onDuplicate = OnDuplicateFirst
+	}
+	if err := validateOnDuplicate(onDuplicate); err != nil {
+		return nil, err
+	}
 
-	if verbose {
+	scopes := &scopeTransport{transport: opts.Transport}
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: tokens,
+			Base:   scopes,
+		},
+	}
+
+	var costs *costTracker
+	if opts.LogCost {
+		costs = &costTracker{}
+		httpClient.Transport = &costTransport{transport: httpClient.Transport, tracker: costs}
+	}
+
+	httpClient.Transport = &partialResponseTransport{transport: httpClient.Transport}
+
+	if opts.Verbose {
 		httpClient.Transport = &debugTransport{
 			transport: httpClient.Transport,
 		}
 	}
 
+	host := opts.Host
+	if host == "" {
+		host = github.DefaultHost
+	}
+
+	// An endpoint given by the environment, such as a proxy, takes precedence over the host
+	gqlClient := githubv4.NewClient(httpClient)
+	if endpoint := os.Getenv(GraphQLURLEnv); endpoint != "" {
+		if err := validateGraphQLURL(endpoint); err != nil {
+			return nil, err
+		}
+		slog.Debug("using GraphQL endpoint from the environment", "url", endpoint)
+		gqlClient = githubv4.NewEnterpriseClient(endpoint, httpClient)
+	} else if host != github.DefaultHost {
+		gqlClient = githubv4.NewEnterpriseClient(enterpriseGraphQLURL(host), httpClient)
+	}
+
 	client := &GraphQLClient{
-		client: githubv4.NewClient(httpClient),
+		client:           gqlClient,
+		host:             host,
+		retry:            opts.Retry,
+		respectRateLimit: opts.RespectRateLimit,
+		pageSize:         pageSizeOrDefault(opts.PageSize, DefaultPageSize),
+		sourcePageSize:   pageSizeOrDefault(opts.SourcePageSize, opts.PageSize),
+		targetPageSize:   pageSizeOrDefault(opts.TargetPageSize, opts.PageSize),
+		noCache:          opts.NoCache,
+		serverFilter:     opts.ServerFilter,
+		createOptions:    opts.CreateMissingOptions,
+		createLabels:     opts.CreateMissingLabels,
+		logStyle:         opts.LogStyle,
+		onDuplicate:      onDuplicate,
+		includeDrafts:    opts.IncludeDrafts,
+		includePRs:       opts.IncludePullRequests,
+		includeArchived:  opts.IncludeArchived,
+		journal:          opts.Journal,
+		normalizeSelect:  opts.NormalizeSelect,
+		scopes:           scopes,
+		limiter:          newMutationLimiter(opts.MinRequestInterval),
+		maxAPICalls:      opts.MaxAPICalls,
+		costs:            costs,
 	}
 	return client, nil
 }
@@ -83,9 +290,22 @@ type (
 	ProjectV2FieldConfiguration struct {
 		TypeName  string `graphql:"__typename"`
 		DateField struct {
-			ID   string
-			Name string
+			ID       string
+			Name     string
+			DataType string
 		} `graphql:"... on ProjectV2Field"`
+		IterationField struct {
+			ID            string
+			Name          string
+			Configuration struct {
+				Iterations []struct {
+					ID        string
+					Title     string
+					StartDate string
+					Duration  int
+				}
+			}
+		} `graphql:"... on ProjectV2IterationField"`
 		SingleSelectField struct {
 			ID      string
 			Name    string
@@ -97,19 +317,37 @@ type (
 	}
 
 	ProjectV2Item struct {
-		ID     string
-		Fields struct {
+		ID         string
+		IsArchived bool
+		Fields     struct {
 			Nodes []ProjectV2ItemFieldValue
 		} `graphql:"fieldValues(first: 100)"`
 		Content struct {
-			TypeName string `graphql:"__typename"`
-			Issue    struct {
-				URL   string
-				Title string
-			} `graphql:"... on Issue"`
+			TypeName    string             `graphql:"__typename"`
+			Issue       ProjectV2ItemIssue `graphql:"... on Issue"`
+			PullRequest ProjectV2ItemIssue `graphql:"... on PullRequest"`
+			DraftIssue  struct {
+				Title     string
+				UpdatedAt githubv4.DateTime
+			} `graphql:"... on DraftIssue"`
 		}
 	}
 
+	// ProjectV2ItemIssue is the issue or pull request behind a project item
+	ProjectV2ItemIssue struct {
+		ID        string
+		URL       string
+		Title     string
+		UpdatedAt githubv4.DateTime
+		// State is OPEN or CLOSED, or MERGED for pull requests
+		State  string
+		Labels struct {
+			Nodes []struct {
+				Name string
+			}
+		} `graphql:"labels(first: 20)"`
+	}
+
 	ProjectV2ItemFieldValue struct {
 		TypeName  string `graphql:"__typename"`
 		DateValue struct {
@@ -130,8 +368,86 @@ type (
 					Name string
 				} `graphql:"... on ProjectV2SingleSelectField"`
 			}
-			Name *string
+			Name     *string
+			OptionID string `graphql:"optionId"`
 		} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+		NumberValue struct {
+			Field struct {
+				TypeName     string `graphql:"__typename"`
+				ProjectField struct {
+					ID   string
+					Name string
+				} `graphql:"... on ProjectV2Field"`
+			}
+			Number *float64
+		} `graphql:"... on ProjectV2ItemFieldNumberValue"`
+		TextValue struct {
+			Field struct {
+				TypeName     string `graphql:"__typename"`
+				ProjectField struct {
+					ID   string
+					Name string
+				} `graphql:"... on ProjectV2Field"`
+			}
+			Text *string
+		} `graphql:"... on ProjectV2ItemFieldTextValue"`
+		IterationValue struct {
+			Field struct {
+				TypeName       string `graphql:"__typename"`
+				IterationField struct {
+					ID   string
+					Name string
+				} `graphql:"... on ProjectV2IterationField"`
+			}
+			Title       string
+			IterationID string `graphql:"iterationId"`
+		} `graphql:"... on ProjectV2ItemFieldIterationValue"`
+		UserValue      ProjectV2ItemFieldUserValue      `graphql:"... on ProjectV2ItemFieldUserValue"`
+		MilestoneValue ProjectV2ItemFieldMilestoneValue `graphql:"... on ProjectV2ItemFieldMilestoneValue"`
+		LabelValue     ProjectV2ItemFieldLabelValue     `graphql:"... on ProjectV2ItemFieldLabelValue"`
+	}
+
+	ProjectV2ItemFieldUserValue struct {
+		Field struct {
+			TypeName     string `graphql:"__typename"`
+			ProjectField struct {
+				ID   string
+				Name string
+			} `graphql:"... on ProjectV2Field"`
+		}
+		Users struct {
+			Nodes []struct {
+				Login string
+			}
+		} `graphql:"users(first: 20)"`
+	}
+
+	ProjectV2ItemFieldMilestoneValue struct {
+		Field struct {
+			TypeName     string `graphql:"__typename"`
+			ProjectField struct {
+				ID   string
+				Name string
+			} `graphql:"... on ProjectV2Field"`
+		}
+		Milestone *struct {
+			Title string
+		}
+	}
+
+	ProjectV2ItemFieldLabelValue struct {
+		Field struct {
+			TypeName     string `graphql:"__typename"`
+			ProjectField struct {
+				ID   string
+				Name string
+			} `graphql:"... on ProjectV2Field"`
+		}
+		Labels struct {
+			Nodes []struct {
+				Name string
+			}
+		} `graphql:"labels(first: 20)"`
 	}
 )
 
@@ -155,9 +471,12 @@ func (c *GraphQLClient) getOrgProject(ctx context.Context, orgName string, proje
 		"projectNumber": githubv4.Int(projectNumber),
 	}
 
-	if err := c.client.Query(ctx, &query, variables); err != nil {
+	if err := c.queryWithRetry(ctx, &query, variables); err != nil {
 		return nil, fmt.Errorf("failed to query organization project: %w", err)
 	}
+	if query.Organization.Project.ID == "" {
+		return nil, &NotFoundError{Kind: ErrProjectNotFound, Name: fmt.Sprintf("orgs/%s/projects/%d", orgName, projectNumber)}
+	}
 
 	return &ProjectV2{ID: query.Organization.Project.ID}, nil
 }
@@ -182,13 +501,46 @@ func (c *GraphQLClient) getUserProject(ctx context.Context, username string, pro
 		"projectNumber": githubv4.Int(projectNumber),
 	}
 
-	if err := c.client.Query(ctx, &query, variables); err != nil {
+	if err := c.queryWithRetry(ctx, &query, variables); err != nil {
 		return nil, fmt.Errorf("failed to query user project: %w", err)
 	}
+	if query.User.Project.ID == "" {
+		return nil, &NotFoundError{Kind: ErrProjectNotFound, Name: fmt.Sprintf("users/%s/projects/%d", username, projectNumber)}
+	}
 
 	return &ProjectV2{ID: query.User.Project.ID}, nil
 }
 
+// enterpriseGraphQLURL returns the GraphQL endpoint of a GitHub Enterprise Server host
+func enterpriseGraphQLURL(host string) string {
+	return "https://" + host + "/api/graphql"
+}
+
+// GraphQLURLEnv is the environment variable overriding the GraphQL endpoint of the client
+const GraphQLURLEnv = "GITHUB_GRAPHQL_URL"
+
+// validateGraphQLURL checks that a GraphQL endpoint is an absolute http or https URL
+func validateGraphQLURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid %s %q (expected an http or https URL such as https://github.example.com/api/graphql)", GraphQLURLEnv, endpoint)
+	}
+	return nil
+}
+
+// Host implements the Client interface
+func (c *GraphQLClient) Host() string {
+	if c.host == "" {
+		return github.DefaultHost
+	}
+	return c.host
+}
+
+// ServerFilter implements the Client interface
+func (c *GraphQLClient) ServerFilter() string {
+	return c.serverFilter
+}
+
 // GetProjectFields implements the Client interface
 func (c *GraphQLClient) GetProjectFields(ctx context.Context, projectID string, issueURL string) ([]github.ProjectField, error) {
 	var query struct {
@@ -201,7 +553,7 @@ func (c *GraphQLClient) GetProjectFields(ctx context.Context, projectID string,
 		"projectID": githubv4.ID(projectID),
 	}
 
-	if err := c.client.Query(ctx, &query, variables); err != nil {
+	if err := c.queryWithRetry(ctx, &query, variables); err != nil {
 		return nil, fmt.Errorf("failed to query project: %w", err)
 	}
 
@@ -209,87 +561,78 @@ func (c *GraphQLClient) GetProjectFields(ctx context.Context, projectID string,
 
 	// Find the item (issue) in the project
 	var targetItem *ProjectV2Item
-	for _, item := range project.Items.Nodes {
-		if item.Content.TypeName == "Issue" && item.Content.Issue.URL == issueURL {
-			targetItem = &item
+	for i := range project.Items.Nodes {
+		if project.Items.Nodes[i].hasIssueURL(issueURL) {
+			targetItem = &project.Items.Nodes[i]
 			break
 		}
 	}
 
 	if targetItem == nil {
-		return nil, fmt.Errorf("issue %s not found in project", issueURL)
+		return nil, issueNotFound(issueURL)
 	}
 
-	// Convert field values to our internal format
-	var fields []github.ProjectField
-	for _, fieldValue := range targetItem.Fields.Nodes {
-		var field github.ProjectField
-
-		switch fieldValue.TypeName {
-		case "ProjectV2ItemFieldDateValue":
-			field = github.ProjectField{
-				ID:   fieldValue.DateValue.Field.DateField.ID,
-				Name: fieldValue.DateValue.Field.DateField.Name,
-				Value: github.ProjectFieldValue{
-					Date: &fieldValue.DateValue.Date.Time,
-				},
-			}
-		case "ProjectV2ItemFieldSingleSelectValue":
-			field = github.ProjectField{
-				ID:   fieldValue.SingleSelectValue.Field.SingleSelectField.ID,
-				Name: fieldValue.SingleSelectValue.Field.SingleSelectField.Name,
-				Value: github.ProjectFieldValue{
-					Text: fieldValue.SingleSelectValue.Name,
-				},
-			}
-		}
+	return toProjectFields(targetItem), nil
+}
 
-		if field.ID != "" { // Only add if we handled this field type
-			fields = append(fields, field)
-		}
+// findProjectItem finds the item of an issue in a project and the current value of a field,
+// matched by its ID if given and else by its name. The value points into the project, not
+// at a copy.
+func (c *GraphQLClient) findProjectItem(project *ProjectV2, issueURL string, field github.ProjectField) (string, *ProjectV2ItemFieldValue, error) {
+	item := c.findItem(project, issueURL)
+	if item == nil {
+		return "", nil, issueNotFound(issueURL)
 	}
 
-	return fields, nil
-}
-
-// findProjectItem finds an item in a project by its issue URL and field name
-func (c *GraphQLClient) findProjectItem(project *ProjectV2, issueURL string, fieldName string) (string, *ProjectV2ItemFieldValue, error) {
-	for _, item := range project.Items.Nodes {
-		if item.Content.TypeName == "Issue" && item.Content.Issue.URL == issueURL {
-			// Find current value of the field we want to update
-			for _, fieldValue := range item.Fields.Nodes {
-				switch fieldValue.TypeName {
-				case "ProjectV2ItemFieldDateValue":
-					if fieldValue.DateValue.Field.DateField.Name == fieldName {
-						return item.ID, &fieldValue, nil
-					}
-				case "ProjectV2ItemFieldSingleSelectValue":
-					if fieldValue.SingleSelectValue.Field.SingleSelectField.Name == fieldName {
-						return item.ID, &fieldValue, nil
-					}
-				}
-			}
-			return item.ID, nil, nil
+	// Find current value of the field we want to update
+	for j := range item.Fields.Nodes {
+		if item.Fields.Nodes[j].matchesField(field) {
+			return item.ID, &item.Fields.Nodes[j], nil
 		}
 	}
-	return "", nil, fmt.Errorf("issue %s not found in project", issueURL)
+	return item.ID, nil, nil
 }
 
-// findProjectField finds a field configuration in a project by its name
-func (c *GraphQLClient) findProjectField(project *ProjectV2, fieldName string) (string, bool, error) {
+// findProjectField finds a field configuration in a project by its ID if given, and else by
+// its name. Fields are matched by ID where possible, as several fields may share a name.
+func (c *GraphQLClient) findProjectField(project *ProjectV2, field github.ProjectField) (string, bool, error) {
 	for _, f := range project.Fields.Nodes {
 		switch f.TypeName {
 		case "ProjectV2Field":
-			if f.DateField.Name == fieldName {
+			if matchesField(field, f.DateField.ID, f.DateField.Name) {
 				return f.DateField.ID, true, nil
 			}
 		case "ProjectV2SingleSelectField":
-			if f.SingleSelectField.Name == fieldName {
+			if matchesField(field, f.SingleSelectField.ID, f.SingleSelectField.Name) {
 				return f.SingleSelectField.ID, false, nil
 			}
+		case "ProjectV2IterationField":
+			if matchesField(field, f.IterationField.ID, f.IterationField.Name) {
+				return f.IterationField.ID, false, nil
+			}
+		}
+	}
+	return "", false, &NotFoundError{Kind: ErrFieldNotFound, Name: field.Name}
+}
+
+// projectFieldDataType returns the data type of the field with the given ID, such as DATE,
+// SINGLE_SELECT or ITERATION, or an empty string if unknown. The caller must hold the lock.
+func projectFieldDataType(project *ProjectV2, fieldID string) string {
+	for _, f := range project.Fields.Nodes {
+		if config := toFieldConfig(f); config.ID == fieldID {
+			return config.DataType
 		}
 	}
-	return "", false, fmt.Errorf("field %s not found in project", fieldName)
+	return ""
+}
+
+// matchesField checks if a field with the given ID and name is the wanted field, comparing
+// IDs if the wanted field has one and names otherwise
+func matchesField(field github.ProjectField, id, name string) bool {
+	if field.ID != "" {
+		return field.ID == id
+	}
+	return field.Name == name
 }
 
 // valuesEqual checks if the current field value equals the new value
@@ -304,50 +647,108 @@ func (c *GraphQLClient) valuesEqual(currentValue *ProjectV2ItemFieldValue, field
 			return currentValue.DateValue.Date.Time.Equal(*field.Value.Date)
 		}
 	case "ProjectV2ItemFieldSingleSelectValue":
+		// Options sharing a name are only told apart by their IDs
+		if field.Value.OptionID != "" && currentValue.SingleSelectValue.OptionID != "" {
+			return currentValue.SingleSelectValue.OptionID == field.Value.OptionID
+		}
 		if currentValue.SingleSelectValue.Name != nil && field.Value.Text != nil {
-			return *currentValue.SingleSelectValue.Name == *field.Value.Text
+			return c.sameOption(*currentValue.SingleSelectValue.Name, *field.Value.Text)
 		}
+	case "ProjectV2ItemFieldNumberValue", "ProjectV2ItemFieldTextValue", "ProjectV2ItemFieldIterationValue":
+		return plainValuesEqual(currentValue, field.Value)
+	default:
+		return issueValuesEqual(currentValue, field.Value)
 	}
 	return false
 }
 
+// plainValuesEqual checks if the current value of a number, text or iteration field equals
+// the new value. Iterations are compared by ID if the new value has one, and else by title.
+func plainValuesEqual(currentValue *ProjectV2ItemFieldValue, value github.ProjectFieldValue) bool {
+	switch currentValue.TypeName {
+	case "ProjectV2ItemFieldNumberValue":
+		return currentValue.NumberValue.Number != nil && value.Number != nil &&
+			*currentValue.NumberValue.Number == *value.Number
+	case "ProjectV2ItemFieldTextValue":
+		return currentValue.TextValue.Text != nil && value.Text != nil &&
+			*currentValue.TextValue.Text == *value.Text
+	case "ProjectV2ItemFieldIterationValue":
+		if value.IterationID != "" {
+			return currentValue.IterationValue.IterationID == value.IterationID
+		}
+		return value.Iteration != nil && currentValue.IterationValue.Title == *value.Iteration
+	default:
+		return false
+	}
+}
+
+// issueValuesEqual checks if the current value of a field reflecting the issue itself, such
+// as its assignees, milestone or labels, equals the new value
+func issueValuesEqual(currentValue *ProjectV2ItemFieldValue, value github.ProjectFieldValue) bool {
+	switch currentValue.TypeName {
+	case "ProjectV2ItemFieldUserValue":
+		return value.Users != nil && github.SameLogins(currentValue.UserValue.logins(), value.Users)
+	case "ProjectV2ItemFieldMilestoneValue":
+		return currentValue.MilestoneValue.Milestone != nil && value.Milestone != nil &&
+			currentValue.MilestoneValue.Milestone.Title == *value.Milestone
+	case "ProjectV2ItemFieldLabelValue":
+		return value.Labels != nil && github.SameLabels(currentValue.LabelValue.names(), value.Labels)
+	default:
+		return false
+	}
+}
+
 // constructMutationInput creates the input for the update mutation based on field type
-func (c *GraphQLClient) constructMutationInput(projectID, itemID, fieldID string, field github.ProjectField, isDateField bool) (githubv4.UpdateProjectV2ItemFieldValueInput, error) {
+func (c *GraphQLClient) constructMutationInput(project *ProjectV2, itemID, fieldID string, field github.ProjectField, isDateField bool) (githubv4.UpdateProjectV2ItemFieldValueInput, error) {
 	input := githubv4.UpdateProjectV2ItemFieldValueInput{
-		ProjectID: projectID,
+		ProjectID: project.ID,
 		ItemID:    itemID,
 		FieldID:   fieldID,
 	}
 
 	switch {
+	case field.Value.IterationID != "":
+		iterationID := githubv4.String(field.Value.IterationID)
+		input.Value = githubv4.ProjectV2FieldValue{IterationID: &iterationID}
 	case isDateField && field.Value.Date != nil:
+		slog.Debug("setting date value",
+			"project_id", project.ID,
+			"item_id", itemID,
+			"field_id", fieldID,
+		)
 		date := githubv4.Date{Time: *field.Value.Date}
 		input.Value = githubv4.ProjectV2FieldValue{Date: &date}
+	case isDateField && field.Value.Number != nil:
+		// Number fields are plain fields like date fields
+		number := githubv4.Float(*field.Value.Number)
+		input.Value = githubv4.ProjectV2FieldValue{Number: &number}
+	case isDateField && field.Value.Text != nil:
+		// So are text fields, whose values are written as they are
+		text := githubv4.String(*field.Value.Text)
+		input.Value = githubv4.ProjectV2FieldValue{Text: &text}
+	case !isDateField && field.Value.OptionID != "":
+		// The option was chosen by ID, so its name is not looked up
+		optionID := githubv4.String(field.Value.OptionID)
+		input.Value = githubv4.ProjectV2FieldValue{SingleSelectOptionID: &optionID}
 	case !isDateField && field.Value.Text != nil:
 		// Find the option ID for the single select value in the target project
-		var optionID string
-		var project *ProjectV2
-		if c.cache.targetProject != nil && c.cache.targetProject.ID == projectID {
-			project = c.cache.targetProject
-		}
-		if project == nil {
-			return input, fmt.Errorf("target project not found in cache")
-		}
-
-		for _, f := range project.Fields.Nodes {
-			if f.TypeName == "ProjectV2SingleSelectField" && f.SingleSelectField.Name == field.Name {
-				for _, opt := range f.SingleSelectField.Options {
-					if opt.Name == *field.Value.Text {
-						optionID = opt.ID
-						break
-					}
-				}
-				break
-			}
-		}
+		optionID := c.optionsFor(project).lookup(fieldID, *field.Value.Text, c.normalizeSelect)
 		if optionID == "" {
-			return input, fmt.Errorf("single select option %q not found in target field %q", *field.Value.Text, field.Name)
+			slog.Debug("single select option not found",
+				"project_id", project.ID,
+				"item_id", itemID,
+				"field_id", fieldID,
+				"option", *field.Value.Text,
+			)
+			return input, &NotFoundError{Kind: ErrOptionNotFound, Name: *field.Value.Text, Field: field.Name}
 		}
+		slog.Debug("resolved single select option",
+			"project_id", project.ID,
+			"item_id", itemID,
+			"field_id", fieldID,
+			"option", *field.Value.Text,
+			"option_id", optionID,
+		)
 		optionIDv4 := githubv4.String(optionID)
 		input.Value = githubv4.ProjectV2FieldValue{SingleSelectOptionID: &optionIDv4}
 	default:
@@ -357,29 +758,95 @@ func (c *GraphQLClient) constructMutationInput(projectID, itemID, fieldID string
 	return input, nil
 }
 
+// optionIndex maps single select field IDs to their option names and IDs. Fields are
+// indexed by ID, as several fields may share a name.
+type optionIndex map[string]map[string]string
+
+// buildOptionIndex indexes the single select options of all fields in a project
+func buildOptionIndex(project *ProjectV2) optionIndex {
+	index := make(optionIndex)
+	for _, f := range project.Fields.Nodes {
+		if f.TypeName != "ProjectV2SingleSelectField" {
+			continue
+		}
+		options := make(map[string]string, len(f.SingleSelectField.Options))
+		for _, opt := range f.SingleSelectField.Options {
+			if _, ok := options[opt.Name]; !ok {
+				options[opt.Name] = opt.ID
+			}
+		}
+		index[f.SingleSelectField.ID] = options
+	}
+	return index
+}
+
+// optionsFor returns the single select option index of a project, reusing the index
+// of the cached target project
+func (c *GraphQLClient) optionsFor(project *ProjectV2) optionIndex {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if project == c.cache.targetProject && c.cache.targetOptions != nil {
+		return c.cache.targetOptions
+	}
+	return buildOptionIndex(project)
+}
+
+// lookup returns the ID of the named option of a single select field, or an empty string.
+// If normalize is set and no option has the exact name, an option whose normalized name
+// matches is used, the first by name if there are several.
+func (idx optionIndex) lookup(fieldID, optionName string, normalize bool) string {
+	if id, ok := idx[fieldID][optionName]; ok || !normalize {
+		return id
+	}
+
+	normalized := github.NormalizeOptionName(optionName)
+	var match string
+	for name := range idx[fieldID] {
+		if github.NormalizeOptionName(name) == normalized && (match == "" || name < match) {
+			match = name
+		}
+	}
+	if match == "" {
+		return ""
+	}
+	return idx[fieldID][match]
+}
+
+// sameOption checks if two single select values name the same option
+func (c *GraphQLClient) sameOption(a, b string) bool {
+	if c.normalizeSelect {
+		return github.NormalizeOptionName(a) == github.NormalizeOptionName(b)
+	}
+	return a == b
+}
+
 // updateCacheFieldValue updates the cached field value after a successful mutation
 func (c *GraphQLClient) updateCacheFieldValue(project *ProjectV2, issueURL string, field github.ProjectField) {
-	for i, item := range project.Items.Nodes {
-		if item.Content.TypeName == "Issue" && item.Content.Issue.URL == issueURL {
-			for j, fieldValue := range item.Fields.Nodes {
-				switch fieldValue.TypeName {
-				case "ProjectV2ItemFieldDateValue":
-					if fieldValue.DateValue.Field.DateField.Name == field.Name {
-						project.Items.Nodes[i].Fields.Nodes[j].DateValue.Date = &GithubDate{Time: *field.Value.Date}
-					}
-				case "ProjectV2ItemFieldSingleSelectValue":
-					if fieldValue.SingleSelectValue.Field.SingleSelectField.Name == field.Name {
-						project.Items.Nodes[i].Fields.Nodes[j].SingleSelectValue.Name = field.Value.Text
-					}
-				}
-			}
-			break
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := c.findItem(project, issueURL)
+	if item == nil {
+		return
+	}
+	for j := range item.Fields.Nodes {
+		value := &item.Fields.Nodes[j]
+		if value.matchesField(field) {
+			value.setValue(field.Value)
 		}
 	}
 }
 
-// getProjectFromCache retrieves a project from cache if available
+// getProjectFromCache retrieves a project from cache if available and caching is enabled
 func (c *GraphQLClient) getProjectFromCache(projectID string) *ProjectV2 {
+	if c.noCache {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if c.cache.sourceProject != nil && c.cache.sourceProject.ID == projectID {
 		return c.cache.sourceProject
 	}
@@ -389,46 +856,49 @@ func (c *GraphQLClient) getProjectFromCache(projectID string) *ProjectV2 {
 	return nil
 }
 
-// fetchProject fetches a project by ID
-func (c *GraphQLClient) fetchProject(ctx context.Context, projectID string) (*ProjectV2, error) {
-	var query struct {
-		Node struct {
-			Project ProjectV2 `graphql:"... on ProjectV2"`
-		} `graphql:"node(id: $projectID)"`
-	}
-
-	variables := map[string]interface{}{
-		"projectID": githubv4.ID(projectID),
+// getProject returns a project from the cache, falling back to fetching it if not cached
+func (c *GraphQLClient) getProject(ctx context.Context, projectID string) (*ProjectV2, error) {
+	if project := c.getProjectFromCache(projectID); project != nil {
+		return project, nil
 	}
+	return c.fetchProject(ctx, projectID)
+}
 
-	if err := c.client.Query(ctx, &query, variables); err != nil {
+// fetchProject fetches a project by ID with all of its items, so that items beyond the
+// first page are found when the project is not cached
+func (c *GraphQLClient) fetchProject(ctx context.Context, projectID string) (*ProjectV2, error) {
+	slog.Debug("project not cached, loading all of its items", "project_id", projectID)
+	project, _, err := c.paginateProjectItems(ctx, projectID, pageSizeOrDefault(c.pageSize, DefaultPageSize), "")
+	if err != nil {
 		return nil, fmt.Errorf("failed to query project: %w", err)
 	}
-
-	return &query.Node.Project, nil
+	return project, nil
 }
 
 // getFieldUpdateValues gets the old and new values for logging
 func (c *GraphQLClient) getFieldUpdateValues(currentValue *ProjectV2ItemFieldValue, field github.ProjectField) (string, string) {
-	var oldValue, newValue string
+	var oldValue string
 	if currentValue != nil {
-		switch currentValue.TypeName {
-		case "ProjectV2ItemFieldDateValue":
-			if currentValue.DateValue.Date != nil {
-				oldValue = currentValue.DateValue.Date.Time.Format("2006-01-02")
-			}
-		case "ProjectV2ItemFieldSingleSelectValue":
-			if currentValue.SingleSelectValue.Name != nil {
-				oldValue = *currentValue.SingleSelectValue.Name
-			}
-		}
+		value, _ := currentValue.value()
+		oldValue = value.String()
 	}
-	if field.Value.Date != nil {
-		newValue = field.Value.Date.Format("2006-01-02")
-	} else if field.Value.Text != nil {
-		newValue = *field.Value.Text
+	return oldValue, field.Value.String()
+}
+
+// logFieldUpdate logs a field update in the configured log style. The given attributes,
+// such as the node IDs involved, are appended to the log record.
+func (c *GraphQLClient) logFieldUpdate(fieldName, oldValue, newValue string, dryRun bool, attrs ...any) {
+	if c.logStyle == LogStyleCompact {
+		slog.Debug(fmt.Sprintf("%s: %s → %s", fieldName, oldValue, newValue), append([]any{"dry_run", dryRun}, attrs...)...)
+		return
 	}
-	return oldValue, newValue
+
+	slog.Debug("updating field value", append([]any{
+		"field", fieldName,
+		"old", oldValue,
+		"new", newValue,
+		"dry_run", dryRun,
+	}, attrs...)...)
 }
 
 // executeFieldUpdate executes the field update mutation
@@ -439,7 +909,7 @@ func (c *GraphQLClient) executeFieldUpdate(ctx context.Context, input githubv4.U
 		} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
 	}
 
-	if err := c.client.Mutate(ctx, &mutation, input, nil); err != nil {
+	if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
 		return fmt.Errorf("failed to update field: %w", err)
 	}
 
@@ -447,336 +917,704 @@ func (c *GraphQLClient) executeFieldUpdate(ctx context.Context, input githubv4.U
 }
 
 // UpdateProjectField implements the Client interface
-func (c *GraphQLClient) UpdateProjectField(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+func (c *GraphQLClient) UpdateProjectField(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) (err error) {
 	// Get project from cache or fetch it
-	project := c.getProjectFromCache(projectID)
-	if project == nil {
-		var err error
-		project, err = c.fetchProject(ctx, projectID)
-		if err != nil {
-			return err
-		}
+	project, err := c.getProject(ctx, projectID)
+	if err != nil {
+		return err
 	}
 
 	// Find the item and its current field value
-	itemID, currentValue, err := c.findProjectItem(project, issueURL, field.Name)
+	c.mu.RLock()
+	itemID, currentValue, err := c.findProjectItem(project, issueURL, field)
+	dataType := fieldDataType(project, field)
+	fieldID, _, _ := c.findProjectField(project, field)
+	c.mu.RUnlock()
 	if err != nil {
 		return err
 	}
 
+	field.Value = valueFor(dataType, currentValue, field.Value)
+
 	// Skip update if values are equal
 	if c.valuesEqual(currentValue, field) {
 		return nil
 	}
 
-	// Find the field configuration
-	fieldID, isDateField, err := c.findProjectField(project, field.Name)
+	// Journal the previous value once the change was made, so that it can be undone
+	if !dryRun && c.journal != nil {
+		oldValue, newValue := c.getFieldUpdateValues(currentValue, field)
+		defer func() {
+			if err != nil {
+				return
+			}
+			err = c.journal.Record(JournalEntry{
+				ProjectID: project.ID,
+				IssueURL:  issueURL,
+				Field:     field.Name,
+				FieldID:   fieldID,
+				OldValue:  oldValue,
+				NewValue:  newValue,
+			})
+		}()
+	}
+
+	switch {
+	// User fields reflect the underlying issue and are set with their own mutations
+	case field.Value.Users != nil:
+		return c.updateUserField(ctx, project, issueURL, currentValue, field, dryRun)
+	// Milestone fields reflect the milestone of the underlying issue
+	case dataType == "MILESTONE":
+		return c.updateMilestoneField(ctx, project, issueURL, currentValue, field, dryRun)
+	// Labels fields reflect the labels of the underlying issue
+	case dataType == "LABELS":
+		return c.updateLabelField(ctx, project, issueURL, currentValue, field, dryRun)
+	default:
+		return c.updateItemField(ctx, project, itemID, issueURL, currentValue, field, dryRun)
+	}
+}
+
+// valueFor converts a value to the value written to a field of the given data type
+func valueFor(dataType string, currentValue *ProjectV2ItemFieldValue, value github.ProjectFieldValue) github.ProjectFieldValue {
+	// Milestones and single select options are both set by name, so the values are interchangeable
+	value = milestoneValueFor(dataType, value)
+	// A single select value adds a label, keeping the other labels of the issue
+	return labelValueFor(dataType, currentValue, value)
+}
+
+// updateItemField sets the value of a field of a project item, which holds the value itself
+// rather than reflecting the underlying issue
+func (c *GraphQLClient) updateItemField(ctx context.Context, project *ProjectV2, itemID, issueURL string, currentValue *ProjectV2ItemFieldValue, field github.ProjectField, dryRun bool) error {
+	fieldID, isDateField, err := c.resolveWriteField(project, field)
 	if err != nil {
 		return err
 	}
 
 	// Log the field update
 	oldValue, newValue := c.getFieldUpdateValues(currentValue, field)
-	slog.Debug("updating field value",
-		"field", field.Name,
-		"old", oldValue,
-		"new", newValue,
-		"dry_run", dryRun,
+	c.logFieldUpdate(field.Name, oldValue, newValue, dryRun,
+		"project_id", project.ID,
+		"item_id", itemID,
+		"field_id", fieldID,
 	)
 
-	if !dryRun {
-		// Construct and execute the mutation
-		input, err := c.constructMutationInput(project.ID, itemID, fieldID, field, isDateField)
-		if err != nil {
+	if dryRun {
+		return nil
+	}
+
+	// Create a missing single select option first, if enabled
+	if !isDateField && c.createOptions && field.Value.Text != nil && field.Value.OptionID == "" && c.optionsFor(project).lookup(fieldID, *field.Value.Text, c.normalizeSelect) == "" {
+		if err := c.CreateSingleSelectOption(ctx, project.ID, fieldID, *field.Value.Text); err != nil {
 			return err
 		}
-
-		if err := c.executeFieldUpdate(ctx, input); err != nil {
+		if project, err = c.getProject(ctx, project.ID); err != nil {
 			return err
 		}
+	}
+
+	// Iterations of other projects are chosen by title
+	if err := c.resolveIteration(project, fieldID, &field); err != nil {
+		return err
+	}
 
-		// Update the cache with the new value
-		c.updateCacheFieldValue(project, issueURL, field)
+	// Construct and execute the mutation
+	input, err := c.constructMutationInput(project, itemID, fieldID, field, isDateField)
+	if err != nil {
+		return err
+	}
+	if err := c.executeFieldUpdate(ctx, input); err != nil {
+		return err
 	}
 
+	// Update the cache with the new value
+	c.updateCacheFieldValue(project, issueURL, field)
 	return nil
 }
 
-// GetProjectIssues implements the Client interface
-func (c *GraphQLClient) GetProjectIssues(ctx context.Context, projectID string) ([]string, error) {
-	type projectQuery struct {
-		Project struct {
-			Items struct {
-				Nodes    []ProjectV2Item
-				PageInfo struct {
-					HasNextPage bool
-					EndCursor   string
-				}
-			} `graphql:"items(first: 100, after: $afterCursor)"`
-		} `graphql:"... on ProjectV2"`
+// resolveIteration sets the ID of an iteration value chosen by title only, as when it was
+// read from another project, to the ID of the iteration with that title
+func (c *GraphQLClient) resolveIteration(project *ProjectV2, fieldID string, field *github.ProjectField) error {
+	if field.Value.Iteration == nil || field.Value.IterationID != "" {
+		return nil
 	}
 
-	var query struct {
-		Node projectQuery `graphql:"node(id: $projectID)"`
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, f := range project.Fields.Nodes {
+		config := toFieldConfig(f)
+		if config.ID != fieldID {
+			continue
+		}
+		for _, iteration := range config.Iterations {
+			if iteration.Title == *field.Value.Iteration {
+				field.Value.IterationID = iteration.ID
+				return nil
+			}
+		}
 	}
+	return &NotFoundError{Kind: ErrIterationNotFound, Name: *field.Value.Iteration, Field: field.Name}
+}
 
-	var items []ProjectV2Item
-	var afterCursor *string
-	var page int
+// resolveWriteField finds the ID of the field a value is written to, and whether the value
+// is written as a date, number or text rather than as an option. A declared data type decides how
+// the value is written, as long as it matches the field.
+func (c *GraphQLClient) resolveWriteField(project *ProjectV2, field github.ProjectField) (string, bool, error) {
+	c.mu.RLock()
+	fieldID, isDateField, err := c.findProjectField(project, field)
+	actualType := projectFieldDataType(project, fieldID)
+	c.mu.RUnlock()
+	if err != nil {
+		return "", false, err
+	}
 
-	slog.Info("loading project issues from GitHub")
+	if field.DataType != "" {
+		if actualType != "" && actualType != field.DataType {
+			return "", false, fmt.Errorf("field %s is of type %s, but was declared as %s", field.Name, actualType, field.DataType)
+		}
+		isDateField = field.DataType == "DATE" || field.DataType == "NUMBER" || field.DataType == "TEXT"
+	}
+	return fieldID, isDateField, nil
+}
 
-	// Fetch items with pagination
-	for {
-		page++
-		slog.Debug("loading page of issues", "page", page)
+// DeleteProjectItem implements the Client interface
+func (c *GraphQLClient) DeleteProjectItem(ctx context.Context, projectID string, issueURL string) error {
+	project, err := c.getProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
 
-		variables := map[string]interface{}{
-			"projectID":   githubv4.ID(projectID),
-			"afterCursor": (*githubv4.String)(afterCursor),
-		}
+	c.mu.RLock()
+	itemID, _, err := c.findProjectItem(project, issueURL, github.ProjectField{})
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
 
-		if err := c.client.Query(ctx, &query, variables); err != nil {
-			return nil, fmt.Errorf("failed to query project: %w", err)
-		}
+	var mutation struct {
+		DeleteProjectV2Item struct {
+			DeletedItemID string `graphql:"deletedItemId"`
+		} `graphql:"deleteProjectV2Item(input: $input)"`
+	}
 
-		items = append(items, query.Node.Project.Items.Nodes...)
+	input := githubv4.DeleteProjectV2ItemInput{
+		ProjectID: githubv4.ID(projectID),
+		ItemID:    githubv4.ID(itemID),
+	}
+
+	if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to delete project item: %w", err)
+	}
 
-		if !query.Node.Project.Items.PageInfo.HasNextPage {
+	// Remove the item from the cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, item := range project.Items.Nodes {
+		if item.ID == itemID {
+			project.Items.Nodes = append(project.Items.Nodes[:i], project.Items.Nodes[i+1:]...)
+			c.reindexItems(project)
 			break
 		}
+	}
 
-		cursor := query.Node.Project.Items.PageInfo.EndCursor
-		afterCursor = &cursor
+	return nil
+}
+
+// GetProjectIssuesFiltered implements the Client interface. The filter is applied to the
+// items of the project on the client, using the cached project if it is loaded.
+func (c *GraphQLClient) GetProjectIssuesFiltered(ctx context.Context, projectID string, filter string) ([]string, error) {
+	itemFilter, err := parseItemFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
 	}
 
+	project, err := c.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	var issues []string
-	for _, item := range items {
-		if item.Content.TypeName == "Issue" {
+	for _, item := range itemFilter.apply(project.Items.Nodes) {
+		if item.isIssue() {
 			issues = append(issues, item.Content.Issue.URL)
 		}
 	}
-
-	slog.Info("completed loading project issues", "total_issues", len(issues), "pages_loaded", page)
 	return issues, nil
 }
 
-// GetProjectFieldConfigsAndIssues implements the Client interface
-func (c *GraphQLClient) GetProjectFieldConfigsAndIssues(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
-	type projectQuery struct {
-		Project struct {
-			ID     string
-			Fields struct {
-				Nodes []ProjectV2FieldConfiguration
-			} `graphql:"fields(first: 100)"`
-			Items struct {
-				Nodes    []ProjectV2Item
-				PageInfo struct {
-					HasNextPage bool
-					EndCursor   string
-				}
-			} `graphql:"items(first: 100, after: $afterCursor)"`
-		} `graphql:"... on ProjectV2"`
+// GetProjectIssues implements the Client interface
+func (c *GraphQLClient) GetProjectIssues(ctx context.Context, projectID string) ([]string, error) {
+	slog.Info("loading project issues from GitHub")
+
+	project, pages, err := c.paginateProjectItems(ctx, projectID, pageSizeOrDefault(c.pageSize, DefaultPageSize), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project: %w", err)
 	}
 
-	var query struct {
-		SourceProject projectQuery `graphql:"sourceProject: node(id: $sourceProjectID)"`
-		TargetProject projectQuery `graphql:"targetProject: node(id: $targetProjectID)"`
+	items, duplicates, err := dedupeProjectItems(projectID, project.Items.Nodes, c.onDuplicate)
+	if err != nil {
+		return nil, err
 	}
 
-	// Initialize variables for pagination
-	var sourceItems []ProjectV2Item
-	var targetItems []ProjectV2Item
-	var afterCursor *string
-	var page int
+	var issues []string
+	c.mu.Lock()
+	c.duplicateIssues = duplicates
+	if c.cache.issueTitles == nil {
+		c.cache.issueTitles = make(map[string]string)
+	}
+	for _, item := range items {
+		if item.isIssue() {
+			issues = append(issues, item.Content.Issue.URL)
+			c.cache.issueTitles[util.CanonicalizeIssueURL(item.Content.Issue.URL)] = item.Content.Issue.Title
+		}
+	}
+	c.mu.Unlock()
 
-	slog.Info("loading project data from GitHub")
+	slog.Info("completed loading project issues", "total_issues", len(issues), "pages_loaded", pages)
+	return issues, nil
+}
 
-	// Fetch source project items with pagination
-	for {
-		page++
-		slog.Debug("loading page", "page", page)
+// GetProjectFieldConfigsAndIssues implements the Client interface
+func (c *GraphQLClient) GetProjectFieldConfigsAndIssues(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
+	slog.Info("loading project data from GitHub",
+		"source_project_id", sourceProjectID,
+		"target_project_id", targetProjectID,
+	)
 
-		variables := map[string]interface{}{
-			"sourceProjectID": githubv4.ID(sourceProjectID),
-			"targetProjectID": githubv4.ID(targetProjectID),
-			"afterCursor":     (*githubv4.String)(afterCursor),
+	// Several target projects may be synced from one source project, so the source project
+	// is only loaded again if it changed or caching is disabled
+	c.mu.RLock()
+	sourceProject, sourceDuplicates := c.cache.sourceProject, c.cache.sourceDuplicates
+	c.mu.RUnlock()
+	reuseSource := !c.noCache && sourceProject != nil && sourceProject.ID == sourceProjectID
+
+	// Paginate each project independently so that each stops at its own last page
+	var sourcePages int
+	if reuseSource {
+		slog.Debug("reusing cached source project", "source_project_id", sourceProjectID)
+	} else {
+		sourceProject, sourcePages, err = c.fetchAllProjectItems(ctx, sourceProjectID, c.sourcePageSize, c.serverFilter)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to query source project: %w", err)
 		}
+	}
 
-		if err := c.client.Query(ctx, &query, variables); err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("failed to query projects: %w", err)
+	// When copying fields within a single project, share one copy of the project so
+	// that field, option and cache lookups all see the same data
+	targetProject, targetPages := sourceProject, 0
+	if targetProjectID != sourceProjectID {
+		targetProject, targetPages, err = c.fetchAllProjectItems(ctx, targetProjectID, c.targetPageSize, "")
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to query target project: %w", err)
 		}
+	}
 
-		sourceItems = append(sourceItems, query.SourceProject.Project.Items.Nodes...)
-		targetItems = append(targetItems, query.TargetProject.Project.Items.Nodes...)
-
-		if !query.SourceProject.Project.Items.PageInfo.HasNextPage && !query.TargetProject.Project.Items.PageInfo.HasNextPage {
-			break
+	// Keep a single item per issue, so that reads and updates of an issue agree on its item.
+	// A reused source project was deduplicated when it was loaded.
+	if !reuseSource {
+		sourceDuplicates = 0
+	}
+	duplicates := sourceDuplicates
+	for _, project := range uniqueProjects(sourceProject, targetProject) {
+		if reuseSource && project == sourceProject {
+			continue
 		}
-
-		// Use the end cursor from either project that has more pages
-		if query.SourceProject.Project.Items.PageInfo.HasNextPage {
-			cursor := query.SourceProject.Project.Items.PageInfo.EndCursor
-			afterCursor = &cursor
-		} else if query.TargetProject.Project.Items.PageInfo.HasNextPage {
-			cursor := query.TargetProject.Project.Items.PageInfo.EndCursor
-			afterCursor = &cursor
+		count, err := c.dedupeProject(project)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if project == sourceProject {
+			sourceDuplicates = count
 		}
+		duplicates += count
 	}
 
 	// Cache the project data with all items
-	c.cache.sourceProject = &ProjectV2{
-		ID: query.SourceProject.Project.ID,
-		Fields: struct {
-			Nodes []ProjectV2FieldConfiguration
-		}{
-			Nodes: query.SourceProject.Project.Fields.Nodes,
-		},
-		Items: struct {
-			Nodes    []ProjectV2Item
-			PageInfo struct {
-				HasNextPage bool
-				EndCursor   string
-			}
-		}{
-			Nodes: sourceItems,
-		},
+	c.mu.Lock()
+	c.duplicateIssues = duplicates
+	c.cache.sourceDuplicates = sourceDuplicates
+	c.cache.sourceProject = sourceProject
+	c.cache.targetProject = targetProject
+	c.cache.targetOptions = buildOptionIndex(targetProject)
+	c.cache.items = nil
+	for _, project := range uniqueProjects(sourceProject, targetProject) {
+		c.indexItems(project)
 	}
+	c.mu.Unlock()
 
-	c.cache.targetProject = &ProjectV2{
-		ID: query.TargetProject.Project.ID,
-		Fields: struct {
-			Nodes []ProjectV2FieldConfiguration
-		}{
-			Nodes: query.TargetProject.Project.Fields.Nodes,
-		},
-		Items: struct {
-			Nodes    []ProjectV2Item
-			PageInfo struct {
-				HasNextPage bool
-				EndCursor   string
-			}
-		}{
-			Nodes: targetItems,
-		},
+	sourceIssues, targetIssues = c.skipArchivedIssues(sourceProject, targetProject, projectIssueURLs(sourceProject), projectIssueURLs(targetProject))
+
+	slog.Info("completed loading project data",
+		"source_issues", len(sourceIssues),
+		"target_issues", len(targetIssues),
+		"source_pages_loaded", sourcePages,
+		"target_pages_loaded", targetPages,
+	)
+
+	return projectFieldConfigs(sourceProject), projectFieldConfigs(targetProject), sourceIssues, targetIssues, nil
+}
+
+// dedupeProject keeps a single item per issue in a project and returns the number of
+// duplicate items dropped
+func (c *GraphQLClient) dedupeProject(project *ProjectV2) (int, error) {
+	items, count, err := dedupeProjectItems(project.ID, project.Items.Nodes, c.onDuplicate)
+	if err != nil {
+		return 0, err
 	}
+	project.Items.Nodes = items
+	return count, nil
+}
 
-	// Convert field configurations
-	for _, field := range query.SourceProject.Project.Fields.Nodes {
-		config := github.ProjectFieldConfig{
-			ID:   field.DateField.ID,
-			Name: field.DateField.Name,
-			Type: field.TypeName,
-		}
-		sourceConfigs = append(sourceConfigs, config)
+// projectFieldConfigs returns the configurations of the fields of a project
+func projectFieldConfigs(project *ProjectV2) []github.ProjectFieldConfig {
+	var configs []github.ProjectFieldConfig
+	for _, field := range project.Fields.Nodes {
+		configs = append(configs, toFieldConfig(field))
 	}
+	return configs
+}
 
-	for _, field := range query.TargetProject.Project.Fields.Nodes {
-		config := github.ProjectFieldConfig{
-			ID:   field.DateField.ID,
-			Name: field.DateField.Name,
-			Type: field.TypeName,
+// projectIssueURLs returns the URLs of the issues of a project
+func projectIssueURLs(project *ProjectV2) []string {
+	var issues []string
+	for _, item := range project.Items.Nodes {
+		if item.isIssue() {
+			issues = append(issues, item.Content.Issue.URL)
 		}
-		targetConfigs = append(targetConfigs, config)
 	}
+	return issues
+}
 
-	// Get issues from all fetched items
-	for _, item := range sourceItems {
-		if item.Content.TypeName == "Issue" {
-			sourceIssues = append(sourceIssues, item.Content.Issue.URL)
+// projectItemsPage is a page of project items along with the project's field configurations
+type projectItemsPage struct {
+	ID     string
+	Fields struct {
+		Nodes []ProjectV2FieldConfiguration
+	} `graphql:"fields(first: 100)"`
+	Items struct {
+		Nodes    []ProjectV2Item
+		PageInfo struct {
+			HasNextPage bool
+			EndCursor   string
 		}
 	}
+}
 
-	for _, item := range targetItems {
-		if item.Content.TypeName == "Issue" {
-			targetIssues = append(targetIssues, item.Content.Issue.URL)
-		}
+// fetchAllProjectItems fetches the field configurations and all items of a project,
+// paginating with the given page size. If a filter is given, only matching items are
+// fetched. It returns the project and the number of pages loaded.
+func (c *GraphQLClient) fetchAllProjectItems(ctx context.Context, projectID string, pageSize int, filter string) (*ProjectV2, int, error) {
+	if filter == "" {
+		return c.paginateProjectItems(ctx, projectID, pageSize, "")
 	}
 
-	slog.Info("completed loading project data",
-		"source_issues", len(sourceIssues),
-		"target_issues", len(targetIssues),
-		"pages_loaded", page,
-	)
+	project, pages, err := c.paginateProjectItems(ctx, projectID, pageSize, filter)
+	if err == nil || !isUnsupportedFilterError(err) {
+		return project, pages, err
+	}
+
+	// Fall back to filtering on the client if the API does not support filtering items
+	slog.Warn("server-side filtering of project items is not supported, filtering on the client", "filter", filter)
+	clientFilter, err := parseItemFilter(filter)
+	if err != nil {
+		return nil, pages, fmt.Errorf("failed to parse filter for client-side filtering: %w", err)
+	}
+
+	project, pages, err = c.paginateProjectItems(ctx, projectID, pageSize, "")
+	if err != nil {
+		return nil, pages, err
+	}
+	project.Items.Nodes = clientFilter.apply(project.Items.Nodes)
+	return project, pages, nil
+}
 
-	return sourceConfigs, targetConfigs, sourceIssues, targetIssues, nil
+// isUnsupportedFilterError reports whether the API rejected the filter argument of the items query
+func isUnsupportedFilterError(err error) bool {
+	return strings.Contains(err.Error(), "doesn't accept argument 'query'")
 }
 
-// GetProjectFieldValues implements the Client interface
-func (c *GraphQLClient) GetProjectFieldValues(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
-	// Use cached data if available
-	var project *ProjectV2
-	if c.cache.sourceProject != nil && c.cache.sourceProject.ID == projectID {
-		project = c.cache.sourceProject
-	} else if c.cache.targetProject != nil && c.cache.targetProject.ID == projectID {
-		project = c.cache.targetProject
+// paginateProjectItems loads all pages of project items, passing the filter to the
+// items query if given
+func (c *GraphQLClient) paginateProjectItems(ctx context.Context, projectID string, pageSize int, filter string) (*ProjectV2, int, error) {
+	project := &ProjectV2{ID: projectID}
+	var afterCursor *string
+	var page int
+
+	for {
+		page++
+		slog.Debug("loading page of project items", "project_id", projectID, "page", page, "page_size", pageSize)
+
+		variables := map[string]interface{}{
+			"projectID":   githubv4.ID(projectID),
+			"first":       githubv4.Int(pageSize),
+			"afterCursor": (*githubv4.String)(afterCursor),
+		}
+
+		result, err := c.queryProjectItemsPage(ctx, variables, filter)
+		if err != nil {
+			return nil, page, err
+		}
+
+		project.ID = result.ID
+		project.Fields.Nodes = result.Fields.Nodes
+		project.Items.Nodes = append(project.Items.Nodes, result.Items.Nodes...)
+
+		if !result.Items.PageInfo.HasNextPage {
+			c.includeDraftIssues(project.Items.Nodes)
+			c.includePullRequests(project.Items.Nodes)
+			return project, page, nil
+		}
+
+		cursor := result.Items.PageInfo.EndCursor
+		afterCursor = &cursor
 	}
+}
 
-	if project == nil {
-		// Fall back to fetching data if not cached
+// queryProjectItemsPage queries a single page of project items. The items query only
+// takes the filter argument if a filter is given, as not all APIs support it.
+func (c *GraphQLClient) queryProjectItemsPage(ctx context.Context, variables map[string]interface{}, filter string) (*projectItemsPage, error) {
+	var page projectItemsPage
+	var rl rateLimit
+
+	if filter == "" {
 		var query struct {
 			Node struct {
-				Project ProjectV2 `graphql:"... on ProjectV2"`
+				Project struct {
+					ID     string
+					Fields struct {
+						Nodes []ProjectV2FieldConfiguration
+					} `graphql:"fields(first: 100)"`
+					Items struct {
+						Nodes    []ProjectV2Item
+						PageInfo struct {
+							HasNextPage bool
+							EndCursor   string
+						}
+					} `graphql:"items(first: $first, after: $afterCursor)"`
+				} `graphql:"... on ProjectV2"`
 			} `graphql:"node(id: $projectID)"`
+			RateLimit rateLimit
 		}
-
-		variables := map[string]interface{}{
-			"projectID": githubv4.ID(projectID),
+		if err := c.queryWithRetry(ctx, &query, variables); err != nil {
+			return nil, err
 		}
+		page, rl = projectItemsPage(query.Node.Project), query.RateLimit
+	} else {
+		var query struct {
+			Node struct {
+				Project struct {
+					ID     string
+					Fields struct {
+						Nodes []ProjectV2FieldConfiguration
+					} `graphql:"fields(first: 100)"`
+					Items struct {
+						Nodes    []ProjectV2Item
+						PageInfo struct {
+							HasNextPage bool
+							EndCursor   string
+						}
+					} `graphql:"items(first: $first, after: $afterCursor, query: $query)"`
+				} `graphql:"... on ProjectV2"`
+			} `graphql:"node(id: $projectID)"`
+			RateLimit rateLimit
+		}
+		variables["query"] = githubv4.String(filter)
+		if err := c.queryWithRetry(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+		page, rl = projectItemsPage(query.Node.Project), query.RateLimit
+	}
 
-		if err := c.client.Query(ctx, &query, variables); err != nil {
-			return nil, fmt.Errorf("failed to query project: %w", err)
+	if err := c.observeRateLimit(ctx, rl); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// toProjectFields converts the field values of an item to our internal format,
+// skipping values of unsupported field types
+func toProjectFields(item *ProjectV2Item) []github.ProjectField {
+	var fields []github.ProjectField
+	for i := range item.Fields.Nodes {
+		fieldValue := &item.Fields.Nodes[i]
+		value, ok := fieldValue.value()
+		if !ok {
+			continue
 		}
+		fields = append(fields, github.ProjectField{
+			ID:    fieldValue.fieldID(),
+			Name:  fieldValue.fieldName(),
+			Value: value,
+		})
+	}
+	return fields
+}
 
-		project = &query.Node.Project
+// value converts a field value into the value of a project field. It reports false for
+// values of field types that are not read, and for date, number, text and milestone values
+// without data.
+func (v *ProjectV2ItemFieldValue) value() (github.ProjectFieldValue, bool) {
+	switch v.TypeName {
+	case "ProjectV2ItemFieldDateValue":
+		if v.DateValue.Date == nil {
+			return github.ProjectFieldValue{}, false
+		}
+		return github.ProjectFieldValue{Date: &v.DateValue.Date.Time}, true
+	case "ProjectV2ItemFieldSingleSelectValue":
+		return github.ProjectFieldValue{Text: v.SingleSelectValue.Name, OptionID: v.SingleSelectValue.OptionID}, true
+	case "ProjectV2ItemFieldNumberValue":
+		return github.ProjectFieldValue{Number: v.NumberValue.Number}, v.NumberValue.Number != nil
+	case "ProjectV2ItemFieldTextValue":
+		return github.ProjectFieldValue{Text: v.TextValue.Text}, v.TextValue.Text != nil
+	case "ProjectV2ItemFieldIterationValue":
+		return github.ProjectFieldValue{Iteration: &v.IterationValue.Title, IterationID: v.IterationValue.IterationID}, true
+	case "ProjectV2ItemFieldUserValue":
+		return github.ProjectFieldValue{Users: v.UserValue.logins()}, true
+	case "ProjectV2ItemFieldMilestoneValue":
+		if v.MilestoneValue.Milestone == nil {
+			return github.ProjectFieldValue{}, false
+		}
+		return github.ProjectFieldValue{Milestone: &v.MilestoneValue.Milestone.Title}, true
+	case "ProjectV2ItemFieldLabelValue":
+		return github.ProjectFieldValue{Labels: v.LabelValue.names()}, true
+	default:
+		return github.ProjectFieldValue{}, false
 	}
+}
 
-	// Find the item (issue) in the project
-	var targetItem *ProjectV2Item
-	for _, item := range project.Items.Nodes {
-		if item.Content.TypeName == "Issue" && item.Content.Issue.URL == issueURL {
-			targetItem = &item
-			break
+// setValue replaces the data of a field value, keeping the field it belongs to
+func (v *ProjectV2ItemFieldValue) setValue(value github.ProjectFieldValue) {
+	switch v.TypeName {
+	case "ProjectV2ItemFieldDateValue":
+		v.DateValue.Date = nil
+		if value.Date != nil {
+			v.DateValue.Date = &GithubDate{Time: *value.Date}
 		}
+	case "ProjectV2ItemFieldSingleSelectValue":
+		v.SingleSelectValue.Name = value.Text
+		v.SingleSelectValue.OptionID = value.OptionID
+	case "ProjectV2ItemFieldNumberValue":
+		v.NumberValue.Number = value.Number
+	case "ProjectV2ItemFieldTextValue":
+		v.TextValue.Text = value.Text
+	case "ProjectV2ItemFieldIterationValue":
+		v.IterationValue.Title = ""
+		if value.Iteration != nil {
+			v.IterationValue.Title = *value.Iteration
+		}
+		v.IterationValue.IterationID = value.IterationID
+	case "ProjectV2ItemFieldUserValue":
+		v.UserValue.setLogins(value.Users)
+	case "ProjectV2ItemFieldMilestoneValue":
+		v.MilestoneValue.Milestone = nil
+		if value.Milestone != nil {
+			v.MilestoneValue.Milestone = &struct{ Title string }{Title: *value.Milestone}
+		}
+	case "ProjectV2ItemFieldLabelValue":
+		v.LabelValue.setNames(value.Labels)
 	}
+}
 
-	if targetItem == nil {
-		return nil, fmt.Errorf("issue %s not found in project", issueURL)
+// GetProjectFieldConfigs implements the Client interface
+func (c *GraphQLClient) GetProjectFieldConfigs(ctx context.Context, projectID string) ([]github.ProjectFieldConfig, error) {
+	var query struct {
+		Node struct {
+			Project struct {
+				Fields struct {
+					Nodes []ProjectV2FieldConfiguration
+				} `graphql:"fields(first: 100)"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $projectID)"`
 	}
 
-	// Convert field values to our internal format
-	var fields []github.ProjectField
-	for _, fieldValue := range targetItem.Fields.Nodes {
-		var field github.ProjectField
-
-		switch fieldValue.TypeName {
-		case "ProjectV2ItemFieldDateValue":
-			field = github.ProjectField{
-				ID:   fieldValue.DateValue.Field.DateField.ID,
-				Name: fieldValue.DateValue.Field.DateField.Name,
-				Value: github.ProjectFieldValue{
-					Date: &fieldValue.DateValue.Date.Time,
-				},
-			}
-		case "ProjectV2ItemFieldSingleSelectValue":
-			field = github.ProjectField{
-				ID:   fieldValue.SingleSelectValue.Field.SingleSelectField.ID,
-				Name: fieldValue.SingleSelectValue.Field.SingleSelectField.Name,
-				Value: github.ProjectFieldValue{
-					Text: fieldValue.SingleSelectValue.Name,
-				},
+	variables := map[string]interface{}{
+		"projectID": githubv4.ID(projectID),
+	}
+
+	if err := c.queryWithRetry(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to query project fields: %w", err)
+	}
+
+	configs := make([]github.ProjectFieldConfig, 0, len(query.Node.Project.Fields.Nodes))
+	for _, field := range query.Node.Project.Fields.Nodes {
+		configs = append(configs, toFieldConfig(field))
+	}
+
+	return configs, nil
+}
+
+// toFieldConfig converts a field configuration to our internal format
+func toFieldConfig(field ProjectV2FieldConfiguration) github.ProjectFieldConfig {
+	switch field.TypeName {
+	case "ProjectV2SingleSelectField":
+		config := github.ProjectFieldConfig{
+			ID:       field.SingleSelectField.ID,
+			Name:     field.SingleSelectField.Name,
+			Type:     field.TypeName,
+			DataType: "SINGLE_SELECT",
+		}
+		for _, opt := range field.SingleSelectField.Options {
+			config.Options = append(config.Options, github.ProjectFieldOption{ID: opt.ID, Name: opt.Name})
+		}
+		return config
+	case "ProjectV2IterationField":
+		config := github.ProjectFieldConfig{
+			ID:       field.IterationField.ID,
+			Name:     field.IterationField.Name,
+			Type:     field.TypeName,
+			DataType: "ITERATION",
+		}
+		for _, it := range field.IterationField.Configuration.Iterations {
+			startDate, err := time.Parse("2006-01-02", it.StartDate)
+			if err != nil {
+				slog.Debug("skipping iteration with invalid start date", "field", config.Name, "iteration", it.Title, "start_date", it.StartDate)
+				continue
 			}
+			config.Iterations = append(config.Iterations, github.ProjectFieldIteration{
+				ID:        it.ID,
+				Title:     it.Title,
+				StartDate: startDate,
+				Duration:  it.Duration,
+			})
 		}
-
-		if field.ID != "" { // Only add if we handled this field type
-			fields = append(fields, field)
+		return config
+	default:
+		return github.ProjectFieldConfig{
+			ID:       field.DateField.ID,
+			Name:     field.DateField.Name,
+			Type:     field.TypeName,
+			DataType: field.DateField.DataType,
 		}
 	}
+}
+
+// GetProjectFieldValues implements the Client interface
+func (c *GraphQLClient) GetProjectFieldValues(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+	// Use cached data if available
+	project, err := c.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
 
-	return fields, nil
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Find the item (issue) in the project
+	targetItem := c.findItem(project, issueURL)
+	if targetItem == nil {
+		return nil, issueNotFound(issueURL)
+	}
+
+	return toProjectFields(targetItem), nil
 }
 
 func (c *GraphQLClient) GetProjectID(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+	if id, ok := c.cachedProjectID(projectInfo); ok {
+		return id, nil
+	}
+
 	slog.Info("getting project ID", "owner_type", projectInfo.OwnerType, "owner_login", projectInfo.OwnerLogin, "project_number", projectInfo.ProjectNumber)
 
 	var project *ProjectV2
@@ -795,25 +1633,92 @@ func (c *GraphQLClient) GetProjectID(ctx context.Context, projectInfo *github.Pr
 		return "", fmt.Errorf("failed to get project: %w", err)
 	}
 
+	c.cacheProjectID(projectInfo, project.ID)
 	return project.ID, nil
 }
 
 func (c *GraphQLClient) GetIssueTitle(_ctx context.Context, issueURL string) (string, error) {
-	if c.cache.sourceProject != nil {
-		for _, item := range c.cache.sourceProject.Items.Nodes {
-			if item.Content.TypeName == "Issue" && item.Content.Issue.URL == issueURL {
-				return item.Content.Issue.Title, nil
-			}
-		}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if issue := c.cachedIssue(issueURL); issue != nil {
+		return issue.Title, nil
 	}
 
-	if c.cache.targetProject != nil {
-		for _, item := range c.cache.targetProject.Items.Nodes {
-			if item.Content.TypeName == "Issue" && item.Content.Issue.URL == issueURL {
-				return item.Content.Issue.Title, nil
-			}
-		}
+	if title, ok := c.cache.issueTitles[util.CanonicalizeIssueURL(issueURL)]; ok {
+		return title, nil
 	}
 
 	return "", fmt.Errorf("issue %s not found in cache", issueURL)
 }
+
+// GetIssueLabels implements the Client interface
+func (c *GraphQLClient) GetIssueLabels(_ctx context.Context, issueURL string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	issue := c.cachedIssue(issueURL)
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s not found in cache", issueURL)
+	}
+
+	labels := make([]string, 0, len(issue.Labels.Nodes))
+	for _, label := range issue.Labels.Nodes {
+		labels = append(labels, label.Name)
+	}
+	return labels, nil
+}
+
+// GetIssueUpdatedAt implements the Client interface
+func (c *GraphQLClient) GetIssueUpdatedAt(_ctx context.Context, issueURL string) (time.Time, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	issue := c.cachedIssue(issueURL)
+	if issue == nil {
+		return time.Time{}, fmt.Errorf("issue %s not found in cache", issueURL)
+	}
+	return issue.UpdatedAt.Time, nil
+}
+
+const (
+	// IssueStateOpen is the state of open issues and pull requests
+	IssueStateOpen = "OPEN"
+	// IssueStateClosed is the state of closed issues and pull requests
+	IssueStateClosed = "CLOSED"
+	// IssueStateMerged is the state of merged pull requests
+	IssueStateMerged = "MERGED"
+)
+
+// GetIssueState implements the Client interface
+func (c *GraphQLClient) GetIssueState(_ctx context.Context, issueURL string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	issue := c.cachedIssue(issueURL)
+	if issue == nil {
+		return "", fmt.Errorf("issue %s not found in cache", issueURL)
+	}
+	return issue.State, nil
+}
+
+// cachedIssue finds an issue in the cached source or target project. The caller must hold the lock.
+func (c *GraphQLClient) cachedIssue(issueURL string) *ProjectV2ItemIssue {
+	for _, project := range []*ProjectV2{c.cache.sourceProject, c.cache.targetProject} {
+		if project == nil {
+			continue
+		}
+		if item := c.findItem(project, issueURL); item != nil {
+			return &item.Content.Issue
+		}
+	}
+	return nil
+}
+
+// uniqueProjects returns the source and target project, or only one of them if both are the same
+func uniqueProjects(sourceProject, targetProject *ProjectV2) []*ProjectV2 {
+	if sourceProject == targetProject {
+		return []*ProjectV2{sourceProject}
+	}
+	return []*ProjectV2{sourceProject, targetProject}
+}
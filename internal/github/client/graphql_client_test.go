@@ -0,0 +1,1020 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// newTestClient creates a client that sends its requests to the given handler
+func newTestClient(t *testing.T, handler func(req GraphQLRequest) string) *GraphQLClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := DecodeGraphQLRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, handler(req))
+	}))
+	t.Cleanup(server.Close)
+
+	// Record response errors like the client created by NewGraphQLClient
+	httpClient := &http.Client{Transport: &partialResponseTransport{transport: server.Client().Transport}}
+	return &GraphQLClient{
+		client:         githubv4.NewEnterpriseClient(server.URL, httpClient),
+		sourcePageSize: DefaultPageSize,
+		targetPageSize: DefaultPageSize,
+	}
+}
+
+// projectItemsResponse builds a response for a page of project items with the given issue URLs
+func projectItemsResponse(projectID string, hasNextPage bool, issueURLs ...string) string {
+	items := make([]map[string]interface{}, 0, len(issueURLs))
+	for _, url := range issueURLs {
+		items = append(items, map[string]interface{}{
+			"id":          "item_" + url,
+			"fieldValues": map[string]interface{}{"nodes": []interface{}{}},
+			"content":     map[string]interface{}{"__typename": "Issue", "url": url, "title": "Title of " + url},
+		})
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"node": map[string]interface{}{
+				"id":     projectID,
+				"fields": map[string]interface{}{"nodes": []interface{}{}},
+				"items": map[string]interface{}{
+					"nodes":    items,
+					"pageInfo": map[string]interface{}{"hasNextPage": hasNextPage, "endCursor": "cursor"},
+				},
+			},
+		},
+	})
+	return string(data)
+}
+
+// singleSelectField builds a single select field configuration with the given options
+func singleSelectField(id, name string, options map[string]string) ProjectV2FieldConfiguration {
+	field := ProjectV2FieldConfiguration{TypeName: "ProjectV2SingleSelectField"}
+	field.SingleSelectField.ID = id
+	field.SingleSelectField.Name = name
+	for optionName, optionID := range options {
+		field.SingleSelectField.Options = append(field.SingleSelectField.Options, struct {
+			ID   string
+			Name string
+		}{ID: optionID, Name: optionName})
+	}
+	return field
+}
+
+func TestBuildOptionIndex(t *testing.T) {
+	dateField := ProjectV2FieldConfiguration{TypeName: "ProjectV2Field"}
+	dateField.DateField.ID = "field_date"
+	dateField.DateField.Name = "Start date"
+
+	project := &ProjectV2{}
+	project.Fields.Nodes = []ProjectV2FieldConfiguration{
+		dateField,
+		singleSelectField("field_status", "Status", map[string]string{"Todo": "opt_todo", "Done": "opt_done"}),
+		singleSelectField("field_priority", "Priority", map[string]string{"High": "opt_high"}),
+	}
+
+	index := buildOptionIndex(project)
+
+	assert.Equal(t, optionIndex{
+		"field_status":   {"Todo": "opt_todo", "Done": "opt_done"},
+		"field_priority": {"High": "opt_high"},
+	}, index)
+	assert.Equal(t, "opt_done", index.lookup("field_status", "Done", false))
+	assert.Empty(t, index.lookup("field_status", "Blocked", false))
+	assert.Empty(t, index.lookup("field_start", "Todo", false))
+}
+
+func TestOptionIndexLookupNormalized(t *testing.T) {
+	index := optionIndex{
+		"field_status": {"🚧 In Progress": "opt_progress", "In Progress": "opt_exact", "✅  Done ": "opt_done"},
+	}
+
+	assert.Equal(t, "opt_exact", index.lookup("field_status", "In Progress", true), "expected exact matches to be preferred")
+	assert.Equal(t, "opt_done", index.lookup("field_status", "Done", true))
+	assert.Equal(t, "opt_done", index.lookup("field_status", "✔️ Done", true))
+	assert.Empty(t, index.lookup("field_status", "Done", false))
+	assert.Empty(t, index.lookup("field_status", "Blocked", true))
+}
+
+func TestValuesEqualNormalizesSingleSelectValues(t *testing.T) {
+	current := &ProjectV2ItemFieldValue{TypeName: "ProjectV2ItemFieldSingleSelectValue"}
+	name := "🚧 In Progress"
+	current.SingleSelectValue.Name = &name
+	value := "In   Progress"
+	field := github.ProjectField{Name: "Status", Value: github.ProjectFieldValue{Text: &value}}
+
+	assert.False(t, (&GraphQLClient{}).valuesEqual(current, field))
+	assert.True(t, (&GraphQLClient{normalizeSelect: true}).valuesEqual(current, field))
+}
+
+func TestNormalizeOptionName(t *testing.T) {
+	tests := map[string]string{
+		"In Progress":       "In Progress",
+		"🚧 In Progress":     "In Progress",
+		"  In   Progress  ": "In Progress",
+		"✔️ Done":           "Done",
+		"👩🏽‍💻 Coding":       "Coding",
+		"Done 🎉":            "Done 🎉",
+		"1 - Backlog":       "1 - Backlog",
+		"🔥":                 "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, github.NormalizeOptionName(name), "normalizing %q", name)
+	}
+}
+
+func TestGithubDateUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    time.Time
+		wantErr string
+	}{
+		{name: "date", data: `"2024-03-01"`, want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "date-time", data: `"2024-03-01T10:30:00Z"`, want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "date-time with offset", data: `"2024-03-01T23:30:00-05:00"`, want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "null", data: `null`},
+		{name: "invalid", data: `"01/03/2024"`, wantErr: `invalid date "01/03/2024" (expected YYYY-MM-DD or RFC 3339)`},
+		{name: "not a string", data: `20240301`, wantErr: "invalid date 20240301"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d GithubDate
+			err := d.UnmarshalJSON([]byte(tt.data))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d.Time)
+		})
+	}
+}
+
+func TestGetProjectFieldValuesSkipsNullDates(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2Field","id":"field_start","name":"Start","dataType":"DATE"},
+			{"__typename":"ProjectV2Field","id":"field_end","name":"End","dataType":"DATE"}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldDateValue","field":{"__typename":"ProjectV2Field","id":"field_start","name":"Start"},"date":null},
+				{"__typename":"ProjectV2ItemFieldDateValue","field":{"__typename":"ProjectV2Field","id":"field_end","name":"End"},"date":"2024-03-01T12:00:00Z"}
+			]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "End", fields[0].Name)
+	assert.Equal(t, "2024-03-01", fields[0].Value.String())
+}
+
+func TestGetProjectFieldsSkipsNullDates(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2Field","id":"field_start","name":"Start","dataType":"DATE"}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldDateValue","field":{"__typename":"ProjectV2Field","id":"field_start","name":"Start"},"date":null}
+			]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	fields, err := c.GetProjectFields(context.Background(), "target", issueURL)
+	require.NoError(t, err)
+	assert.Empty(t, fields, "expected an empty date to be treated as no value")
+}
+
+func TestUpdateCacheFieldValueWithoutDate(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	date := GithubDate{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	var item ProjectV2Item
+	item.Content.TypeName = "Issue"
+	item.Content.Issue.URL = issueURL
+	var fieldValue ProjectV2ItemFieldValue
+	fieldValue.TypeName = "ProjectV2ItemFieldDateValue"
+	fieldValue.DateValue.Field.DateField.ID = "field_start"
+	fieldValue.DateValue.Field.DateField.Name = "Start"
+	fieldValue.DateValue.Date = &date
+	item.Fields.Nodes = []ProjectV2ItemFieldValue{fieldValue}
+	project := &ProjectV2{ID: "target"}
+	project.Items.Nodes = []ProjectV2Item{item}
+
+	c := &GraphQLClient{}
+	c.updateCacheFieldValue(project, issueURL, github.ProjectField{ID: "field_start", Name: "Start"})
+	assert.Nil(t, project.Items.Nodes[0].Fields.Nodes[0].DateValue.Date)
+}
+
+func TestFindProjectItemReturnsValueOfProject(t *testing.T) {
+	newItem := func(id, issueURL, date string) ProjectV2Item {
+		start, err := time.Parse("2006-01-02", date)
+		require.NoError(t, err)
+
+		var item ProjectV2Item
+		item.ID = id
+		item.Content.TypeName = "Issue"
+		item.Content.Issue.URL = issueURL
+		var fieldValue ProjectV2ItemFieldValue
+		fieldValue.TypeName = "ProjectV2ItemFieldDateValue"
+		fieldValue.DateValue.Field.DateField.ID = "field_start"
+		fieldValue.DateValue.Field.DateField.Name = "Start"
+		fieldValue.DateValue.Date = &GithubDate{Time: start}
+		item.Fields.Nodes = []ProjectV2ItemFieldValue{fieldValue}
+		return item
+	}
+
+	// Items of similar issues, only the last one matches
+	project := &ProjectV2{ID: "target"}
+	project.Items.Nodes = []ProjectV2Item{
+		newItem("item_10", "https://github.com/org/repo/issues/10", "2024-01-10"),
+		newItem("item_11", "https://github.com/org/repo-1/issues/1", "2024-01-11"),
+		newItem("item_1", "https://github.com/org/repo/issues/1", "2024-01-01"),
+	}
+
+	c := &GraphQLClient{}
+	itemID, value, err := c.findProjectItem(project, "https://github.com/org/repo/issues/1", github.ProjectField{ID: "field_start"})
+	require.NoError(t, err)
+	assert.Equal(t, "item_1", itemID)
+	assert.Same(t, &project.Items.Nodes[2].Fields.Nodes[0], value, "expected the value of the project, not a copy")
+
+	c.cache.targetProject = project
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", "https://github.com/org/repo/issues/10", nil)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "2024-01-10", fields[0].Value.String())
+}
+
+func TestGetProjectIDOfUserProject(t *testing.T) {
+	var requests []GraphQLRequest
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		requests = append(requests, req)
+		return `{"data":{"user":{"projectV2":{"id":"project_user"}}}}`
+	})
+
+	project := &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeUser, OwnerLogin: "octocat", ProjectNumber: 3}
+	id, err := c.GetProjectID(context.Background(), project)
+	require.NoError(t, err)
+	assert.Equal(t, "project_user", id)
+
+	require.Len(t, requests, 1)
+	assert.Contains(t, requests[0].Query, "user(login: $login)")
+	assert.Equal(t, "octocat", requests[0].Variables["login"])
+	assert.Equal(t, float64(3), requests[0].Variables["projectNumber"])
+
+	// Invalid project numbers are rejected without a request
+	_, err = c.GetProjectID(context.Background(), &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeUser, OwnerLogin: "octocat"})
+	assert.EqualError(t, err, "failed to get project: invalid project number: 0")
+	assert.Len(t, requests, 1)
+}
+
+func TestUpdateProjectFieldBuildsDateMutation(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var itemInput map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "updateProjectV2ItemFieldValue(") {
+			itemInput, _ = req.Variables["input"].(map[string]interface{})
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2Field","id":"field_start","name":"Start","dataType":"DATE"}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldDateValue","field":{"__typename":"ProjectV2Field","id":"field_start","name":"Start"},"date":"2024-03-01"}
+			]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "2024-03-01", fields[0].Value.String())
+
+	date := time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name:  "Start",
+		Value: github.ProjectFieldValue{Date: &date},
+	}, false)
+	require.NoError(t, err)
+
+	require.NotNil(t, itemInput, "expected the item value to be set")
+	assert.Equal(t, map[string]interface{}{
+		"projectId": "target",
+		"itemId":    "item_1",
+		"fieldId":   "field_start",
+		"value":     map[string]interface{}{"date": "2024-04-15T00:00:00Z"},
+	}, itemInput)
+}
+
+func TestUpdateProjectFieldChecksDeclaredDataType(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var mutations int
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "updateProjectV2ItemFieldValue(") {
+			mutations++
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2Field","id":"field_start","name":"Start","dataType":"DATE"}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	date := time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+	err := c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name:     "Start",
+		Value:    github.ProjectFieldValue{Date: &date},
+		DataType: "DATE",
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mutations)
+
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name:     "Start",
+		Value:    github.ProjectFieldValue{Date: &date},
+		DataType: "SINGLE_SELECT",
+	}, false)
+	assert.EqualError(t, err, "field Start is of type DATE, but was declared as SINGLE_SELECT")
+	assert.Equal(t, 1, mutations)
+}
+
+func TestUpdateProjectFieldMatchesSingleSelectFieldsByID(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var itemInput map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "updateProjectV2ItemFieldValue(") {
+			itemInput, _ = req.Variables["input"].(map[string]interface{})
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		// Two single select fields named Status with options of the same name
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2SingleSelectField","id":"field_custom_status","name":"Status","options":[{"id":"opt_custom_done","name":"Done"},{"id":"opt_custom_blocked","name":"Blocked"}]},
+			{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status","options":[{"id":"opt_done","name":"Done"},{"id":"opt_todo","name":"Todo"}]}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"__typename":"ProjectV2SingleSelectField","id":"field_custom_status","name":"Status"},"name":"Blocked"},
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status"},"name":"Todo"}
+			]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+
+	// The value of the other Status field must neither be compared nor updated
+	done := "Done"
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		ID:    "field_status",
+		Name:  "Status",
+		Value: github.ProjectFieldValue{Text: &done},
+	}, false)
+	require.NoError(t, err)
+
+	require.NotNil(t, itemInput, "expected the item value to be set")
+	assert.Equal(t, "field_status", itemInput["fieldId"])
+	assert.Equal(t, map[string]interface{}{"singleSelectOptionId": "opt_done"}, itemInput["value"])
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, value, err := c.findProjectItem(c.cache.targetProject, issueURL, github.ProjectField{ID: "field_status"})
+	require.NoError(t, err)
+	assert.Equal(t, "Done", *value.SingleSelectValue.Name)
+	_, value, err = c.findProjectItem(c.cache.targetProject, issueURL, github.ProjectField{ID: "field_custom_status"})
+	require.NoError(t, err)
+	assert.Equal(t, "Blocked", *value.SingleSelectValue.Name, "expected the other field to be unchanged")
+}
+
+func TestUpdateProjectFieldUsesDataTypeOfFieldByID(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var queries []string
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.HasPrefix(req.Query, "mutation") {
+			queries = append(queries, req.Query)
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		// The built-in milestone field and a text field of the same name
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2Field","id":"field_milestone","name":"Milestone","dataType":"MILESTONE"},
+			{"__typename":"ProjectV2Field","id":"field_milestone_text","name":"Milestone","dataType":"TEXT"}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+
+	// The text field is written as text, not as the milestone of the issue
+	title := "v1.1"
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		ID:    "field_milestone_text",
+		Name:  "Milestone",
+		Value: github.ProjectFieldValue{Text: &title},
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "updateProjectV2ItemFieldValue(")
+}
+
+func TestUpdateProjectFieldSelectsOptionByID(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var inputs []map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "updateProjectV2ItemFieldValue(") {
+			input, _ := req.Variables["input"].(map[string]interface{})
+			inputs = append(inputs, input)
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		// Two options named Done, the issue has the first one
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status","options":[{"id":"opt_done_1","name":"Done"},{"id":"opt_done_2","name":"Done"}]}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status"},"name":"Done","optionId":"opt_done_1"}
+			]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+
+	// The name matches, but the option differs
+	done := "Done"
+	field := github.ProjectField{
+		ID:    "field_status",
+		Name:  "Status",
+		Value: github.ProjectFieldValue{Text: &done, OptionID: "opt_done_2"},
+	}
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+	require.Len(t, inputs, 1)
+	assert.Equal(t, map[string]interface{}{"singleSelectOptionId": "opt_done_2"}, inputs[0]["value"])
+
+	// The cache holds the selected option, so the update is not repeated
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+	assert.Len(t, inputs, 1)
+}
+
+func TestUpdateProjectFieldSetsIteration(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var inputs []map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "updateProjectV2ItemFieldValue(") {
+			input, _ := req.Variables["input"].(map[string]interface{})
+			inputs = append(inputs, input)
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2IterationField","id":"field_sprint","name":"Sprint","configuration":{"iterations":[
+				{"id":"it_1","title":"Sprint 1","startDate":"2024-03-04","duration":14}
+			]}}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	configs, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, []github.ProjectFieldIteration{
+		{ID: "it_1", Title: "Sprint 1", StartDate: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Duration: 14},
+	}, configs[0].Iterations)
+
+	title := "Sprint 1"
+	field := github.ProjectField{
+		Name:  "Sprint",
+		Value: github.ProjectFieldValue{Iteration: &title, IterationID: "it_1"},
+	}
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+	require.Len(t, inputs, 1)
+	assert.Equal(t, "field_sprint", inputs[0]["fieldId"])
+	assert.Equal(t, map[string]interface{}{"iterationId": "it_1"}, inputs[0]["value"])
+}
+
+// plainValuesTestClient returns a client with a cached project holding an issue with values
+// for a number, a text and an iteration field. The update mutations sent are recorded.
+func plainValuesTestClient(t *testing.T) (*GraphQLClient, *[]map[string]interface{}) {
+	t.Helper()
+
+	var inputs []map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "updateProjectV2ItemFieldValue(") {
+			input, _ := req.Variables["input"].(map[string]interface{})
+			inputs = append(inputs, input)
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2Field","id":"field_points","name":"Points","dataType":"NUMBER"},
+			{"__typename":"ProjectV2Field","id":"field_notes","name":"Notes","dataType":"TEXT"},
+			{"__typename":"ProjectV2IterationField","id":"field_sprint","name":"Sprint","configuration":{"iterations":[
+				{"id":"it_1","title":"Sprint 1","startDate":"2024-03-04","duration":14},
+				{"id":"it_2","title":"Sprint 2","startDate":"2024-03-18","duration":14}
+			]}}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldNumberValue","field":{"__typename":"ProjectV2Field","id":"field_points","name":"Points"},"number":3},
+				{"__typename":"ProjectV2ItemFieldTextValue","field":{"__typename":"ProjectV2Field","id":"field_notes","name":"Notes"},"text":"Needs review"},
+				{"__typename":"ProjectV2ItemFieldIterationValue","field":{"__typename":"ProjectV2IterationField","id":"field_sprint","name":"Sprint"},"title":"Sprint 1","iterationId":"it_1"}
+			]},"content":{"__typename":"Issue","url":"https://github.com/org/repo/issues/1","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+	return c, &inputs
+}
+
+func TestGetProjectFieldValuesReadsNumberTextAndIterationValues(t *testing.T) {
+	c, _ := plainValuesTestClient(t)
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", "https://github.com/org/repo/issues/1", nil)
+	require.NoError(t, err)
+
+	points, notes, sprint := 3.0, "Needs review", "Sprint 1"
+	assert.Equal(t, []github.ProjectField{
+		{ID: "field_points", Name: "Points", Value: github.ProjectFieldValue{Number: &points}},
+		{ID: "field_notes", Name: "Notes", Value: github.ProjectFieldValue{Text: &notes}},
+		{ID: "field_sprint", Name: "Sprint", Value: github.ProjectFieldValue{Iteration: &sprint, IterationID: "it_1"}},
+	}, fields)
+}
+
+func TestUpdateProjectFieldWritesNumberTextAndIterationValues(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c, inputs := plainValuesTestClient(t)
+
+	// Values equal to the current ones are not written
+	points, notes, sprint := 3.0, "Needs review", "Sprint 1"
+	for _, field := range []github.ProjectField{
+		{Name: "Points", Value: github.ProjectFieldValue{Number: &points}},
+		{Name: "Notes", Value: github.ProjectFieldValue{Text: &notes}},
+		{Name: "Sprint", Value: github.ProjectFieldValue{Iteration: &sprint}},
+	} {
+		require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+	}
+	assert.Empty(t, *inputs)
+
+	// Text values are written as text, and iterations chosen by title are set by their ID
+	notes, sprint = "Done", "Sprint 2"
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name: "Notes", Value: github.ProjectFieldValue{Text: &notes},
+	}, false))
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name: "Sprint", Value: github.ProjectFieldValue{Iteration: &sprint},
+	}, false))
+	require.Len(t, *inputs, 2)
+	assert.Equal(t, map[string]interface{}{"text": "Done"}, (*inputs)[0]["value"])
+	assert.Equal(t, map[string]interface{}{"iterationId": "it_2"}, (*inputs)[1]["value"])
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Done", fields[1].Value.String(), "expected the cache to be updated")
+	assert.Equal(t, "it_2", fields[2].Value.IterationID, "expected the cache to be updated")
+
+	// Iterations missing in the field are reported
+	sprint = "Sprint 9"
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name: "Sprint", Value: github.ProjectFieldValue{Iteration: &sprint},
+	}, false)
+	assert.ErrorIs(t, err, ErrIterationNotFound)
+}
+
+func TestGetProjectFieldConfigsAndIssuesUsesPageSizePerProject(t *testing.T) {
+	var mu sync.Mutex
+	pageSizes := make(map[string]float64)
+
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		projectID, _ := req.Variables["projectID"].(string)
+		mu.Lock()
+		pageSizes[projectID], _ = req.Variables["first"].(float64)
+		mu.Unlock()
+		return projectItemsResponse(projectID, false, "https://github.com/org/repo/issues/1")
+	})
+	c.sourcePageSize = 25
+	c.targetPageSize = 50
+
+	_, _, sourceIssues, targetIssues, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{"source": 25, "target": 50}, pageSizes)
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, sourceIssues)
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, targetIssues)
+}
+
+func TestPageSizeOrDefault(t *testing.T) {
+	assert.Equal(t, DefaultPageSize, pageSizeOrDefault(0, 0))
+	assert.Equal(t, 40, pageSizeOrDefault(0, 40))
+	assert.Equal(t, 20, pageSizeOrDefault(20, 40))
+	assert.Equal(t, MaxPageSize, pageSizeOrDefault(500, 40), "expected the page size to be capped")
+}
+
+func TestGetProjectIssuesUsesPageSize(t *testing.T) {
+	var first float64
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		first, _ = req.Variables["first"].(float64)
+		return projectItemsResponse("project", false, "https://github.com/org/repo/issues/1")
+	})
+	c.pageSize = 30
+
+	_, err := c.GetProjectIssues(context.Background(), "project")
+	require.NoError(t, err)
+	assert.Equal(t, float64(30), first)
+}
+
+func TestGetProjectFieldValuesLoadsAllPagesOfUncachedProject(t *testing.T) {
+	urls := make([]string, 150)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://github.com/org/repo/issues/%d", i+1)
+	}
+
+	var calls int
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		calls++
+		if req.Variables["afterCursor"] == nil {
+			return projectItemsResponse("project", true, urls[:100]...)
+		}
+
+		// The last issue of the second page has a status
+		var page map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(projectItemsResponse("project", false, urls[100:]...)), &page))
+		items := page["data"].(map[string]interface{})["node"].(map[string]interface{})["items"].(map[string]interface{})["nodes"].([]interface{})
+		items[len(items)-1].(map[string]interface{})["fieldValues"] = map[string]interface{}{"nodes": []interface{}{
+			map[string]interface{}{
+				"__typename": "ProjectV2ItemFieldSingleSelectValue",
+				"field":      map[string]interface{}{"__typename": "ProjectV2SingleSelectField", "id": "field_status", "name": "Status"},
+				"name":       "Done",
+			},
+		}}
+		data, err := json.Marshal(page)
+		require.NoError(t, err)
+		return string(data)
+	})
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "project", urls[149], nil)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "Status", fields[0].Name)
+	assert.Equal(t, "Done", fields[0].Value.String())
+	assert.Equal(t, 2, calls, "expected both pages to be loaded")
+
+	_, err = c.GetProjectFieldValues(context.Background(), "project", "https://github.com/org/repo/issues/151", nil)
+	assert.ErrorIs(t, err, ErrIssueNotFound)
+}
+
+func TestGetProjectFieldConfigsAndIssuesStopsAtLastPagePerProject(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+
+	// The source project has three pages, the target project a single one
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		projectID, _ := req.Variables["projectID"].(string)
+		mu.Lock()
+		requests[projectID]++
+		page := requests[projectID]
+		mu.Unlock()
+
+		if projectID == "source" {
+			return projectItemsResponse(projectID, page < 3, fmt.Sprintf("https://github.com/org/repo/issues/%d", page))
+		}
+		return projectItemsResponse(projectID, false, "https://github.com/org/repo/issues/1")
+	})
+
+	_, _, sourceIssues, targetIssues, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"source": 3, "target": 1}, requests)
+	assert.Equal(t, []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}, sourceIssues)
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, targetIssues)
+
+	// Both projects are cached with all their items
+	assert.Len(t, c.cache.sourceProject.Items.Nodes, 3)
+	assert.Len(t, c.cache.targetProject.Items.Nodes, 1)
+
+	// Listing the issues of a project paginates the same way
+	requests = make(map[string]int)
+	issues, err := c.GetProjectIssues(context.Background(), "source")
+	require.NoError(t, err)
+	assert.Equal(t, sourceIssues, issues)
+	assert.Equal(t, map[string]int{"source": 3}, requests)
+}
+
+func TestGetProjectFieldValuesWithNoCacheFetchesProject(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var mu sync.Mutex
+	requests := 0
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		projectID, _ := req.Variables["projectID"].(string)
+		return projectItemsResponse(projectID, false, issueURL)
+	})
+
+	// Populate the cache
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "other")
+	require.NoError(t, err)
+	requestsAfterLoad := requests
+
+	_, err = c.GetProjectFieldValues(context.Background(), "project", issueURL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, requestsAfterLoad, requests, "expected cached project to be used")
+
+	c.noCache = true
+	_, err = c.GetProjectFieldValues(context.Background(), "project", issueURL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, requestsAfterLoad+1, requests, "expected project to be fetched despite populated cache")
+}
+
+func TestGetProjectFieldConfigsAndIssuesSharesSameProject(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		projectID, _ := req.Variables["projectID"].(string)
+		return projectItemsResponse(projectID, false, "https://github.com/org/repo/issues/1")
+	})
+
+	_, _, sourceIssues, targetIssues, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, requests, "expected the project to be fetched once")
+	assert.Equal(t, sourceIssues, targetIssues)
+	assert.Same(t, c.cache.sourceProject, c.cache.targetProject)
+}
+
+func TestGetIssueLabels(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		data, _ := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{
+				"node": map[string]interface{}{
+					"id":     "project",
+					"fields": map[string]interface{}{"nodes": []interface{}{}},
+					"items": map[string]interface{}{
+						"nodes": []interface{}{map[string]interface{}{
+							"id":          "item",
+							"fieldValues": map[string]interface{}{"nodes": []interface{}{}},
+							"content": map[string]interface{}{
+								"__typename": "Issue",
+								"url":        issueURL,
+								"title":      "Issue",
+								"labels": map[string]interface{}{"nodes": []interface{}{
+									map[string]interface{}{"name": "bug"},
+									map[string]interface{}{"name": "ui"},
+								}},
+							},
+						}},
+						"pageInfo": map[string]interface{}{"hasNextPage": false},
+					},
+				},
+			},
+		})
+		return string(data)
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+	require.NoError(t, err)
+
+	labels, err := c.GetIssueLabels(context.Background(), issueURL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bug", "ui"}, labels)
+
+	_, err = c.GetIssueLabels(context.Background(), "https://github.com/org/repo/issues/2")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestGetIssueState(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		assert.Contains(t, req.Query, "state", "expected the state of the items to be queried")
+		return `{"data":{"node":{"id":"project","fields":{"nodes":[]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue","state":"CLOSED"}},
+			{"id":"item_2","fieldValues":{"nodes":[]},"content":{"__typename":"DraftIssue","title":"Plan"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+	c.includeDrafts = true
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+	require.NoError(t, err)
+
+	state, err := c.GetIssueState(context.Background(), issueURL)
+	require.NoError(t, err)
+	assert.Equal(t, IssueStateClosed, state)
+
+	state, err = c.GetIssueState(context.Background(), DraftIssueURL("Plan"))
+	require.NoError(t, err)
+	assert.Equal(t, IssueStateOpen, state, "expected draft issues to be open")
+
+	_, err = c.GetIssueState(context.Background(), "https://github.com/org/repo/issues/2")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestGetProjectFieldConfigsAndIssuesPassesServerFilter(t *testing.T) {
+	var mu sync.Mutex
+	queries := make(map[string]GraphQLRequest)
+
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		projectID, _ := req.Variables["projectID"].(string)
+		mu.Lock()
+		queries[projectID] = req
+		mu.Unlock()
+		return projectItemsResponse(projectID, false, "https://github.com/org/repo/issues/1")
+	})
+	c.serverFilter = "status:Done"
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+	require.NoError(t, err)
+
+	assert.Contains(t, queries["source"].Query, "query: $query")
+	assert.Equal(t, "status:Done", queries["source"].Variables["query"])
+	assert.NotContains(t, queries["target"].Query, "query: $query", "expected the filter to only apply to the source project")
+}
+
+func TestGetProjectFieldConfigsAndIssuesFallsBackToClientSideFilter(t *testing.T) {
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "query: $query") {
+			return `{"errors":[{"message":"Field 'items' doesn't accept argument 'query'"}]}`
+		}
+		projectID, _ := req.Variables["projectID"].(string)
+		return projectItemsResponse(projectID, false, "https://github.com/org/repo/issues/1")
+	})
+	c.serverFilter = "status:Done"
+
+	_, _, sourceIssues, targetIssues, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+	require.NoError(t, err)
+
+	assert.Empty(t, sourceIssues, "expected items without a matching status to be filtered out")
+	assert.Len(t, targetIssues, 1)
+}
+
+func TestLogFieldUpdate(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	t.Run("compact", func(t *testing.T) {
+		buf.Reset()
+		c := &GraphQLClient{logStyle: LogStyleCompact}
+		c.logFieldUpdate("Start date", "2024-01-01", "2024-02-01", false)
+		assert.Equal(t, "level=DEBUG msg=\"Start date: 2024-01-01 → 2024-02-01\" dry_run=false\n", buf.String())
+	})
+
+	t.Run("with node IDs", func(t *testing.T) {
+		buf.Reset()
+		c := &GraphQLClient{}
+		c.logFieldUpdate("Start date", "2024-01-01", "2024-02-01", false, "project_id", "project_1", "item_id", "item_1", "field_id", "field_1")
+		assert.Equal(t, "level=DEBUG msg=\"updating field value\" field=\"Start date\" old=2024-01-01 new=2024-02-01 dry_run=false project_id=project_1 item_id=item_1 field_id=field_1\n", buf.String())
+	})
+
+	t.Run("structured", func(t *testing.T) {
+		buf.Reset()
+		c := &GraphQLClient{}
+		c.logFieldUpdate("Start date", "2024-01-01", "2024-02-01", true)
+		assert.Equal(t, "level=DEBUG msg=\"updating field value\" field=\"Start date\" old=2024-01-01 new=2024-02-01 dry_run=true\n", buf.String())
+	})
+}
+
+func TestGetProjectFieldConfigsAndIssuesReusesSourceProject(t *testing.T) {
+	for _, noCache := range []bool{false, true} {
+		t.Run(fmt.Sprintf("no cache %v", noCache), func(t *testing.T) {
+			var mu sync.Mutex
+			requests := make(map[string]int)
+			c := newTestClient(t, func(req GraphQLRequest) string {
+				projectID, _ := req.Variables["projectID"].(string)
+				mu.Lock()
+				requests[projectID]++
+				mu.Unlock()
+				return projectItemsResponse(projectID, false, "https://github.com/org/repo/issues/1")
+			})
+			c.noCache = noCache
+
+			for _, targetID := range []string{"target_1", "target_2"} {
+				_, _, sourceIssues, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", targetID)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, sourceIssues)
+			}
+
+			wantSourceRequests := 1
+			if noCache {
+				wantSourceRequests = 2
+			}
+			assert.Equal(t, map[string]int{"source": wantSourceRequests, "target_1": 1, "target_2": 1}, requests)
+		})
+	}
+}
+
+func TestGetProjectFieldValuesMatchesIssueURLVariants(t *testing.T) {
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		projectID, _ := req.Variables["projectID"].(string)
+		return projectItemsResponse(projectID, false, "https://github.com/Org/Repo/issues/1")
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+	require.NoError(t, err)
+
+	for _, issueURL := range []string{
+		"https://github.com/Org/Repo/issues/1",
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/1/",
+		"https://GITHUB.com/Org/Repo/issues/1",
+	} {
+		_, err := c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+		assert.NoError(t, err, "expected %s to match the issue", issueURL)
+		title, err := c.GetIssueTitle(context.Background(), issueURL)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, title)
+	}
+
+	_, err = c.GetProjectFieldValues(context.Background(), "target", "https://github.com/org/repo/issues/2", nil)
+	assert.Error(t, err)
+}
+
+func TestNewGraphQLClientUsesGraphQLURLFromEnvironment(t *testing.T) {
+	var requested []string
+	transport := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.String())
+		return GraphQLResponse(map[string]interface{}{
+			"organization": map[string]interface{}{"projectV2": map[string]interface{}{"id": "project"}},
+		}), nil
+	})
+	project := &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "org", ProjectNumber: 1}
+
+	for _, opts := range []Options{{}, {Host: "github.example.com"}} {
+		t.Setenv(GraphQLURLEnv, "http://proxy.internal:8080/graphql")
+		opts.Transport = transport
+		c, err := NewGraphQLClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), opts)
+		require.NoError(t, err)
+		_, err = c.GetProjectID(context.Background(), project)
+		require.NoError(t, err)
+	}
+
+	t.Setenv(GraphQLURLEnv, "")
+	c, err := NewGraphQLClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), Options{Host: "github.example.com", Transport: transport})
+	require.NoError(t, err)
+	_, err = c.GetProjectID(context.Background(), project)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"http://proxy.internal:8080/graphql",
+		"http://proxy.internal:8080/graphql",
+		"https://github.example.com/api/graphql",
+	}, requested)
+}
+
+func TestNewGraphQLClientRejectsInvalidGraphQLURL(t *testing.T) {
+	for _, endpoint := range []string{"github.example.com/api/graphql", "ftp://github.example.com/api/graphql", "https://", "http://[::1"} {
+		t.Setenv(GraphQLURLEnv, endpoint)
+		_, err := NewGraphQLClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), Options{})
+		assert.EqualError(t, err, `invalid GITHUB_GRAPHQL_URL "`+endpoint+`" (expected an http or https URL such as https://github.example.com/api/graphql)`)
+	}
+}
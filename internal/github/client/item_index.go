@@ -0,0 +1,60 @@
+package client
+
+import "github.com/naag/gh-project-toolkit/internal/github/util"
+
+// itemIndex maps the canonical URLs of the issues of a project to the position of their
+// item, so that issues are looked up without canonicalizing the URL of every item
+type itemIndex map[string]int
+
+// buildItemIndex indexes the issue items of a project. Of several items of an issue, the
+// first one is indexed, like a search through the items would find.
+func buildItemIndex(project *ProjectV2) itemIndex {
+	index := make(itemIndex, len(project.Items.Nodes))
+	for i := range project.Items.Nodes {
+		item := &project.Items.Nodes[i]
+		if !item.isIssue() {
+			continue
+		}
+		issueURL := util.CanonicalizeIssueURL(item.Content.Issue.URL)
+		if _, ok := index[issueURL]; !ok {
+			index[issueURL] = i
+		}
+	}
+	return index
+}
+
+// indexItems rebuilds the item index of a cached project after its items changed. The
+// caller must hold the lock.
+func (c *GraphQLClient) indexItems(project *ProjectV2) {
+	if c.cache.items == nil {
+		c.cache.items = make(map[*ProjectV2]itemIndex)
+	}
+	c.cache.items[project] = buildItemIndex(project)
+}
+
+// reindexItems rebuilds the item index of a project after its items were added or removed,
+// if the project is indexed. The caller must hold the lock.
+func (c *GraphQLClient) reindexItems(project *ProjectV2) {
+	if _, ok := c.cache.items[project]; ok {
+		c.indexItems(project)
+	}
+}
+
+// findItem finds the item of an issue in a project, or returns nil if the issue is not in
+// the project. Cached projects are looked up in their item index, other projects are
+// searched. The caller must hold the lock.
+func (c *GraphQLClient) findItem(project *ProjectV2, issueURL string) *ProjectV2Item {
+	if index, ok := c.cache.items[project]; ok {
+		if i, ok := index[util.CanonicalizeIssueURL(issueURL)]; ok {
+			return &project.Items.Nodes[i]
+		}
+		return nil
+	}
+
+	for i := range project.Items.Nodes {
+		if project.Items.Nodes[i].hasIssueURL(issueURL) {
+			return &project.Items.Nodes[i]
+		}
+	}
+	return nil
+}
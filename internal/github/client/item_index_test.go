@@ -0,0 +1,44 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindItemUsesIndexOfCachedProjects(t *testing.T) {
+	c := &GraphQLClient{}
+	project := &ProjectV2{ID: "project"}
+	project.Items.Nodes = []ProjectV2Item{
+		projectIssueItem("item_1", "https://github.com/Org/Repo/issues/1"),
+		projectIssueItem("item_2", "https://github.com/org/repo/issues/2"),
+	}
+	c.indexItems(project)
+
+	item := c.findItem(project, "https://github.com/org/repo/issues/1/")
+	require.NotNil(t, item, "expected variants of the issue URL to be found")
+	assert.Equal(t, "item_1", item.ID)
+	assert.Nil(t, c.findItem(project, "https://github.com/org/repo/issues/3"))
+
+	// Removing an item shifts the others, so the index must be rebuilt
+	project.Items.Nodes = project.Items.Nodes[1:]
+	c.reindexItems(project)
+	item = c.findItem(project, "https://github.com/org/repo/issues/2")
+	require.NotNil(t, item)
+	assert.Equal(t, "item_2", item.ID)
+	assert.Nil(t, c.findItem(project, "https://github.com/org/repo/issues/1"))
+}
+
+func TestFindItemSearchesProjectsWithoutIndex(t *testing.T) {
+	c := &GraphQLClient{}
+	project := &ProjectV2{ID: "project"}
+	project.Items.Nodes = []ProjectV2Item{projectIssueItem("item_1", "https://github.com/Org/Repo/issues/1")}
+
+	item := c.findItem(project, "https://github.com/org/repo/issues/1")
+	require.NotNil(t, item)
+	assert.Equal(t, "item_1", item.ID)
+
+	c.reindexItems(project)
+	assert.Empty(t, c.cache.items, "expected projects that are not cached to stay unindexed")
+}
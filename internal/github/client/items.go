@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// defaultItemPollInterval is the delay between checks whether a new item is queryable
+	defaultItemPollInterval = 500 * time.Millisecond
+	// defaultItemWaitTimeout bounds the wait for a new item to become queryable
+	defaultItemWaitTimeout = 10 * time.Second
+)
+
+// AddProjectItem implements the Client interface
+func (c *GraphQLClient) AddProjectItem(ctx context.Context, projectID string, issueURL string) (string, error) {
+	return c.AddIssueToProject(ctx, projectID, issueURL, false)
+}
+
+// AddIssueToProject implements the Client interface. Issues and pull requests can be added.
+// The node ID of the issue is resolved
+// in dry run mode as well, so that missing issues are reported, but no item is added and
+// the returned item ID is empty.
+func (c *GraphQLClient) AddIssueToProject(ctx context.Context, projectID string, issueURL string, dryRun bool) (string, error) {
+	var query struct {
+		Resource struct {
+			Issue struct {
+				ID string
+			} `graphql:"... on Issue"`
+			PullRequest struct {
+				ID string
+			} `graphql:"... on PullRequest"`
+		} `graphql:"resource(url: $url)"`
+	}
+
+	u, err := url.Parse(issueURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid issue URL %s: %w", issueURL, err)
+	}
+
+	if err := c.queryWithRetry(ctx, &query, map[string]interface{}{"url": githubv4.URI{URL: u}}); err != nil {
+		return "", fmt.Errorf("failed to query issue %s: %w", issueURL, err)
+	}
+	contentID := query.Resource.Issue.ID
+	if contentID == "" {
+		contentID = query.Resource.PullRequest.ID
+	}
+	if contentID == "" {
+		return "", fmt.Errorf("issue %s not found", issueURL)
+	}
+	if dryRun {
+		slog.Info("would add issue to project", "project_id", projectID, "issue", issueURL)
+		return "", nil
+	}
+
+	var mutation struct {
+		AddProjectV2ItemByID struct {
+			Item struct {
+				ID string
+			}
+		} `graphql:"addProjectV2ItemById(input: $input)"`
+	}
+
+	input := githubv4.AddProjectV2ItemByIdInput{
+		ProjectID: githubv4.ID(projectID),
+		ContentID: githubv4.ID(contentID),
+	}
+
+	if err := c.mutateOnce(ctx, &mutation, input, nil); err != nil {
+		return "", fmt.Errorf("failed to add issue %s to project: %w", issueURL, err)
+	}
+
+	itemID := mutation.AddProjectV2ItemByID.Item.ID
+	item, err := c.waitForItem(ctx, itemID)
+	if err != nil {
+		return "", err
+	}
+
+	// Add the item to the cached project so that its fields can be updated right away
+	c.mu.Lock()
+	for _, project := range []*ProjectV2{c.cache.sourceProject, c.cache.targetProject} {
+		if project != nil && project.ID == projectID {
+			project.Items.Nodes = append(project.Items.Nodes, *item)
+			c.reindexItems(project)
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	return itemID, nil
+}
+
+// waitForItem polls for a newly created project item until it can be queried. Items are
+// not always queryable right after creation, so updating their fields would fail otherwise.
+func (c *GraphQLClient) waitForItem(ctx context.Context, itemID string) (*ProjectV2Item, error) {
+	interval, timeout := c.itemPollInterval, c.itemWaitTimeout
+	if interval <= 0 {
+		interval = defaultItemPollInterval
+	}
+	if timeout <= 0 {
+		timeout = defaultItemWaitTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		var query struct {
+			Node struct {
+				Item ProjectV2Item `graphql:"... on ProjectV2Item"`
+			} `graphql:"node(id: $itemID)"`
+		}
+
+		err := c.queryWithRetry(ctx, &query, map[string]interface{}{"itemID": githubv4.ID(itemID)})
+		if err != nil && ctx.Err() == nil && !isNodeNotFound(err) {
+			return nil, fmt.Errorf("failed to query project item: %w", err)
+		}
+
+		if err == nil && query.Node.Item.ID != "" {
+			if attempt > 1 {
+				slog.Info("new project item became available", "item_id", itemID, "waited", time.Since(start).Round(time.Millisecond))
+			}
+			return &query.Node.Item, nil
+		}
+
+		slog.Debug("waiting for new project item to become available", "item_id", itemID, "attempt", attempt)
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("project item %s did not become available within %s: %w", itemID, timeout, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// isNodeNotFound reports whether the API could not resolve a node ID, as happens for
+// items that are not queryable yet
+func isNodeNotFound(err error) bool {
+	return strings.Contains(err.Error(), "Could not resolve to a node")
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestAddProjectItemWaitsForItem(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var mu sync.Mutex
+	itemQueries := 0
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "resource(url: $url)"):
+			return `{"data":{"resource":{"id":"issue_1"}}}`
+		case strings.Contains(req.Query, "addProjectV2ItemById("):
+			return `{"data":{"addProjectV2ItemById":{"item":{"id":"item_1"}}}}`
+		case strings.Contains(req.Query, "node(id: $itemID)"):
+			mu.Lock()
+			defer mu.Unlock()
+			itemQueries++
+			if itemQueries == 1 {
+				return `{"data":{"node":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a node with the global id of 'item_1'"}]}`
+			}
+			return `{"data":{"node":{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}}}`
+		default:
+			projectID, _ := req.Variables["projectID"].(string)
+			return projectItemsResponse(projectID, false)
+		}
+	})
+	c.itemPollInterval = time.Millisecond
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "source", "target")
+	require.NoError(t, err)
+
+	itemID, err := c.AddProjectItem(context.Background(), "target", issueURL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "item_1", itemID)
+	assert.Equal(t, 2, itemQueries, "expected the item to be queried until it appears")
+
+	foundID, _, err := c.findProjectItem(c.cache.targetProject, issueURL, github.ProjectField{Name: "Status"})
+	require.NoError(t, err, "expected the new item to be cached")
+	assert.Equal(t, "item_1", foundID)
+}
+
+func TestAddProjectItemTimesOut(t *testing.T) {
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "resource(url: $url)"):
+			return `{"data":{"resource":{"id":"issue_1"}}}`
+		case strings.Contains(req.Query, "addProjectV2ItemById("):
+			return `{"data":{"addProjectV2ItemById":{"item":{"id":"item_1"}}}}`
+		default:
+			return `{"data":{"node":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a node with the global id of 'item_1'"}]}`
+		}
+	})
+	c.itemPollInterval = time.Millisecond
+	c.itemWaitTimeout = 20 * time.Millisecond
+
+	_, err := c.AddProjectItem(context.Background(), "target", "https://github.com/org/repo/issues/1")
+	assert.ErrorContains(t, err, "did not become available")
+}
+
+func TestAddIssueToProjectDryRun(t *testing.T) {
+	var mutations int
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "resource(url: $url)"):
+			if strings.HasSuffix(req.Variables["url"].(string), "/2") {
+				return `{"data":{"resource":null}}`
+			}
+			return `{"data":{"resource":{"id":"issue_1"}}}`
+		default:
+			mutations++
+			return `{"data":{"addProjectV2ItemById":{"item":{"id":"item_1"}}}}`
+		}
+	})
+
+	itemID, err := c.AddIssueToProject(context.Background(), "target", "https://github.com/org/repo/issues/1", true)
+	require.NoError(t, err)
+	assert.Empty(t, itemID)
+	assert.Zero(t, mutations, "expected no item to be added in dry run mode")
+
+	// Missing issues are reported in dry run mode as well
+	_, err = c.AddIssueToProject(context.Background(), "target", "https://github.com/org/repo/issues/2", true)
+	assert.EqualError(t, err, "issue https://github.com/org/repo/issues/2 not found")
+}
+
+func TestAddIssueToProjectAddsPullRequests(t *testing.T) {
+	prURL := "https://github.com/org/repo/pull/3"
+
+	var contentID interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "resource(url: $url)"):
+			if !strings.Contains(req.Query, "... on PullRequest") {
+				return `{"data":{"resource":{}}}`
+			}
+			return `{"data":{"resource":{"id":"pr_3"}}}`
+		case strings.Contains(req.Query, "addProjectV2ItemById("):
+			contentID = req.Variables["input"].(map[string]interface{})["contentId"]
+			return `{"data":{"addProjectV2ItemById":{"item":{"id":"item_3"}}}}`
+		default:
+			return `{"data":{"node":{"id":"item_3","fieldValues":{"nodes":[]},"content":{"__typename":"PullRequest","url":"` + prURL + `","title":"Pull request"}}}}`
+		}
+	})
+
+	itemID, err := c.AddIssueToProject(context.Background(), "target", prURL, false)
+	require.NoError(t, err)
+	assert.Equal(t, "item_3", itemID)
+	assert.Equal(t, "pr_3", contentID)
+}
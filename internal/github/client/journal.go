@@ -0,0 +1,83 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournalEntry records a field change made by UpdateProjectField. Values are in the format
+// of github.ProjectFieldValue.String, an empty old value means the field had no value.
+type JournalEntry struct {
+	Time      time.Time `json:"time"`
+	ProjectID string    `json:"project_id"`
+	IssueURL  string    `json:"issue_url"`
+	Field     string    `json:"field"`
+	// FieldID tells apart fields sharing a name. Empty in journals of earlier versions.
+	FieldID  string `json:"field_id,omitempty"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// Journal appends a JSON line per field change to a writer, so that the changes of a run
+// can be undone
+type Journal struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewJournal returns a journal writing to w, which should be opened for appending
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{w: w, now: time.Now}
+}
+
+// Record appends an entry to the journal, setting its time if not set
+func (j *Journal) Record(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = j.now().UTC()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// ReadJournal reads the entries of a journal in the order they were recorded. Blank lines
+// are ignored, and malformed lines are reported with their line numbers.
+func ReadJournal(r io.Reader) ([]JournalEntry, error) {
+	var entries []JournalEntry
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if entry.ProjectID == "" || entry.IssueURL == "" || entry.Field == "" {
+			return nil, fmt.Errorf("line %d: missing project_id, issue_url or field", lineNumber)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestJournalRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	journal := NewJournal(&buf)
+	journal.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	entry := JournalEntry{ProjectID: "PVT_1", IssueURL: "https://github.com/org/repo/issues/1", Field: "Status", OldValue: "Todo", NewValue: "Done"}
+	require.NoError(t, journal.Record(entry))
+	require.NoError(t, journal.Record(entry))
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"), "expected one line per entry")
+
+	entries, err := ReadJournal(&buf)
+	require.NoError(t, err)
+	entry.Time = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []JournalEntry{entry, entry}, entries)
+
+	_, err = ReadJournal(strings.NewReader("\n{\"project_id\":\"PVT_1\"}\n"))
+	assert.EqualError(t, err, "line 2: missing project_id, issue_url or field")
+}
+
+func TestUpdateProjectFieldRecordsJournal(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		if strings.Contains(req.Query, "updateProjectV2ItemFieldValue(") {
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		}
+		return `{"data":{"node":{"id":"target","fields":{"nodes":[
+			{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status","options":[{"id":"opt_todo","name":"Todo"},{"id":"opt_done","name":"Done"}]}
+		]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[
+				{"__typename":"ProjectV2ItemFieldSingleSelectValue","field":{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status"},"name":"Todo"}
+			]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	})
+	var buf bytes.Buffer
+	c.journal = NewJournal(&buf)
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+
+	done := "Done"
+	field := github.ProjectField{Name: "Status", Value: github.ProjectFieldValue{Text: &done}}
+
+	// Dry runs change nothing, so nothing is recorded
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, true))
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+	// Unchanged values are not recorded either
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+
+	entries, err := ReadJournal(&buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "target", entries[0].ProjectID)
+	assert.Equal(t, issueURL, entries[0].IssueURL)
+	assert.Equal(t, "Status", entries[0].Field)
+	assert.Equal(t, "field_status", entries[0].FieldID, "expected the field ID to be recorded")
+	assert.Equal(t, "Todo", entries[0].OldValue)
+	assert.Equal(t, "Done", entries[0].NewValue)
+	assert.False(t, entries[0].Time.IsZero())
+}
@@ -0,0 +1,333 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// defaultLabelColor is the color of created labels, the gray GitHub picks for new labels
+const defaultLabelColor = "ededed"
+
+// issueLabel is a label of a repository
+type issueLabel struct {
+	ID   string
+	Name string
+}
+
+// labelable is an issue or pull request with its labels and repository
+type labelable struct {
+	ID         string
+	Repository struct {
+		ID            string
+		NameWithOwner string
+	}
+	Labels struct {
+		Nodes []issueLabel
+	} `graphql:"labels(first: 100)"`
+}
+
+// names returns the names of the labels of a labels field value
+func (v *ProjectV2ItemFieldLabelValue) names() []string {
+	names := make([]string, 0, len(v.Labels.Nodes))
+	for _, label := range v.Labels.Nodes {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// setNames replaces the labels of a labels field value
+func (v *ProjectV2ItemFieldLabelValue) setNames(names []string) {
+	v.Labels.Nodes = v.Labels.Nodes[:0]
+	for _, name := range names {
+		v.Labels.Nodes = append(v.Labels.Nodes, struct{ Name string }{Name: name})
+	}
+}
+
+// labelValueFor converts a single select value written to a labels field into the labels of
+// the issue with that label added, so that the other labels of the issue are kept
+func labelValueFor(dataType string, currentValue *ProjectV2ItemFieldValue, value github.ProjectFieldValue) github.ProjectFieldValue {
+	if dataType != "LABELS" || value.Labels != nil || value.Text == nil {
+		return value
+	}
+
+	var labels []string
+	if currentValue != nil {
+		labels = currentValue.LabelValue.names()
+	}
+	for _, label := range labels {
+		if strings.EqualFold(label, *value.Text) {
+			return github.ProjectFieldValue{Labels: labels}
+		}
+	}
+	return github.ProjectFieldValue{Labels: append(labels, *value.Text)}
+}
+
+// updateLabelField sets the labels of a labels field. The labels field of a project reflects
+// the labels of the underlying issue, so the issue's labels are updated instead of the item.
+func (c *GraphQLClient) updateLabelField(ctx context.Context, project *ProjectV2, issueURL string, currentValue *ProjectV2ItemFieldValue, field github.ProjectField, dryRun bool) error {
+	if field.Value.Labels == nil {
+		if !field.Value.IsEmpty() {
+			return fmt.Errorf("field %s of project holds labels, but the value %q is not a label", field.Name, field.Value)
+		}
+		// Labels are only removed by an empty list of labels, not by a missing value
+		slog.Debug("keeping labels without a value to set", "issue", issueURL, "field", field.Name)
+		return nil
+	}
+	if IsDraftIssueURL(issueURL) {
+		return fmt.Errorf("labels of draft issue %s cannot be synced", issueURL)
+	}
+
+	var current []string
+	if currentValue != nil {
+		current = currentValue.LabelValue.names()
+	}
+	c.logFieldUpdate(field.Name, strings.Join(current, ", "), field.Value.String(), dryRun,
+		"project_id", project.ID,
+	)
+
+	return c.SetIssueLabels(ctx, issueURL, field.Value.Labels, dryRun)
+}
+
+// SetIssueLabels implements the Client interface. The labels of the issue are updated in
+// the cached projects as well.
+func (c *GraphQLClient) SetIssueLabels(ctx context.Context, issueURL string, labels []string, dryRun bool) error {
+	labels, err := c.setIssueLabels(ctx, issueURL, labels, dryRun)
+	if err != nil || dryRun {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, project := range uniqueProjects(c.cache.sourceProject, c.cache.targetProject) {
+		if project == nil {
+			continue
+		}
+		item := c.findItem(project, issueURL)
+		if item == nil {
+			continue
+		}
+		item.Content.Issue.Labels.Nodes = item.Content.Issue.Labels.Nodes[:0]
+		for _, label := range labels {
+			item.Content.Issue.Labels.Nodes = append(item.Content.Issue.Labels.Nodes, struct{ Name string }{Name: label})
+		}
+		for j := range item.Fields.Nodes {
+			if item.Fields.Nodes[j].TypeName == "ProjectV2ItemFieldLabelValue" {
+				item.Fields.Nodes[j].LabelValue.setNames(labels)
+			}
+		}
+	}
+	return nil
+}
+
+// setIssueLabels replaces the labels of an issue and returns the labels it carries
+// afterwards. Labels missing in the repository of the issue are skipped with a warning,
+// or created if enabled. Labels are looked up in dry run mode as well.
+func (c *GraphQLClient) setIssueLabels(ctx context.Context, issueURL string, labels []string, dryRun bool) ([]string, error) {
+	issue, err := c.labelableIssue(ctx, issueURL)
+	if err != nil {
+		return nil, err
+	}
+
+	want, err := c.resolveLabels(ctx, issue, labels, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	add, remove := diffLabels(issue.Labels.Nodes, want)
+
+	names := make([]string, 0, len(want))
+	for _, label := range want {
+		names = append(names, label.Name)
+	}
+	if dryRun || len(add) == 0 && len(remove) == 0 {
+		return names, nil
+	}
+
+	if len(add) > 0 {
+		var mutation struct {
+			AddLabelsToLabelable struct {
+				ClientMutationID string
+			} `graphql:"addLabelsToLabelable(input: $input)"`
+		}
+		input := githubv4.AddLabelsToLabelableInput{LabelableID: githubv4.ID(issue.ID), LabelIDs: add}
+		if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
+			return nil, fmt.Errorf("failed to add labels to %s: %w", issueURL, err)
+		}
+	}
+	if len(remove) > 0 {
+		var mutation struct {
+			RemoveLabelsFromLabelable struct {
+				ClientMutationID string
+			} `graphql:"removeLabelsFromLabelable(input: $input)"`
+		}
+		input := githubv4.RemoveLabelsFromLabelableInput{LabelableID: githubv4.ID(issue.ID), LabelIDs: remove}
+		if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
+			return nil, fmt.Errorf("failed to remove labels from %s: %w", issueURL, err)
+		}
+	}
+	return names, nil
+}
+
+// labelableIssue looks up an issue or pull request with its labels and repository
+func (c *GraphQLClient) labelableIssue(ctx context.Context, issueURL string) (labelable, error) {
+	u, err := url.Parse(issueURL)
+	if err != nil {
+		return labelable{}, fmt.Errorf("invalid issue URL %s: %w", issueURL, err)
+	}
+
+	var query struct {
+		Resource struct {
+			Issue       labelable `graphql:"... on Issue"`
+			PullRequest labelable `graphql:"... on PullRequest"`
+		} `graphql:"resource(url: $url)"`
+	}
+	if err := c.queryWithRetry(ctx, &query, map[string]interface{}{"url": githubv4.URI{URL: u}}); err != nil {
+		return labelable{}, fmt.Errorf("failed to query labels of %s: %w", issueURL, err)
+	}
+
+	switch {
+	case query.Resource.Issue.ID != "":
+		return query.Resource.Issue, nil
+	case query.Resource.PullRequest.ID != "":
+		return query.Resource.PullRequest, nil
+	default:
+		return labelable{}, issueNotFound(issueURL)
+	}
+}
+
+// resolveLabels looks up the labels with the given names in the repository of an issue,
+// skipping or creating missing labels. In dry run mode, labels are not created.
+func (c *GraphQLClient) resolveLabels(ctx context.Context, issue labelable, names []string, dryRun bool) ([]issueLabel, error) {
+	repoLabels, err := c.repositoryLabels(ctx, issue.Repository.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]issueLabel, 0, len(names))
+	for _, name := range names {
+		label, ok := repoLabels[strings.ToLower(name)]
+		switch {
+		case ok:
+		case !c.createLabels:
+			slog.Warn("skipping label missing in repository",
+				"label", name,
+				"repository", issue.Repository.NameWithOwner,
+			)
+			continue
+		case dryRun:
+			slog.Info("would create label", "label", name, "repository", issue.Repository.NameWithOwner)
+			label = issueLabel{Name: name}
+		default:
+			if label, err = c.createLabel(ctx, issue.Repository.ID, name); err != nil {
+				return nil, err
+			}
+			slog.Info("created label", "label", name, "repository", issue.Repository.NameWithOwner)
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// diffLabels returns the IDs of the labels to add and to remove to turn current into want.
+// Label names are compared ignoring case, like GitHub does.
+func diffLabels(current, want []issueLabel) (add, remove []githubv4.ID) {
+	currentSet := make(map[string]bool, len(current))
+	for _, label := range current {
+		currentSet[strings.ToLower(label.Name)] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, label := range want {
+		wantSet[strings.ToLower(label.Name)] = true
+		if !currentSet[strings.ToLower(label.Name)] {
+			add = append(add, githubv4.ID(label.ID))
+		}
+	}
+	for _, label := range current {
+		if !wantSet[strings.ToLower(label.Name)] {
+			remove = append(remove, githubv4.ID(label.ID))
+		}
+	}
+	return add, remove
+}
+
+// repositoryLabels returns the labels of a repository by their lowercased name. The labels
+// of each repository are only loaded once.
+func (c *GraphQLClient) repositoryLabels(ctx context.Context, repositoryID string) (map[string]issueLabel, error) {
+	c.mu.RLock()
+	labels, ok := c.cache.repositoryLabels[repositoryID]
+	c.mu.RUnlock()
+	if ok {
+		return labels, nil
+	}
+
+	var query struct {
+		Node struct {
+			Repository struct {
+				Labels struct {
+					Nodes    []issueLabel
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				} `graphql:"labels(first: 100, after: $cursor)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $repositoryID)"`
+	}
+
+	labels = make(map[string]issueLabel)
+	variables := map[string]interface{}{
+		"repositoryID": githubv4.ID(repositoryID),
+		"cursor":       (*githubv4.String)(nil),
+	}
+	for {
+		if err := c.queryWithRetry(ctx, &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to query repository labels: %w", err)
+		}
+		page := query.Node.Repository.Labels
+		for _, label := range page.Nodes {
+			labels[strings.ToLower(label.Name)] = label
+		}
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(githubv4.String(page.PageInfo.EndCursor))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache.repositoryLabels == nil {
+		c.cache.repositoryLabels = make(map[string]map[string]issueLabel)
+	}
+	c.cache.repositoryLabels[repositoryID] = labels
+	return labels, nil
+}
+
+// createLabel creates a label in a repository and adds it to the cached labels
+func (c *GraphQLClient) createLabel(ctx context.Context, repositoryID, name string) (issueLabel, error) {
+	var mutation struct {
+		CreateLabel struct {
+			Label issueLabel
+		} `graphql:"createLabel(input: $input)"`
+	}
+	input := githubv4.CreateLabelInput{
+		RepositoryID: githubv4.ID(repositoryID),
+		Name:         githubv4.String(name),
+		Color:        defaultLabelColor,
+	}
+	// Creating a label is not idempotent, so it is not retried
+	if err := c.mutateOnce(ctx, &mutation, input, nil); err != nil {
+		return issueLabel{}, fmt.Errorf("failed to create label %q: %w", name, err)
+	}
+
+	label := mutation.CreateLabel.Label
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.repositoryLabels[repositoryID][strings.ToLower(label.Name)] = label
+	return label, nil
+}
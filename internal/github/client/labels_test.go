@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// labelsTestClient returns a client with a cached project holding an issue labeled bug,
+// whose repository has the labels bug and team-a. The mutations sent are recorded by name.
+func labelsTestClient(t *testing.T) (*GraphQLClient, *[]string, *[]map[string]interface{}) {
+	t.Helper()
+
+	var names []string
+	var inputs []map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "resource(url: $url)"):
+			return `{"data":{"resource":{"id":"issue_1","repository":{"id":"repo_1","nameWithOwner":"org/repo"},"labels":{"nodes":[
+				{"id":"label_bug","name":"bug"}
+			]}}}}`
+		case strings.Contains(req.Query, "node(id: $repositoryID)"):
+			return `{"data":{"node":{"labels":{"nodes":[
+				{"id":"label_bug","name":"bug"},
+				{"id":"label_team_a","name":"team-a"}
+			],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+		case strings.HasPrefix(req.Query, "mutation"):
+			inputs = append(inputs, req.Variables["input"].(map[string]interface{}))
+			if strings.Contains(req.Query, "createLabel(") {
+				names = append(names, "createLabel")
+				return `{"data":{"createLabel":{"label":{"id":"label_new","name":"team-b"}}}}`
+			}
+			for _, name := range []string{"addLabelsToLabelable", "removeLabelsFromLabelable"} {
+				if strings.Contains(req.Query, name+"(") {
+					names = append(names, name)
+					return `{"data":{"` + name + `":{"clientMutationId":""}}}`
+				}
+			}
+			return `{"data":{}}`
+		default:
+			return `{"data":{"node":{"id":"target","fields":{"nodes":[
+				{"__typename":"ProjectV2Field","id":"field_labels","name":"Labels","dataType":"LABELS"}
+			]},"items":{"nodes":[
+				{"id":"item_1","fieldValues":{"nodes":[
+					{"__typename":"ProjectV2ItemFieldLabelValue","field":{"__typename":"ProjectV2Field","id":"field_labels","name":"Labels"},"labels":{"nodes":[{"name":"bug"}]}}
+				]},"content":{"__typename":"Issue","id":"issue_1","url":"https://github.com/org/repo/issues/1","title":"Issue"}}
+			],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+		}
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+	return c, &names, &inputs
+}
+
+func TestUpdateProjectFieldAddsLabel(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c, names, inputs := labelsTestClient(t)
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "bug", fields[0].Value.String())
+
+	// Single select values are added as a label, matched case-insensitively
+	team := "Team-A"
+	field := github.ProjectField{Name: "Labels", Value: github.ProjectFieldValue{Text: &team}}
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+	assert.Equal(t, []string{"addLabelsToLabelable"}, *names)
+	assert.Equal(t, map[string]interface{}{"labelableId": "issue_1", "labelIds": []interface{}{"label_team_a"}}, (*inputs)[0])
+
+	fields, err = c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "bug, team-a", fields[0].Value.String(), "expected the cache to be updated")
+
+	// A label the issue already carries is not added again
+	require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+	assert.Len(t, *names, 1)
+}
+
+func TestUpdateProjectFieldReplacesLabels(t *testing.T) {
+	c, names, inputs := labelsTestClient(t)
+
+	err := c.UpdateProjectField(context.Background(), "target", "https://github.com/org/repo/issues/1", github.ProjectField{
+		Name:  "Labels",
+		Value: github.ProjectFieldValue{Labels: []string{"team-a"}},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"addLabelsToLabelable", "removeLabelsFromLabelable"}, *names)
+	assert.Equal(t, []interface{}{"label_team_a"}, (*inputs)[0]["labelIds"])
+	assert.Equal(t, []interface{}{"label_bug"}, (*inputs)[1]["labelIds"])
+}
+
+func TestUpdateProjectFieldMissingLabels(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	team := "team-b"
+	field := github.ProjectField{Name: "Labels", Value: github.ProjectFieldValue{Text: &team}}
+
+	t.Run("missing labels are skipped", func(t *testing.T) {
+		c, names, _ := labelsTestClient(t)
+		require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+		assert.Empty(t, *names)
+	})
+
+	t.Run("missing labels are not created in dry run mode", func(t *testing.T) {
+		c, names, _ := labelsTestClient(t)
+		c.createLabels = true
+		require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, true))
+		assert.Empty(t, *names)
+	})
+
+	t.Run("missing labels are created if enabled", func(t *testing.T) {
+		c, names, inputs := labelsTestClient(t)
+		c.createLabels = true
+		require.NoError(t, c.UpdateProjectField(context.Background(), "target", issueURL, field, false))
+		assert.Equal(t, []string{"createLabel", "addLabelsToLabelable"}, *names)
+		assert.Equal(t, map[string]interface{}{"repositoryId": "repo_1", "name": "team-b", "color": defaultLabelColor}, (*inputs)[0])
+		assert.Equal(t, []interface{}{"label_new"}, (*inputs)[1]["labelIds"])
+	})
+}
+
+func TestUpdateProjectFieldKeepsLabelsWithoutValue(t *testing.T) {
+	c, names, _ := labelsTestClient(t)
+
+	err := c.UpdateProjectField(context.Background(), "target", "https://github.com/org/repo/issues/1", github.ProjectField{
+		Name: "Labels",
+	}, false)
+	require.NoError(t, err)
+	assert.Empty(t, *names)
+}
+
+func TestSetIssueLabels(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c, names, _ := labelsTestClient(t)
+
+	// Dry runs only look up the labels
+	require.NoError(t, c.SetIssueLabels(context.Background(), issueURL, []string{"team-a"}, true))
+	assert.Empty(t, *names)
+
+	require.NoError(t, c.SetIssueLabels(context.Background(), issueURL, []string{"team-a"}, false))
+	assert.Equal(t, []string{"addLabelsToLabelable", "removeLabelsFromLabelable"}, *names)
+
+	labels, err := c.GetIssueLabels(context.Background(), issueURL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, labels, "expected the cached issue to be updated")
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", fields[0].Value.String(), "expected the cached labels field to be updated")
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// mutationLimiter spaces out mutations shared by concurrent callers, so that a burst of
+// updates does not trigger the secondary rate limits of GitHub. Every mutation waits at
+// least the interval after the previous one, plus up to half the interval of jitter.
+type mutationLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newMutationLimiter returns a limiter for the given interval, or nil if it is not positive
+func newMutationLimiter(interval time.Duration) *mutationLimiter {
+	if interval <= 0 {
+		return nil
+	}
+	return &mutationLimiter{interval: interval}
+}
+
+// wait blocks until the next mutation may be sent, or the context is done. A nil limiter
+// does not wait.
+func (l *mutationLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval + rand.N(l.interval/2+1))
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationLimiterSpacesOutMutations(t *testing.T) {
+	interval := 20 * time.Millisecond
+	l := newMutationLimiter(interval)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 2*interval, "expected the third mutation to wait for two intervals")
+}
+
+func TestMutationLimiterDisabled(t *testing.T) {
+	l := newMutationLimiter(0)
+	assert.Nil(t, l)
+	assert.NoError(t, l.wait(context.Background()), "expected a nil limiter not to wait")
+}
+
+func TestMutationLimiterCanceled(t *testing.T) {
+	l := newMutationLimiter(time.Hour)
+	require.NoError(t, l.wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.wait(ctx), context.Canceled)
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// fieldDataType returns the data type of a project field, such as DATE, ASSIGNEES or
+// MILESTONE, or an empty string for single select and unknown fields. The field is matched
+// by its ID if given and else by its name. The caller must hold the lock.
+func fieldDataType(project *ProjectV2, field github.ProjectField) string {
+	for _, f := range project.Fields.Nodes {
+		if f.TypeName == "ProjectV2Field" && matchesField(field, f.DateField.ID, f.DateField.Name) {
+			return f.DateField.DataType
+		}
+	}
+	return ""
+}
+
+// issueNodeID returns the node ID of an issue in a project, or an empty string if the
+// issue is not in the project or is a draft issue. The caller must hold the lock.
+func (c *GraphQLClient) issueNodeID(project *ProjectV2, issueURL string) string {
+	if item := c.findItem(project, issueURL); item != nil {
+		return item.Content.Issue.ID
+	}
+	return ""
+}
+
+// milestoneValueFor converts between milestone titles and single select values, which are
+// both set by name, depending on the data type of the target field
+func milestoneValueFor(dataType string, value github.ProjectFieldValue) github.ProjectFieldValue {
+	switch {
+	case dataType == "MILESTONE" && value.Text != nil && value.Milestone == nil:
+		return github.ProjectFieldValue{Milestone: value.Text}
+	case dataType != "MILESTONE" && value.Milestone != nil:
+		return github.ProjectFieldValue{Text: value.Milestone}
+	default:
+		return value
+	}
+}
+
+// updateMilestoneField sets the milestone of an issue. The milestone field of a project
+// reflects the milestone of the underlying issue, so the issue is updated instead of the item.
+// The milestone is looked up in dry run mode as well, so that missing milestones are reported.
+func (c *GraphQLClient) updateMilestoneField(ctx context.Context, project *ProjectV2, issueURL string, currentValue *ProjectV2ItemFieldValue, field github.ProjectField, dryRun bool) error {
+	if field.Value.Milestone == nil {
+		return fmt.Errorf("field %s of project holds milestones, but the value %q is not a milestone title", field.Name, field.Value)
+	}
+
+	if IsDraftIssueURL(issueURL) {
+		return fmt.Errorf("draft issue %s cannot have a milestone", issueURL)
+	}
+	if IsPullRequestURL(issueURL) {
+		return fmt.Errorf("milestone of pull request %s cannot be synced", issueURL)
+	}
+
+	c.mu.RLock()
+	issueID := c.issueNodeID(project, issueURL)
+	c.mu.RUnlock()
+	if issueID == "" {
+		return issueNotFound(issueURL)
+	}
+
+	milestoneID, err := c.findMilestone(ctx, issueID, *field.Value.Milestone)
+	if err != nil {
+		return err
+	}
+
+	oldValue, newValue := c.getFieldUpdateValues(currentValue, field)
+	c.logFieldUpdate(field.Name, oldValue, newValue, dryRun,
+		"project_id", project.ID,
+		"issue_id", issueID,
+		"milestone_id", milestoneID,
+	)
+
+	if dryRun {
+		return nil
+	}
+
+	var mutation struct {
+		UpdateIssue struct {
+			Issue struct {
+				ID string
+			}
+		} `graphql:"updateIssue(input: $input)"`
+	}
+	id := githubv4.ID(milestoneID)
+	input := githubv4.UpdateIssueInput{
+		ID:          githubv4.ID(issueID),
+		MilestoneID: &id,
+	}
+	if err := c.mutateWithRetry(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to set milestone of %s: %w", issueURL, err)
+	}
+
+	c.updateCacheFieldValue(project, issueURL, field)
+	return nil
+}
+
+// findMilestone looks up a milestone by its title in the repository of an issue
+func (c *GraphQLClient) findMilestone(ctx context.Context, issueID, title string) (string, error) {
+	var query struct {
+		Node struct {
+			Issue struct {
+				Repository struct {
+					NameWithOwner string
+					Milestones    struct {
+						Nodes []struct {
+							ID    string
+							Title string
+						}
+					} `graphql:"milestones(first: 100, query: $title)"`
+				}
+			} `graphql:"... on Issue"`
+		} `graphql:"node(id: $issueID)"`
+	}
+
+	variables := map[string]interface{}{
+		"issueID": githubv4.ID(issueID),
+		"title":   githubv4.String(title),
+	}
+	if err := c.queryWithRetry(ctx, &query, variables); err != nil {
+		return "", fmt.Errorf("failed to query milestones: %w", err)
+	}
+
+	// The query matches titles partially, so look for the exact title
+	repository := query.Node.Issue.Repository
+	for _, milestone := range repository.Milestones.Nodes {
+		if milestone.Title == title {
+			return milestone.ID, nil
+		}
+	}
+	return "", fmt.Errorf("milestone %q does not exist in repository %s", title, repository.NameWithOwner)
+}
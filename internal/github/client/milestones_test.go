@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// milestonesTestClient returns a client with a cached project holding an issue in milestone
+// v1.0, whose repository has the milestones v1.0 and v1.1
+func milestonesTestClient(t *testing.T) (*GraphQLClient, *[]map[string]interface{}) {
+	t.Helper()
+
+	var mutations []map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "milestones(first: 100, query: $title)"):
+			return `{"data":{"node":{"repository":{"nameWithOwner":"org/repo","milestones":{"nodes":[
+				{"id":"milestone_10","title":"v1.0"},
+				{"id":"milestone_11","title":"v1.1"}
+			]}}}}}`
+		case strings.Contains(req.Query, "updateIssue("):
+			mutations = append(mutations, req.Variables["input"].(map[string]interface{}))
+			return `{"data":{"updateIssue":{"issue":{"id":"issue_1"}}}}`
+		default:
+			return `{"data":{"node":{"id":"target","fields":{"nodes":[
+				{"__typename":"ProjectV2Field","id":"field_milestone","name":"Milestone","dataType":"MILESTONE"}
+			]},"items":{"nodes":[
+				{"id":"item_1","fieldValues":{"nodes":[
+					{"__typename":"ProjectV2ItemFieldMilestoneValue","field":{"__typename":"ProjectV2Field","id":"field_milestone","name":"Milestone"},"milestone":{"title":"v1.0"}}
+				]},"content":{"__typename":"Issue","id":"issue_1","url":"https://github.com/org/repo/issues/1","title":"Issue"}}
+			],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+		}
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+	return c, &mutations
+}
+
+func TestUpdateProjectFieldSetsMilestone(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c, mutations := milestonesTestClient(t)
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "v1.0", fields[0].Value.String())
+
+	// Single select values are set as milestone titles
+	title := "v1.1"
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name:  "Milestone",
+		Value: github.ProjectFieldValue{Text: &title},
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, *mutations, 1)
+	assert.Equal(t, map[string]interface{}{"id": "issue_1", "milestoneId": "milestone_11"}, (*mutations)[0])
+
+	fields, err = c.GetProjectFieldValues(context.Background(), "target", issueURL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1", fields[0].Value.String(), "expected the cache to be updated")
+}
+
+func TestUpdateProjectFieldMilestoneDryRun(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	c, mutations := milestonesTestClient(t)
+
+	title := "v1.1"
+	err := c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name:  "Milestone",
+		Value: github.ProjectFieldValue{Milestone: &title},
+	}, true)
+	require.NoError(t, err)
+	assert.Empty(t, *mutations)
+
+	// Missing milestones are reported in dry run mode as well
+	missing := "v2.0"
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name:  "Milestone",
+		Value: github.ProjectFieldValue{Milestone: &missing},
+	}, true)
+	assert.EqualError(t, err, `milestone "v2.0" does not exist in repository org/repo`)
+}
@@ -2,18 +2,39 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/naag/gh-project-toolkit/internal/github"
 )
 
 type MockClient struct {
 	GetProjectIDFunc                    func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error)
+	GetProjectIDsFunc                   func(ctx context.Context, sourceProject, targetProject *github.ProjectInfo) (string, string, error)
 	GetProjectFieldsFunc                func(ctx context.Context, projectID string, issueURL string) ([]github.ProjectField, error)
 	UpdateProjectFieldFunc              func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error
 	GetProjectIssuesFunc                func(ctx context.Context, projectID string) ([]string, error)
+	GetProjectIssuesFilteredFunc        func(ctx context.Context, projectID string, filter string) ([]string, error)
 	GetProjectFieldConfigsAndIssuesFunc func(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error)
+	GetProjectFieldConfigsFunc          func(ctx context.Context, projectID string) ([]github.ProjectFieldConfig, error)
 	GetProjectFieldValuesFunc           func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error)
 	GetIssueTitleFunc                   func(ctx context.Context, issueURL string) (string, error)
+	GetIssueTitlesFunc                  func(ctx context.Context, issueURLs []string) (map[string]string, error)
+	GetIssueLabelsFunc                  func(ctx context.Context, issueURL string) ([]string, error)
+	SetIssueLabelsFunc                  func(ctx context.Context, issueURL string, labels []string, dryRun bool) error
+	GetIssueUpdatedAtFunc               func(ctx context.Context, issueURL string) (time.Time, error)
+	GetIssueStateFunc                   func(ctx context.Context, issueURL string) (string, error)
+	RateLimitStatusFunc                 func() github.RateLimitStatus
+	DuplicateIssueCountFunc             func() int
+	APICallCountFunc                    func() int
+	QueryCostFunc                       func() int
+	ClearProjectFieldFunc               func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error
+	DeleteProjectItemFunc               func(ctx context.Context, projectID string, issueURL string) error
+	CreateSingleSelectOptionFunc        func(ctx context.Context, projectID, fieldID, optionName string) error
+	HostFunc                            func() string
+	ServerFilterFunc                    func() string
+	AddProjectItemFunc                  func(ctx context.Context, projectID string, issueURL string) (string, error)
+	AddIssueToProjectFunc               func(ctx context.Context, projectID string, issueURL string, dryRun bool) (string, error)
+	CreateProjectFieldFunc              func(ctx context.Context, projectID string, cfg github.ProjectFieldConfig) (github.ProjectFieldConfig, error)
 }
 
 func (c *MockClient) GetProjectID(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
@@ -23,6 +44,23 @@ func (c *MockClient) GetProjectID(ctx context.Context, projectInfo *github.Proje
 	return "", nil
 }
 
+// GetProjectIDs implements the Client interface. Unless GetProjectIDsFunc is set, the IDs are
+// resolved one by one with GetProjectID.
+func (c *MockClient) GetProjectIDs(ctx context.Context, sourceProject, targetProject *github.ProjectInfo) (string, string, error) {
+	if c.GetProjectIDsFunc != nil {
+		return c.GetProjectIDsFunc(ctx, sourceProject, targetProject)
+	}
+	sourceID, err := c.GetProjectID(ctx, sourceProject)
+	if err != nil {
+		return "", "", err
+	}
+	targetID, err := c.GetProjectID(ctx, targetProject)
+	if err != nil {
+		return "", "", err
+	}
+	return sourceID, targetID, nil
+}
+
 func (c *MockClient) GetProjectFields(ctx context.Context, projectID string, issueURL string) ([]github.ProjectField, error) {
 	if c.GetProjectFieldsFunc != nil {
 		return c.GetProjectFieldsFunc(ctx, projectID, issueURL)
@@ -46,6 +84,14 @@ func (c *MockClient) GetProjectIssues(ctx context.Context, projectID string) ([]
 	return nil, nil
 }
 
+// GetProjectIssuesFiltered implements the Client interface
+func (c *MockClient) GetProjectIssuesFiltered(ctx context.Context, projectID string, filter string) ([]string, error) {
+	if c.GetProjectIssuesFilteredFunc != nil {
+		return c.GetProjectIssuesFilteredFunc(ctx, projectID, filter)
+	}
+	return nil, nil
+}
+
 // GetProjectFieldConfigsAndIssues implements the Client interface
 func (c *MockClient) GetProjectFieldConfigsAndIssues(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
 	if c.GetProjectFieldConfigsAndIssuesFunc != nil {
@@ -54,6 +100,14 @@ func (c *MockClient) GetProjectFieldConfigsAndIssues(ctx context.Context, source
 	return nil, nil, nil, nil, nil
 }
 
+// GetProjectFieldConfigs implements the Client interface
+func (c *MockClient) GetProjectFieldConfigs(ctx context.Context, projectID string) ([]github.ProjectFieldConfig, error) {
+	if c.GetProjectFieldConfigsFunc != nil {
+		return c.GetProjectFieldConfigsFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
 // GetProjectFieldValues implements the Client interface
 func (c *MockClient) GetProjectFieldValues(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.
 	ProjectField, error) {
@@ -70,3 +124,139 @@ func (c *MockClient) GetIssueTitle(ctx context.Context, issueURL string) (string
 	}
 	return "", nil
 }
+
+// GetIssueTitles implements the Client interface
+func (c *MockClient) GetIssueTitles(ctx context.Context, issueURLs []string) (map[string]string, error) {
+	if c.GetIssueTitlesFunc != nil {
+		return c.GetIssueTitlesFunc(ctx, issueURLs)
+	}
+	return nil, nil
+}
+
+// GetIssueLabels implements the Client interface
+func (c *MockClient) GetIssueLabels(ctx context.Context, issueURL string) ([]string, error) {
+	if c.GetIssueLabelsFunc != nil {
+		return c.GetIssueLabelsFunc(ctx, issueURL)
+	}
+	return nil, nil
+}
+
+// SetIssueLabels implements the Client interface
+func (c *MockClient) SetIssueLabels(ctx context.Context, issueURL string, labels []string, dryRun bool) error {
+	if c.SetIssueLabelsFunc != nil {
+		return c.SetIssueLabelsFunc(ctx, issueURL, labels, dryRun)
+	}
+	return nil
+}
+
+// GetIssueUpdatedAt implements the Client interface
+func (c *MockClient) GetIssueUpdatedAt(ctx context.Context, issueURL string) (time.Time, error) {
+	if c.GetIssueUpdatedAtFunc != nil {
+		return c.GetIssueUpdatedAtFunc(ctx, issueURL)
+	}
+	return time.Time{}, nil
+}
+
+// GetIssueState implements the Client interface
+func (c *MockClient) GetIssueState(ctx context.Context, issueURL string) (string, error) {
+	if c.GetIssueStateFunc != nil {
+		return c.GetIssueStateFunc(ctx, issueURL)
+	}
+	return "", nil
+}
+
+// RateLimitStatus implements the Client interface
+func (c *MockClient) RateLimitStatus() github.RateLimitStatus {
+	if c.RateLimitStatusFunc != nil {
+		return c.RateLimitStatusFunc()
+	}
+	return github.RateLimitStatus{}
+}
+
+// DuplicateIssueCount implements the Client interface
+func (c *MockClient) DuplicateIssueCount() int {
+	if c.DuplicateIssueCountFunc != nil {
+		return c.DuplicateIssueCountFunc()
+	}
+	return 0
+}
+
+// APICallCount implements the Client interface
+func (c *MockClient) APICallCount() int {
+	if c.APICallCountFunc != nil {
+		return c.APICallCountFunc()
+	}
+	return 0
+}
+
+// QueryCost implements the Client interface
+func (c *MockClient) QueryCost() int {
+	if c.QueryCostFunc != nil {
+		return c.QueryCostFunc()
+	}
+	return 0
+}
+
+// ClearProjectField implements the Client interface
+func (c *MockClient) ClearProjectField(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+	if c.ClearProjectFieldFunc != nil {
+		return c.ClearProjectFieldFunc(ctx, projectID, issueURL, field, dryRun)
+	}
+	return nil
+}
+
+// DeleteProjectItem implements the Client interface
+func (c *MockClient) DeleteProjectItem(ctx context.Context, projectID string, issueURL string) error {
+	if c.DeleteProjectItemFunc != nil {
+		return c.DeleteProjectItemFunc(ctx, projectID, issueURL)
+	}
+	return nil
+}
+
+// CreateSingleSelectOption implements the Client interface
+func (c *MockClient) CreateSingleSelectOption(ctx context.Context, projectID, fieldID, optionName string) error {
+	if c.CreateSingleSelectOptionFunc != nil {
+		return c.CreateSingleSelectOptionFunc(ctx, projectID, fieldID, optionName)
+	}
+	return nil
+}
+
+// Host implements the Client interface
+func (c *MockClient) Host() string {
+	if c.HostFunc != nil {
+		return c.HostFunc()
+	}
+	return github.DefaultHost
+}
+
+// ServerFilter implements the Client interface
+func (c *MockClient) ServerFilter() string {
+	if c.ServerFilterFunc != nil {
+		return c.ServerFilterFunc()
+	}
+	return ""
+}
+
+// AddProjectItem implements the Client interface
+func (c *MockClient) AddProjectItem(ctx context.Context, projectID string, issueURL string) (string, error) {
+	if c.AddProjectItemFunc != nil {
+		return c.AddProjectItemFunc(ctx, projectID, issueURL)
+	}
+	return "", nil
+}
+
+// AddIssueToProject implements the Client interface
+func (c *MockClient) AddIssueToProject(ctx context.Context, projectID string, issueURL string, dryRun bool) (string, error) {
+	if c.AddIssueToProjectFunc != nil {
+		return c.AddIssueToProjectFunc(ctx, projectID, issueURL, dryRun)
+	}
+	return "", nil
+}
+
+// CreateProjectField implements the Client interface
+func (c *MockClient) CreateProjectField(ctx context.Context, projectID string, cfg github.ProjectFieldConfig) (github.ProjectFieldConfig, error) {
+	if c.CreateProjectFieldFunc != nil {
+		return c.CreateProjectFieldFunc(ctx, projectID, cfg)
+	}
+	return github.ProjectFieldConfig{}, nil
+}
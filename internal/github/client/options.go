@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// defaultOptionColor is the color of single select options created by the toolkit
+const defaultOptionColor = githubv4.ProjectV2SingleSelectFieldOptionColorGray
+
+// UpdateProjectV2FieldInput is the input of the updateProjectV2Field mutation, which is
+// not yet part of the githubv4 package
+type UpdateProjectV2FieldInput struct {
+	FieldID             githubv4.ID                    `json:"fieldId"`
+	SingleSelectOptions []singleSelectFieldOptionInput `json:"singleSelectOptions,omitempty"`
+}
+
+// singleSelectFieldOptionInput describes a single select option. Existing options are
+// passed with their ID so that they are kept along with the values set to them.
+type singleSelectFieldOptionInput struct {
+	ID          *githubv4.ID                                   `json:"id,omitempty"`
+	Name        githubv4.String                                `json:"name"`
+	Color       githubv4.ProjectV2SingleSelectFieldOptionColor `json:"color"`
+	Description githubv4.String                                `json:"description"`
+}
+
+// singleSelectOption is a single select option as returned by the API
+type singleSelectOption struct {
+	ID          string
+	Name        string
+	Color       githubv4.ProjectV2SingleSelectFieldOptionColor
+	Description string
+}
+
+// CreateSingleSelectOption implements the Client interface
+func (c *GraphQLClient) CreateSingleSelectOption(ctx context.Context, projectID, fieldID, optionName string) error {
+	// Serialize option creation, as each update replaces all options of the field
+	c.optionsMu.Lock()
+	defer c.optionsMu.Unlock()
+
+	// Load the current options to preserve their colors and descriptions
+	var query struct {
+		Node struct {
+			Field struct {
+				Name    string
+				Options []singleSelectOption
+			} `graphql:"... on ProjectV2SingleSelectField"`
+		} `graphql:"node(id: $fieldID)"`
+	}
+
+	if err := c.queryWithRetry(ctx, &query, map[string]interface{}{"fieldID": githubv4.ID(fieldID)}); err != nil {
+		return fmt.Errorf("failed to query field options: %w", err)
+	}
+
+	options := make([]singleSelectFieldOptionInput, 0, len(query.Node.Field.Options)+1)
+	for _, option := range query.Node.Field.Options {
+		if option.Name == optionName {
+			// The option was created in the meantime, so only refresh the cache
+			c.refreshFieldOptions(projectID, fieldID, query.Node.Field.Options)
+			return nil
+		}
+		id := githubv4.ID(option.ID)
+		options = append(options, singleSelectFieldOptionInput{
+			ID:          &id,
+			Name:        githubv4.String(option.Name),
+			Color:       option.Color,
+			Description: githubv4.String(option.Description),
+		})
+	}
+	options = append(options, singleSelectFieldOptionInput{
+		Name:  githubv4.String(optionName),
+		Color: defaultOptionColor,
+	})
+
+	var mutation struct {
+		UpdateProjectV2Field struct {
+			ProjectV2Field struct {
+				SingleSelectField struct {
+					Options []singleSelectOption
+				} `graphql:"... on ProjectV2SingleSelectField"`
+			}
+		} `graphql:"updateProjectV2Field(input: $input)"`
+	}
+
+	input := UpdateProjectV2FieldInput{
+		FieldID:             githubv4.ID(fieldID),
+		SingleSelectOptions: options,
+	}
+
+	if err := c.mutateOnce(ctx, &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to create option %q in field %q: %w", optionName, query.Node.Field.Name, err)
+	}
+
+	slog.Info("created single select option", "field", query.Node.Field.Name, "option", optionName)
+
+	c.refreshFieldOptions(projectID, fieldID, mutation.UpdateProjectV2Field.ProjectV2Field.SingleSelectField.Options)
+	return nil
+}
+
+// refreshFieldOptions replaces the options of a single select field in the cached project
+// and rebuilds the option index of the target project
+func (c *GraphQLClient) refreshFieldOptions(projectID, fieldID string, options []singleSelectOption) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, project := range []*ProjectV2{c.cache.sourceProject, c.cache.targetProject} {
+		if project == nil || project.ID != projectID {
+			continue
+		}
+		for i, field := range project.Fields.Nodes {
+			if field.TypeName != "ProjectV2SingleSelectField" || field.SingleSelectField.ID != fieldID {
+				continue
+			}
+			project.Fields.Nodes[i].SingleSelectField.Options = nil
+			for _, option := range options {
+				project.Fields.Nodes[i].SingleSelectField.Options = append(project.Fields.Nodes[i].SingleSelectField.Options, struct {
+					ID   string
+					Name string
+				}{ID: option.ID, Name: option.Name})
+			}
+		}
+	}
+
+	if c.cache.targetProject != nil && c.cache.targetProject.ID == projectID {
+		c.cache.targetOptions = buildOptionIndex(c.cache.targetProject)
+	}
+}
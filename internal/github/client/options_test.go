@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestUpdateProjectFieldCreatesMissingOption(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var fieldInput, itemInput map[string]interface{}
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		switch {
+		case strings.Contains(req.Query, "updateProjectV2Field("):
+			fieldInput, _ = req.Variables["input"].(map[string]interface{})
+			return `{"data":{"updateProjectV2Field":{"projectV2Field":{"options":[
+				{"id":"opt_todo","name":"Todo","color":"BLUE","description":"Not started"},
+				{"id":"opt_done","name":"Done","color":"GRAY","description":""}
+			]}}}}`
+		case strings.Contains(req.Query, "updateProjectV2ItemFieldValue("):
+			itemInput, _ = req.Variables["input"].(map[string]interface{})
+			return `{"data":{"updateProjectV2ItemFieldValue":{"clientMutationId":""}}}`
+		case strings.Contains(req.Query, "node(id: $fieldID)"):
+			return `{"data":{"node":{"name":"Status","options":[
+				{"id":"opt_todo","name":"Todo","color":"BLUE","description":"Not started"}
+			]}}}`
+		default:
+			return `{"data":{"node":{"id":"target","fields":{"nodes":[
+				{"__typename":"ProjectV2SingleSelectField","id":"field_status","name":"Status","options":[{"id":"opt_todo","name":"Todo"}]}
+			]},"items":{"nodes":[
+				{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","url":"` + issueURL + `","title":"Issue"}}
+			],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+		}
+	})
+	c.createOptions = true
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "target", "target")
+	require.NoError(t, err)
+
+	done := "Done"
+	err = c.UpdateProjectField(context.Background(), "target", issueURL, github.ProjectField{
+		Name:  "Status",
+		Value: github.ProjectFieldValue{Text: &done},
+	}, false)
+	require.NoError(t, err)
+
+	require.NotNil(t, fieldInput, "expected the field options to be updated")
+	assert.Equal(t, "field_status", fieldInput["fieldId"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "opt_todo", "name": "Todo", "color": "BLUE", "description": "Not started"},
+		map[string]interface{}{"name": "Done", "color": "GRAY", "description": ""},
+	}, fieldInput["singleSelectOptions"])
+
+	require.NotNil(t, itemInput, "expected the item value to be set")
+	assert.Equal(t, map[string]interface{}{"singleSelectOptionId": "opt_done"}, itemInput["value"])
+	assert.Equal(t, "opt_done", c.optionsFor(c.cache.targetProject).lookup("field_status", "Done", false))
+}
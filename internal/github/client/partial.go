@@ -0,0 +1,137 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// graphQLError is an error of a GraphQL response, with the path of the field it concerns
+type graphQLError struct {
+	Message string
+	Type    string
+	Path    []interface{}
+}
+
+// pathString formats the path of an error such as node.items.nodes.3.content
+func (e graphQLError) pathString() string {
+	parts := make([]string, len(e.Path))
+	for i, part := range e.Path {
+		parts[i] = fmt.Sprint(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// responseErrors records the errors of a GraphQL response and whether it held data. The
+// GraphQL library only returns the message of the first error, so the response is decoded
+// again by partialResponseTransport.
+type responseErrors struct {
+	mu      sync.Mutex
+	hasData bool
+	errors  []graphQLError
+}
+
+type responseErrorsKey struct{}
+
+// withResponseErrors returns a context that records the errors of the response to a
+// request sent with it
+func withResponseErrors(ctx context.Context, recorder *responseErrors) context.Context {
+	return context.WithValue(ctx, responseErrorsKey{}, recorder)
+}
+
+// partial reports whether the response held data and all of its errors concern fields whose
+// loss leaves the data we need intact, see safe. Any other error, such as one on the items,
+// fields or page info of a project or on the project itself, or an error without a path,
+// leaves the data incomplete.
+func (r *responseErrors) partial() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.hasData || len(r.errors) == 0 {
+		return false
+	}
+	for _, e := range r.errors {
+		if !e.safe() {
+			return false
+		}
+	}
+	return true
+}
+
+// safe reports whether an error concerns a field that may be null without losing data we
+// need: the content of a single item, as in node.items.nodes.3.content, which is then
+// skipped like an item that is not an issue, or a list of labels, which is then empty
+func (e graphQLError) safe() bool {
+	n := len(e.Path)
+	if n == 0 {
+		return false
+	}
+	if e.Path[n-1] == "labels" {
+		return true
+	}
+	if n < 3 || e.Path[n-1] != "content" || e.Path[n-3] != "nodes" {
+		return false
+	}
+	_, isIndex := e.Path[n-2].(float64)
+	return isIndex
+}
+
+// describe lists the paths and messages of the errors for logging
+func (r *responseErrors) describe() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	descriptions := make([]string, 0, len(r.errors))
+	for _, e := range r.errors {
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", e.pathString(), e.Message))
+	}
+	return descriptions
+}
+
+// partialResponseTransport records the errors of GraphQL responses to requests whose
+// context carries a responseErrors recorder
+type partialResponseTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *partialResponseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder, ok := req.Context().Value(responseErrorsKey{}).(*responseErrors)
+	if !ok || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var out struct {
+		Data   json.RawMessage
+		Errors []graphQLError
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		// The GraphQL library reports malformed responses
+		return resp, nil
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.hasData = len(out.Data) > 0 && string(out.Data) != "null"
+	recorder.errors = out.Errors
+	return resp, nil
+}
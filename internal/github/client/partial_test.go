@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryProceedsWithPartialResponse(t *testing.T) {
+	// The content of the second item is in a repository the token cannot access
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		return `{"data":{"node":{"id":"project","fields":{"nodes":[]},"items":{"nodes":[
+			{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"issue_1","url":"https://github.com/org/repo/issues/1","title":"Issue"}},
+			{"id":"item_2","fieldValues":{"nodes":[]},"content":null}
+		],"pageInfo":{"hasNextPage":false,"endCursor":""}}}},
+		"errors":[{"type":"FORBIDDEN","path":["node","items","nodes",1,"content"],"message":"Resource not accessible by integration"}]}`
+	})
+
+	_, _, sourceIssues, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, sourceIssues)
+}
+
+func TestQueryFailsOnTopLevelErrors(t *testing.T) {
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		return `{"data":{"node":null},"errors":[{"type":"NOT_FOUND","path":["node"],"message":"Could not resolve to a node with the global id of 'project'"}]}`
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+	assert.ErrorContains(t, err, "Could not resolve to a node")
+}
+
+func TestQueryFailsOnItemsErrors(t *testing.T) {
+	// Without its items, the project would look like it holds fewer issues than it does
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		return `{"data":{"node":{"id":"project","fields":{"nodes":[]},"items":null}},
+		"errors":[{"type":"INTERNAL","path":["node","items"],"message":"Something went wrong"}]}`
+	})
+
+	_, _, _, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+	assert.ErrorContains(t, err, "Something went wrong")
+}
+
+func TestResponseErrorsPartial(t *testing.T) {
+	content := graphQLError{Message: "forbidden", Path: []interface{}{"node", "items", "nodes", float64(1), "content"}}
+	labels := graphQLError{Message: "forbidden", Path: []interface{}{"node", "items", "nodes", float64(1), "content", "labels"}}
+	topLevel := graphQLError{Message: "not found", Path: []interface{}{"node"}}
+
+	tests := []struct {
+		name     string
+		recorder *responseErrors
+		want     bool
+	}{
+		{name: "item content", recorder: &responseErrors{hasData: true, errors: []graphQLError{content}}, want: true},
+		{name: "labels", recorder: &responseErrors{hasData: true, errors: []graphQLError{content, labels}}, want: true},
+		{name: "without data", recorder: &responseErrors{errors: []graphQLError{content}}, want: false},
+		{name: "top-level error", recorder: &responseErrors{hasData: true, errors: []graphQLError{content, topLevel}}, want: false},
+		{name: "items", recorder: &responseErrors{hasData: true, errors: []graphQLError{{Path: []interface{}{"node", "items"}}}}, want: false},
+		{name: "fields", recorder: &responseErrors{hasData: true, errors: []graphQLError{{Path: []interface{}{"node", "fields"}}}}, want: false},
+		{name: "page info", recorder: &responseErrors{hasData: true, errors: []graphQLError{{Path: []interface{}{"node", "items", "pageInfo"}}}}, want: false},
+		{name: "field values of an item", recorder: &responseErrors{hasData: true, errors: []graphQLError{{Path: []interface{}{"node", "items", "nodes", float64(1), "fieldValues"}}}}, want: false},
+		{name: "error without path", recorder: &responseErrors{hasData: true, errors: []graphQLError{{Message: "boom"}}}, want: false},
+		{name: "no errors", recorder: &responseErrors{hasData: true}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.recorder.partial())
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// projectIDKey returns the key of a project in the project ID cache. Logins are case
+// insensitive, so they are lowercased.
+func projectIDKey(projectInfo *github.ProjectInfo) string {
+	return fmt.Sprintf("%s/%s/%d", projectInfo.OwnerType, strings.ToLower(projectInfo.OwnerLogin), projectInfo.ProjectNumber)
+}
+
+// cachedProjectID returns the cached node ID of a project, if it was resolved before
+func (c *GraphQLClient) cachedProjectID(projectInfo *github.ProjectInfo) (string, bool) {
+	if c.noCache {
+		return "", false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.cache.projectIDs[projectIDKey(projectInfo)]
+	return id, ok
+}
+
+// cacheProjectID records the node ID of a project. Node IDs never change, so they are kept
+// for the lifetime of the client.
+func (c *GraphQLClient) cacheProjectID(projectInfo *github.ProjectInfo, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache.projectIDs == nil {
+		c.cache.projectIDs = make(map[string]string)
+	}
+	c.cache.projectIDs[projectIDKey(projectInfo)] = id
+}
+
+// GetProjectIDs implements the Client interface. Two projects of the same owner are resolved
+// in a single query, and IDs resolved before are taken from the cache.
+func (c *GraphQLClient) GetProjectIDs(ctx context.Context, sourceProject, targetProject *github.ProjectInfo) (string, string, error) {
+	sourceID, sourceCached := c.cachedProjectID(sourceProject)
+	targetID, targetCached := c.cachedProjectID(targetProject)
+	if sourceCached && targetCached {
+		return sourceID, targetID, nil
+	}
+
+	sameOwner := sourceProject.OwnerType == targetProject.OwnerType &&
+		strings.EqualFold(sourceProject.OwnerLogin, targetProject.OwnerLogin)
+	if !sourceCached && !targetCached && sameOwner && sourceProject.ProjectNumber != targetProject.ProjectNumber {
+		sourceID, targetID, err := c.getOwnerProjectIDs(ctx, sourceProject.OwnerType, sourceProject.OwnerLogin, sourceProject.ProjectNumber, targetProject.ProjectNumber)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get projects: %w", err)
+		}
+		c.cacheProjectID(sourceProject, sourceID)
+		c.cacheProjectID(targetProject, targetID)
+		return sourceID, targetID, nil
+	}
+
+	sourceID, err := c.GetProjectID(ctx, sourceProject)
+	if err != nil {
+		return "", "", err
+	}
+	targetID, err = c.GetProjectID(ctx, targetProject)
+	if err != nil {
+		return "", "", err
+	}
+	return sourceID, targetID, nil
+}
+
+// projectIDPair is the IDs of two projects of one owner, looked up with aliases
+type projectIDPair struct {
+	Source struct {
+		ID string
+	} `graphql:"source: projectV2(number: $sourceNumber)"`
+	Target struct {
+		ID string
+	} `graphql:"target: projectV2(number: $targetNumber)"`
+}
+
+// getOwnerProjectIDs looks up the IDs of two projects of the same organization or user in
+// one query
+func (c *GraphQLClient) getOwnerProjectIDs(ctx context.Context, ownerType github.ProjectOwnerType, login string, sourceNumber, targetNumber int) (string, string, error) {
+	if sourceNumber <= 0 {
+		return "", "", fmt.Errorf("invalid project number: %d", sourceNumber)
+	}
+	if targetNumber <= 0 {
+		return "", "", fmt.Errorf("invalid project number: %d", targetNumber)
+	}
+
+	slog.Debug("loading project IDs", "owner_type", ownerType, "owner_login", login, "source_number", sourceNumber, "target_number", targetNumber)
+
+	variables := map[string]interface{}{
+		"login":        githubv4.String(login),
+		"sourceNumber": githubv4.Int(sourceNumber),
+		"targetNumber": githubv4.Int(targetNumber),
+	}
+
+	var pair projectIDPair
+	var ownerPath string
+	switch ownerType {
+	case github.ProjectOwnerTypeOrg:
+		var query struct {
+			Organization projectIDPair `graphql:"organization(login: $login)"`
+		}
+		if err := c.queryWithRetry(ctx, &query, variables); err != nil {
+			return "", "", fmt.Errorf("failed to query organization projects: %w", err)
+		}
+		pair, ownerPath = query.Organization, "orgs/"+login
+	case github.ProjectOwnerTypeUser:
+		var query struct {
+			User projectIDPair `graphql:"user(login: $login)"`
+		}
+		if err := c.queryWithRetry(ctx, &query, variables); err != nil {
+			return "", "", fmt.Errorf("failed to query user projects: %w", err)
+		}
+		pair, ownerPath = query.User, "users/"+login
+	default:
+		return "", "", fmt.Errorf("invalid owner type")
+	}
+
+	if pair.Source.ID == "" {
+		return "", "", &NotFoundError{Kind: ErrProjectNotFound, Name: fmt.Sprintf("%s/projects/%d", ownerPath, sourceNumber)}
+	}
+	if pair.Target.ID == "" {
+		return "", "", &NotFoundError{Kind: ErrProjectNotFound, Name: fmt.Sprintf("%s/projects/%d", ownerPath, targetNumber)}
+	}
+	return pair.Source.ID, pair.Target.ID, nil
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestGetProjectIDsResolvesProjectsOfOneOwnerInOneQuery(t *testing.T) {
+	var queries []string
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		queries = append(queries, req.Query)
+		return `{"data":{"organization":{"source":{"id":"project_1"},"target":{"id":"project_2"}}}}`
+	})
+
+	source := &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "myorg", ProjectNumber: 824}
+	target := &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "MyOrg", ProjectNumber: 825}
+
+	sourceID, targetID, err := c.GetProjectIDs(context.Background(), source, target)
+	require.NoError(t, err)
+	assert.Equal(t, "project_1", sourceID)
+	assert.Equal(t, "project_2", targetID)
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "source: projectV2(number: $sourceNumber)")
+
+	// Resolved IDs are cached
+	sourceID, targetID, err = c.GetProjectIDs(context.Background(), source, target)
+	require.NoError(t, err)
+	assert.Equal(t, "project_1", sourceID)
+	assert.Equal(t, "project_2", targetID)
+	id, err := c.GetProjectID(context.Background(), target)
+	require.NoError(t, err)
+	assert.Equal(t, "project_2", id)
+	assert.Len(t, queries, 1)
+}
+
+func TestGetProjectIDsOfDifferentOwners(t *testing.T) {
+	var queries int
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		queries++
+		if strings.Contains(req.Query, "user(login: $login)") {
+			return `{"data":{"user":{"projectV2":{"id":"project_2"}}}}`
+		}
+		return `{"data":{"organization":{"projectV2":{"id":"project_1"}}}}`
+	})
+
+	source := &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "myorg", ProjectNumber: 1}
+	target := &github.ProjectInfo{OwnerType: github.ProjectOwnerTypeUser, OwnerLogin: "me", ProjectNumber: 1}
+
+	sourceID, targetID, err := c.GetProjectIDs(context.Background(), source, target)
+	require.NoError(t, err)
+	assert.Equal(t, "project_1", sourceID)
+	assert.Equal(t, "project_2", targetID)
+	assert.Equal(t, 2, queries)
+}
+
+func TestGetProjectIDsReportsMissingProject(t *testing.T) {
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		return `{"data":{"organization":{"source":{"id":"project_1"},"target":{"id":""}}}}`
+	})
+
+	_, _, err := c.GetProjectIDs(context.Background(),
+		&github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "myorg", ProjectNumber: 824},
+		&github.ProjectInfo{OwnerType: github.ProjectOwnerTypeOrg, OwnerLogin: "myorg", ProjectNumber: 999},
+	)
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "orgs/myorg/projects/999", notFound.Name)
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+// IsPullRequestURL reports whether a URL is the URL of a pull request, such as
+// https://github.com/owner/repo/pull/1
+func IsPullRequestURL(issueURL string) bool {
+	return strings.Contains(util.CanonicalizeIssueURL(issueURL), "/pull/")
+}
+
+// includePullRequests treats pull requests like issues if they are included, so that their
+// field values are synced like those of issues. The response decoder fills every fragment
+// with matching fields, so the issue fragment of excluded pull requests is cleared.
+func (c *GraphQLClient) includePullRequests(items []ProjectV2Item) {
+	for i := range items {
+		content := &items[i].Content
+		if content.TypeName != "PullRequest" {
+			continue
+		}
+		if c.includePRs {
+			content.Issue = content.PullRequest
+		} else {
+			content.Issue = ProjectV2ItemIssue{}
+		}
+	}
+}
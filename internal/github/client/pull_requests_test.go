@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestPullRequestsAreIncluded(t *testing.T) {
+	response := `{"data":{"node":{"id":"project","fields":{"nodes":[
+		{"__typename":"ProjectV2Field","id":"field_start","name":"Start","dataType":"DATE"},
+		{"__typename":"ProjectV2Field","id":"field_milestone","name":"Milestone","dataType":"MILESTONE"}
+	]},"items":{"nodes":[
+		{"id":"item_1","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"issue_1","url":"https://github.com/org/repo/issues/1","title":"Issue"}},
+		{"id":"item_2","fieldValues":{"nodes":[
+			{"__typename":"ProjectV2ItemFieldDateValue","field":{"__typename":"ProjectV2Field","id":"field_start","name":"Start"},"date":"2024-03-01"}
+		]},"content":{"__typename":"PullRequest","id":"pr_2","url":"https://github.com/org/repo/pull/2","title":"Fix the build"}}
+	],"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}`
+	prURL := "https://github.com/org/repo/pull/2"
+
+	t.Run("excluded by default", func(t *testing.T) {
+		c := newTestClient(t, func(req GraphQLRequest) string { return response })
+
+		_, _, sourceIssues, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, sourceIssues)
+	})
+
+	t.Run("included", func(t *testing.T) {
+		c := newTestClient(t, func(req GraphQLRequest) string { return response })
+		c.includePRs = true
+
+		_, _, sourceIssues, _, err := c.GetProjectFieldConfigsAndIssues(context.Background(), "project", "project")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://github.com/org/repo/issues/1", prURL}, sourceIssues)
+		assert.True(t, IsPullRequestURL(prURL))
+
+		fields, err := c.GetProjectFieldValues(context.Background(), "project", prURL, nil)
+		require.NoError(t, err)
+		require.Len(t, fields, 1)
+		assert.Equal(t, "2024-03-01", fields[0].Value.String())
+
+		title, err := c.GetIssueTitle(context.Background(), prURL)
+		require.NoError(t, err)
+		assert.Equal(t, "Fix the build", title)
+
+		milestone := "v1.0"
+		err = c.UpdateProjectField(context.Background(), "project", prURL, github.ProjectField{
+			Name:  "Milestone",
+			Value: github.ProjectFieldValue{Milestone: &milestone},
+		}, true)
+		assert.EqualError(t, err, "milestone of pull request "+prURL+" cannot be synced")
+	})
+}
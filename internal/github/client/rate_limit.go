@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// rateLimitThreshold is the remaining budget below which we pause until the limit resets
+const rateLimitThreshold = 100
+
+// rateLimit is selected alongside project queries to track the GraphQL rate limit budget
+type rateLimit struct {
+	Limit     int
+	Remaining int
+	ResetAt   githubv4.DateTime
+}
+
+// observeRateLimit records the rate limit reported by a query and, if enabled,
+// waits for the limit to reset once the remaining budget runs low
+func (c *GraphQLClient) observeRateLimit(ctx context.Context, rl rateLimit) error {
+	if rl.Limit == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.rateLimit = github.RateLimitStatus{
+		Limit:     rl.Limit,
+		Remaining: rl.Remaining,
+		ResetAt:   rl.ResetAt.Time,
+	}
+	c.mu.Unlock()
+
+	slog.Debug("GitHub rate limit status",
+		"remaining", rl.Remaining,
+		"limit", rl.Limit,
+		"reset_at", rl.ResetAt.Time,
+	)
+
+	if !c.respectRateLimit || rl.Remaining >= rateLimitThreshold {
+		return nil
+	}
+
+	wait := time.Until(rl.ResetAt.Time)
+	if wait <= 0 {
+		return nil
+	}
+
+	slog.Info("GitHub rate limit nearly exhausted, waiting for reset",
+		"remaining", rl.Remaining,
+		"reset_at", rl.ResetAt.Time,
+		"wait", wait.Round(time.Second),
+	)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RateLimitStatus implements the Client interface
+func (c *GraphQLClient) RateLimitStatus() github.RateLimitStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rateLimit
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// DefaultMaxRetries is the default number of retries for transient failures
+	DefaultMaxRetries = 3
+	// DefaultRetryBaseDelay is the default delay before the first retry
+	DefaultRetryBaseDelay = time.Second
+)
+
+// RetryConfig controls how transient GraphQL failures are retried
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the initial attempt
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled on every further retry
+	BaseDelay time.Duration
+}
+
+// backoff returns the delay before the given retry (starting at 0), with jitter applied
+func (r RetryConfig) backoff(retry int) time.Duration {
+	delay := r.BaseDelay * time.Duration(1<<retry)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryable reports whether an error is a transient failure worth retrying
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := err.Error()
+	if strings.Contains(msg, "non-200 OK status code: 5") {
+		return true
+	}
+	return strings.Contains(msg, "RATE_LIMITED") || strings.Contains(strings.ToLower(msg), "rate limit")
+}
+
+// withRetry runs fn, retrying transient failures with exponential backoff
+func (c *GraphQLClient) withRetry(ctx context.Context, operation string, fn func() error) error {
+	for retry := 0; ; retry++ {
+		err := fn()
+		if err == nil || retry >= c.retry.MaxRetries || !isRetryable(err) {
+			return err
+		}
+		// Timeouts of a canceled or expired context look like network timeouts, but must not be retried
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delay := c.retry.backoff(retry)
+		slog.Warn("retrying after transient GitHub API error",
+			"operation", operation,
+			"attempt", retry+1,
+			"delay", delay,
+			"error", err,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// queryWithRetry executes a GraphQL query, retrying transient failures. Responses with
+// data whose errors only concern nested fields are used as they are, with a warning.
+func (c *GraphQLClient) queryWithRetry(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	return c.withRetry(ctx, "query", func() error {
+		if err := c.countAPICall(); err != nil {
+			return err
+		}
+		recorder := &responseErrors{}
+		err := c.client.Query(withResponseErrors(ctx, recorder), q, variables)
+		if err != nil && recorder.partial() {
+			slog.Warn("proceeding with partial GraphQL response", "errors", recorder.describe())
+			return nil
+		}
+		return err
+	})
+}
+
+// mutateWithRetry executes a GraphQL mutation, retrying transient failures. Every attempt
+// waits for the mutation limiter first. Dry runs never get here, so they are not slowed down.
+func (c *GraphQLClient) mutateWithRetry(ctx context.Context, m interface{}, input githubv4.Input, variables map[string]interface{}) error {
+	return c.withRetry(ctx, "mutation", func() error {
+		if err := c.limiter.wait(ctx); err != nil {
+			return err
+		}
+		if err := c.countAPICall(); err != nil {
+			return err
+		}
+		return c.client.Mutate(ctx, m, input, variables)
+	})
+}
+
+// mutateOnce executes a GraphQL mutation that is not idempotent, such as one creating a
+// field, option or item, without retrying it. A failed attempt may still have succeeded on
+// the server, so a retry could create a duplicate. It waits for the mutation limiter first.
+func (c *GraphQLClient) mutateOnce(ctx context.Context, m interface{}, input githubv4.Input, variables map[string]interface{}) error {
+	if err := c.limiter.wait(ctx); err != nil {
+		return err
+	}
+	if err := c.countAPICall(); err != nil {
+		return err
+	}
+	return c.client.Mutate(ctx, m, input, variables)
+}
+
+// countAPICall counts a request sent to the GitHub API, including retries. Once the maximum
+// number of API calls is reached, it fails with ErrAPIBudgetExhausted instead, and the
+// request must not be sent.
+func (c *GraphQLClient) countAPICall() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxAPICalls > 0 && c.apiCalls >= c.maxAPICalls {
+		return fmt.Errorf("%w after %d calls", ErrAPIBudgetExhausted, c.apiCalls)
+	}
+	c.apiCalls++
+	return nil
+}
+
+// APICallCount implements the Client interface
+func (c *GraphQLClient) APICallCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.apiCalls
+}
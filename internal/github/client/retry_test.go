@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "server error",
+			err:  errors.New(`non-200 OK status code: 502 Bad Gateway body: ""`),
+			want: true,
+		},
+		{
+			name: "rate limited",
+			err:  errors.New("API rate limit exceeded for user ID 1."),
+			want: true,
+		},
+		{
+			name: "secondary rate limit",
+			err:  errors.New(`non-200 OK status code: 403 Forbidden body: "You have exceeded a secondary rate limit"`),
+			want: true,
+		},
+		{
+			name: "client error",
+			err:  errors.New(`non-200 OK status code: 401 Unauthorized body: ""`),
+			want: false,
+		},
+		{
+			name: "context canceled",
+			err:  context.Canceled,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isRetryable(tt.err))
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	transient := errors.New(`non-200 OK status code: 502 Bad Gateway body: ""`)
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		c := &GraphQLClient{retry: RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond}}
+		calls := 0
+		err := c.withRetry(context.Background(), "query", func() error {
+			calls++
+			if calls < 3 {
+				return transient
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		c := &GraphQLClient{retry: RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond}}
+		calls := 0
+		err := c.withRetry(context.Background(), "query", func() error {
+			calls++
+			return transient
+		})
+		assert.ErrorIs(t, err, transient)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		c := &GraphQLClient{retry: RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond}}
+		permanent := errors.New("field not found")
+		calls := 0
+		err := c.withRetry(context.Background(), "query", func() error {
+			calls++
+			return permanent
+		})
+		assert.ErrorIs(t, err, permanent)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("aborts on context cancellation", func(t *testing.T) {
+		c := &GraphQLClient{retry: RetryConfig{MaxRetries: 3, BaseDelay: time.Hour}}
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := c.withRetry(ctx, "query", func() error {
+			calls++
+			cancel()
+			return transient
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestAPICallCountIncludesRetries(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		requests++
+		if requests == 1 {
+			return `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`
+		}
+		return `{"data":{"viewer":{"login":"octocat"}}}`
+	})
+	c.retry = RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	var query struct {
+		Viewer struct {
+			Login string
+		}
+	}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, c.queryWithRetry(context.Background(), &query, nil))
+	}
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 3, c.APICallCount())
+}
+
+func TestMaxAPICallsStopsRequests(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		requests++
+		if requests == 1 {
+			return `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`
+		}
+		return `{"data":{"viewer":{"login":"octocat"}}}`
+	})
+	c.retry = RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond}
+	c.maxAPICalls = 3
+
+	var query struct {
+		Viewer struct {
+			Login string
+		}
+	}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, c.queryWithRetry(context.Background(), &query, nil))
+	}
+
+	err := c.queryWithRetry(context.Background(), &query, nil)
+	assert.ErrorIs(t, err, ErrAPIBudgetExhausted)
+	assert.EqualError(t, err, "API call budget exhausted after 3 calls")
+	assert.Equal(t, 3, requests, "expected no request beyond the budget")
+	assert.Equal(t, 3, c.APICallCount())
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// RoundTripFunc is an http.RoundTripper backed by a function. Set it as Options.Transport
+// to drive a GraphQLClient with canned responses, e.g. in tests.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// GraphQLRequest is the body of a GraphQL request
+type GraphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// DecodeGraphQLRequest decodes the body of a GraphQL request
+func DecodeGraphQLRequest(req *http.Request) (GraphQLRequest, error) {
+	var body GraphQLRequest
+	err := json.NewDecoder(req.Body).Decode(&body)
+	return body, err
+}
+
+// GraphQLResponse builds a successful HTTP response with the given GraphQL data, which is
+// marshaled to JSON, and optional GraphQL error messages
+func GraphQLResponse(data interface{}, errorMessages ...string) *http.Response {
+	body := map[string]interface{}{"data": data}
+	if len(errorMessages) > 0 {
+		errs := make([]map[string]string, 0, len(errorMessages))
+		for _, message := range errorMessages {
+			errs = append(errs, map[string]string{"message": message})
+		}
+		body["errors"] = errs
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		panic("failed to marshal GraphQL response: " + err.Error())
+	}
+	return HTTPResponse(http.StatusOK, string(payload))
+}
+
+// HTTPResponse builds an HTTP response with the given status code and JSON body
+func HTTPResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newRoundTripClient creates a client that is served by the given function
+func newRoundTripClient(t *testing.T, fn RoundTripFunc) *GraphQLClient {
+	t.Helper()
+
+	c, err := NewGraphQLClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), Options{
+		Retry:     RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond},
+		Transport: fn,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+// issueItem builds the GraphQL data of a project item for an issue
+func issueItem(url string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          "item_" + url,
+		"fieldValues": map[string]interface{}{"nodes": []interface{}{}},
+		"content":     map[string]interface{}{"__typename": "Issue", "url": url, "title": "Title of " + url},
+	}
+}
+
+// itemsPage builds the GraphQL data of a page of project items
+func itemsPage(hasNextPage bool, endCursor string, items ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"node": map[string]interface{}{
+			"items": map[string]interface{}{
+				"nodes":    items,
+				"pageInfo": map[string]interface{}{"hasNextPage": hasNextPage, "endCursor": endCursor},
+			},
+		},
+	}
+}
+
+func TestRoundTripFuncPagination(t *testing.T) {
+	var cursors []interface{}
+	c := newRoundTripClient(t, func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+		body, err := DecodeGraphQLRequest(req)
+		require.NoError(t, err)
+		cursors = append(cursors, body.Variables["afterCursor"])
+
+		if body.Variables["afterCursor"] == nil {
+			return GraphQLResponse(itemsPage(true, "page_2", issueItem("https://github.com/org/repo/issues/1"))), nil
+		}
+		return GraphQLResponse(itemsPage(false, "", issueItem("https://github.com/org/repo/issues/2"))), nil
+	})
+
+	issues, err := c.GetProjectIssues(context.Background(), "project")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1", "https://github.com/org/repo/issues/2"}, issues)
+	assert.Equal(t, []interface{}{nil, "page_2"}, cursors)
+}
+
+func TestRoundTripFuncRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newRoundTripClient(t, func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			return HTTPResponse(http.StatusBadGateway, ""), nil
+		}
+		return GraphQLResponse(itemsPage(false, "", issueItem("https://github.com/org/repo/issues/1"))), nil
+	})
+
+	issues, err := c.GetProjectIssues(context.Background(), "project")
+	require.NoError(t, err)
+
+	assert.Len(t, issues, 1)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRoundTripFuncDecodesFieldValues(t *testing.T) {
+	item := issueItem("https://github.com/org/repo/issues/1")
+	item["fieldValues"] = map[string]interface{}{"nodes": []interface{}{
+		map[string]interface{}{
+			"__typename": "ProjectV2ItemFieldDateValue",
+			"field":      map[string]interface{}{"__typename": "ProjectV2Field", "id": "field_start", "name": "Start date"},
+			"date":       "2024-03-01",
+		},
+		map[string]interface{}{
+			"__typename": "ProjectV2ItemFieldSingleSelectValue",
+			"field":      map[string]interface{}{"__typename": "ProjectV2SingleSelectField", "id": "field_status", "name": "Status"},
+			"name":       "Done",
+		},
+	}}
+
+	c := newRoundTripClient(t, func(req *http.Request) (*http.Response, error) {
+		return GraphQLResponse(map[string]interface{}{
+			"node": map[string]interface{}{
+				"id":     "project",
+				"fields": map[string]interface{}{"nodes": []interface{}{}},
+				"items": map[string]interface{}{
+					"nodes":    []interface{}{item},
+					"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+				},
+			},
+		}), nil
+	})
+
+	fields, err := c.GetProjectFieldValues(context.Background(), "project", "https://github.com/org/repo/issues/1", nil)
+	require.NoError(t, err)
+
+	require.Len(t, fields, 2)
+	assert.Equal(t, "Start date", fields[0].Name)
+	assert.Equal(t, "2024-03-01", fields[0].Value.String())
+	assert.Equal(t, "Status", fields[1].Name)
+	assert.Equal(t, "Done", fields[1].Value.String())
+}
+
+func TestRoundTripFuncGraphQLErrors(t *testing.T) {
+	c := newRoundTripClient(t, func(req *http.Request) (*http.Response, error) {
+		return GraphQLResponse(nil, "Could not resolve to a node with the global id of 'project'"), nil
+	})
+
+	_, err := c.GetProjectIssues(context.Background(), "project")
+	assert.ErrorContains(t, err, "Could not resolve to a node")
+}
+
+func TestRoundTripFuncHonorsDeadline(t *testing.T) {
+	var calls atomic.Int32
+	c := newRoundTripClient(t, func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		// Hang like a stuck request until the deadline expires
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetProjectIssues(ctx, "project")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load(), "expected no retries after the deadline")
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+// issueTitleBatchSize is the maximum number of issues looked up in a single query, which
+// keeps the query well below the node limit and complexity budget of the API
+const issueTitleBatchSize = 50
+
+// issueTitleNode is the result of a single issue lookup in a batched title query
+type issueTitleNode struct {
+	Issue struct {
+		Title string
+	} `graphql:"... on Issue"`
+}
+
+// GetIssueTitles implements the Client interface
+func (c *GraphQLClient) GetIssueTitles(ctx context.Context, issueURLs []string) (map[string]string, error) {
+	titles := make(map[string]string, len(issueURLs))
+	var missing []string
+
+	c.mu.RLock()
+	for _, issueURL := range issueURLs {
+		if issue := c.cachedIssue(issueURL); issue != nil {
+			titles[issueURL] = issue.Title
+		} else if title, ok := c.cache.issueTitles[util.CanonicalizeIssueURL(issueURL)]; ok {
+			titles[issueURL] = title
+		} else {
+			missing = append(missing, issueURL)
+		}
+	}
+	c.mu.RUnlock()
+
+	for start := 0; start < len(missing); start += issueTitleBatchSize {
+		end := min(start+issueTitleBatchSize, len(missing))
+		fetched, err := c.queryIssueTitles(ctx, missing[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		if c.cache.issueTitles == nil {
+			c.cache.issueTitles = make(map[string]string)
+		}
+		for issueURL, title := range fetched {
+			c.cache.issueTitles[util.CanonicalizeIssueURL(issueURL)] = title
+			titles[issueURL] = title
+		}
+		c.mu.Unlock()
+	}
+
+	return titles, nil
+}
+
+// queryIssueTitles looks up the titles of the given issues in a single query, using one
+// aliased resource lookup per issue. Issues that cannot be resolved are left out.
+func (c *GraphQLClient) queryIssueTitles(ctx context.Context, issueURLs []string) (map[string]string, error) {
+	// The number of lookups varies, so the query struct is built at runtime
+	fields := make([]reflect.StructField, 0, len(issueURLs))
+	variables := make(map[string]interface{}, len(issueURLs))
+	for i, issueURL := range issueURLs {
+		u, err := url.Parse(issueURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issue URL %s: %w", issueURL, err)
+		}
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Issue%d", i),
+			Type: reflect.TypeOf(issueTitleNode{}),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"issue%d: resource(url: $url%d)"`, i, i)),
+		})
+		variables[fmt.Sprintf("url%d", i)] = githubv4.URI{URL: u}
+	}
+
+	query := reflect.New(reflect.StructOf(fields))
+	slog.Debug("loading issue titles", "count", len(issueURLs))
+	if err := c.queryWithRetry(ctx, query.Interface(), variables); err != nil {
+		return nil, fmt.Errorf("failed to query issue titles: %w", err)
+	}
+
+	titles := make(map[string]string, len(issueURLs))
+	for i, issueURL := range issueURLs {
+		node := query.Elem().Field(i).Interface().(issueTitleNode)
+		if node.Issue.Title != "" {
+			titles[issueURL] = node.Issue.Title
+		}
+	}
+	return titles, nil
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIssueTitlesBatchesLookups(t *testing.T) {
+	var issueURLs []string
+	for i := 0; i < issueTitleBatchSize+10; i++ {
+		issueURLs = append(issueURLs, fmt.Sprintf("https://github.com/org/repo/issues/%d", i))
+	}
+
+	var mu sync.Mutex
+	var queries []GraphQLRequest
+	c := newTestClient(t, func(req GraphQLRequest) string {
+		mu.Lock()
+		queries = append(queries, req)
+		mu.Unlock()
+
+		data := make(map[string]interface{})
+		for name, value := range req.Variables {
+			alias := strings.Replace(name, "url", "issue", 1)
+			if value == issueURLs[1] {
+				// Unknown resources resolve to null
+				data[alias] = nil
+				continue
+			}
+			data[alias] = map[string]interface{}{"title": "Title of " + value.(string)}
+		}
+		body, _ := json.Marshal(map[string]interface{}{"data": data})
+		return string(body)
+	})
+
+	titles, err := c.GetIssueTitles(context.Background(), issueURLs)
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2, "expected the lookups to be split into two queries")
+	assert.Contains(t, queries[0].Query, "issue0: resource(url: $url0)")
+	assert.Len(t, queries[0].Variables, issueTitleBatchSize)
+	assert.Len(t, queries[1].Variables, 10)
+
+	assert.Len(t, titles, len(issueURLs)-1)
+	assert.Equal(t, "Title of "+issueURLs[0], titles[issueURLs[0]])
+	assert.NotContains(t, titles, issueURLs[1])
+
+	// Fetched titles are cached
+	title, err := c.GetIssueTitle(context.Background(), issueURLs[len(issueURLs)-1])
+	require.NoError(t, err)
+	assert.Equal(t, "Title of "+issueURLs[len(issueURLs)-1], title)
+
+	_, err = c.GetIssueTitles(context.Background(), issueURLs[:3])
+	require.NoError(t, err)
+	assert.Len(t, queries, 3, "expected only the unresolved issue to be looked up again")
+	assert.Len(t, queries[2].Variables, 1)
+}
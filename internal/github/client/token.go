@@ -0,0 +1,101 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TokenFromFile reads the GitHub token from a file, trimming surrounding whitespace
+func TokenFromFile(path string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// TokenFromEnvironment returns the GitHub token from the GITHUB_TOKEN environment variable,
+// falling back to the credentials of the gh CLI for the given host
+func TokenFromEnvironment(host string) (string, error) {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	token, cliErr := tokenFromGHCLI(host)
+	if cliErr == nil {
+		return token, nil
+	}
+
+	hostsFile := ghHostsFile()
+	token, fileErr := tokenFromHostsFile(hostsFile, host)
+	if fileErr == nil {
+		return token, nil
+	}
+
+	return "", fmt.Errorf("no GitHub token found: %w", errors.Join(
+		errors.New("GITHUB_TOKEN environment variable not set"),
+		fmt.Errorf("gh auth token: %w", cliErr),
+		fmt.Errorf("%s: %w", hostsFile, fileErr),
+	))
+}
+
+// tokenFromGHCLI reads the token of the given host from `gh auth token`
+func tokenFromGHCLI(host string) (string, error) {
+	out, err := exec.Command("gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errors.New("empty token")
+	}
+	return token, nil
+}
+
+// ghHostsFile returns the path of the gh CLI hosts file
+func ghHostsFile() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "gh", "hosts.yml")
+	}
+	return filepath.Join(home, ".config", "gh", "hosts.yml")
+}
+
+// tokenFromHostsFile reads the token of the given host from a gh CLI hosts file
+func tokenFromHostsFile(path string, host string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the gh CLI config location
+	if err != nil {
+		return "", err
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("failed to parse hosts file: %w", err)
+	}
+
+	token := hosts[host].OAuthToken
+	if token == "" {
+		return "", fmt.Errorf("no token for %s", host)
+	}
+	return token, nil
+}
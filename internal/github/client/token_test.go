@@ -0,0 +1,56 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestTokenFromHostsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.yml")
+	content := `github.com:
+    oauth_token: gho_secret
+    user: octocat
+    git_protocol: https
+ghe.example.com:
+    user: octocat
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	token, err := tokenFromHostsFile(path, "github.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "gho_secret", token)
+
+	_, err = tokenFromHostsFile(path, "ghe.example.com")
+	assert.ErrorContains(t, err, "no token for ghe.example.com")
+
+	_, err = tokenFromHostsFile(filepath.Join(t.TempDir(), "missing.yml"), "github.com")
+	assert.Error(t, err)
+}
+
+func TestTokenFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "token")
+	assert.NoError(t, os.WriteFile(path, []byte("  ghp_secret\n"), 0o600))
+	token, err := TokenFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghp_secret", token)
+
+	empty := filepath.Join(dir, "empty")
+	assert.NoError(t, os.WriteFile(empty, []byte("\n"), 0o600))
+	_, err = TokenFromFile(empty)
+	assert.ErrorContains(t, err, "is empty")
+}
+
+func TestTokenFromEnvironmentPrefersVariable(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env_token")
+
+	token, err := TokenFromEnvironment(github.DefaultHost)
+	assert.NoError(t, err)
+	assert.Equal(t, "env_token", token)
+}
@@ -1,9 +1,15 @@
 package github
 
 import (
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
+// DefaultHost is the host of github.com, used unless a GitHub Enterprise Server host is configured
+const DefaultHost = "github.com"
+
 type ProjectInfo struct {
 	OwnerType     ProjectOwnerType
 	OwnerLogin    string
@@ -13,18 +19,132 @@ type ProjectInfo struct {
 type ProjectFieldValue struct {
 	Date *time.Time
 	Text *string
+	// Users holds the logins of a user field, such as the assignees of an issue
+	Users []string
+	// Milestone holds the title of the milestone of an issue
+	Milestone *string
+	// Labels holds the names of the labels of an issue
+	Labels []string
+	// Number holds the value of a number field
+	Number *float64
+	// OptionID holds the ID of a single select option named by Text. When writing a value,
+	// the option is chosen by this ID instead of by its name, which options may share.
+	OptionID string
+	// Iteration holds the title of the iteration of an iteration field
+	Iteration *string
+	// IterationID holds the ID of the iteration named by Iteration, by which it is set
+	IterationID string
+}
+
+// IsEmpty reports whether the value holds no data
+func (v ProjectFieldValue) IsEmpty() bool {
+	return v.Date == nil && v.Text == nil && len(v.Users) == 0 && v.Milestone == nil && len(v.Labels) == 0 && v.Number == nil && v.Iteration == nil
+}
+
+// String formats the value for display, returning an empty string for empty values
+func (v ProjectFieldValue) String() string {
+	switch {
+	case v.Date != nil:
+		return v.Date.Format("2006-01-02")
+	case v.Text != nil:
+		return *v.Text
+	case len(v.Users) > 0:
+		return strings.Join(v.Users, ", ")
+	case v.Milestone != nil:
+		return *v.Milestone
+	case len(v.Labels) > 0:
+		return strings.Join(v.Labels, ", ")
+	case v.Number != nil:
+		return strconv.FormatFloat(*v.Number, 'f', -1, 64)
+	case v.Iteration != nil:
+		return *v.Iteration
+	default:
+		return ""
+	}
+}
+
+// SameLogins reports whether two lists of user logins contain the same users, in any order
+func SameLogins(a, b []string) bool {
+	return sameNames(a, b)
+}
+
+// SameLabels reports whether two lists of label names contain the same labels, in any order
+func SameLabels(a, b []string) bool {
+	return sameNames(a, b)
+}
+
+// sameNames reports whether two lists of names are equal ignoring order and case, as GitHub
+// compares logins and label names
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, name := range a {
+		counts[strings.ToLower(name)]++
+	}
+	for _, name := range b {
+		counts[strings.ToLower(name)]--
+		if counts[strings.ToLower(name)] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeOptionName strips leading emoji from a single select option name and trims and
+// collapses its whitespace, so that "🚧  In Progress " and "In Progress" are the same option
+func NormalizeOptionName(name string) string {
+	name = strings.TrimLeftFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.In(r, unicode.So, unicode.Sk, unicode.Variation_Selector) || r == '\u200d'
+	})
+	return strings.Join(strings.Fields(name), " ")
 }
 
 type ProjectField struct {
 	ID    string
 	Name  string
 	Value ProjectFieldValue
+	// DataType is the declared data type of the field when it is updated, such as DATE or
+	// SINGLE_SELECT. If set, it must match the field, and decides how the value is written.
+	DataType string
 }
 
 type ProjectFieldConfig struct {
-	ID   string
-	Name string
-	Type string // e.g., "ProjectV2Field", "ProjectV2SingleSelectField"
+	ID       string               `json:"id"`
+	Name     string               `json:"name"`
+	Type     string               `json:"type"`              // e.g., "ProjectV2Field", "ProjectV2SingleSelectField"
+	DataType string               `json:"data_type"`         // e.g., "DATE", "TEXT", "SINGLE_SELECT"
+	Options  []ProjectFieldOption `json:"options,omitempty"` // Only set for single select fields
+	// Iterations are the active and upcoming iterations, only set for iteration fields
+	Iterations []ProjectFieldIteration `json:"iterations,omitempty"`
+}
+
+// ProjectFieldOption is an option of a single select field
+type ProjectFieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ProjectFieldIteration is an iteration of an iteration field
+type ProjectFieldIteration struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	StartDate time.Time `json:"start_date"`
+	// Duration is the length of the iteration in days
+	Duration int `json:"duration"`
+}
+
+// EndDate returns the day after the last day of the iteration
+func (i ProjectFieldIteration) EndDate() time.Time {
+	return i.StartDate.AddDate(0, 0, i.Duration)
+}
+
+// RateLimitStatus describes the GitHub GraphQL API rate limit budget
+type RateLimitStatus struct {
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
 }
 
 type ProjectOwnerType string
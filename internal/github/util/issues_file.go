@@ -0,0 +1,55 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadIssuesFile reads issue URLs from a file, one per line. Blank lines and lines starting
+// with # are ignored. All malformed lines are reported in a single error.
+func ReadIssuesFile(path string, host string) ([]string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to open issues file: %w", err)
+	}
+	defer f.Close()
+
+	issues, err := ParseIssueList(f, host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issues file %s: %w", path, err)
+	}
+	return issues, nil
+}
+
+// ParseIssueList parses a list of issue URLs, one per line, ignoring blank lines and
+// lines starting with #
+func ParseIssueList(r io.Reader, host string) ([]string, error) {
+	var issues []string
+	var malformed []string
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := ValidateIssueURL(line, host); err != nil {
+			malformed = append(malformed, fmt.Sprintf("  line %d: %q: %v", lineNumber, line, err))
+			continue
+		}
+		issues = append(issues, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(malformed) > 0 {
+		return nil, fmt.Errorf("%d malformed issue URLs:\n%s", len(malformed), strings.Join(malformed, "\n"))
+	}
+	return issues, nil
+}
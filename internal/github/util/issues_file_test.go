@@ -0,0 +1,88 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIssueURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		host    string
+		wantErr string
+	}{
+		{name: "issue URL", url: "https://github.com/org/repo/issues/12"},
+		{name: "enterprise issue URL", url: "https://github.example.com/org/repo/issues/1", host: "github.example.com"},
+		{name: "pull request URL", url: "https://github.com/org/repo/pull/12", wantErr: "invalid issue URL format: expected https://github.com/OWNER/REPO/issues/NUMBER"},
+		{name: "other host", url: "https://gitlab.com/org/repo/issues/12", wantErr: "not a URL of GitHub host github.com"},
+		{name: "invalid number", url: "https://github.com/org/repo/issues/abc", wantErr: `invalid issue number "abc"`},
+		{name: "relative URL", url: "org/repo/issues/12", wantErr: "not an absolute URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIssueURL(tt.url, tt.host)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseIssueRepository(t *testing.T) {
+	repo, err := ParseIssueRepository("https://github.com/org/repo/issues/12")
+	require.NoError(t, err)
+	assert.Equal(t, "org/repo", repo)
+
+	repo, err = ParseIssueRepository("https://github.example.com/Org/Repo/issues/1/")
+	require.NoError(t, err)
+	assert.Equal(t, "Org/Repo", repo)
+
+	repo, err = ParseIssueRepository("https://github.com/org/repo/pull/3")
+	require.NoError(t, err)
+	assert.Equal(t, "org/repo", repo)
+
+	_, err = ParseIssueRepository("draft:Plan the launch")
+	assert.EqualError(t, err, "not an issue URL: draft:Plan the launch")
+}
+
+func TestParseIssueList(t *testing.T) {
+	input := strings.Join([]string{
+		"# Issues for the Q3 sync",
+		"https://github.com/org/repo/issues/1",
+		"",
+		"  https://github.com/org/repo/issues/2  ",
+		"https://github.com/org/repo/pull/3",
+		"not a url",
+	}, "\n")
+
+	_, err := ParseIssueList(strings.NewReader(input), "")
+	require.Error(t, err)
+	assert.Equal(t, `2 malformed issue URLs:
+  line 5: "https://github.com/org/repo/pull/3": invalid issue URL format: expected https://github.com/OWNER/REPO/issues/NUMBER
+  line 6: "not a url": not an absolute URL`, err.Error())
+
+	issues, err := ParseIssueList(strings.NewReader(strings.Join(strings.Split(input, "\n")[:4], "\n")), "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1", "https://github.com/org/repo/issues/2"}, issues)
+}
+
+func TestReadIssuesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.txt")
+	require.NoError(t, os.WriteFile(path, []byte("https://github.com/org/repo/issues/1\n"), 0o600))
+
+	issues, err := ReadIssuesFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/org/repo/issues/1"}, issues)
+
+	_, err = ReadIssuesFile(filepath.Join(t.TempDir(), "missing.txt"), "")
+	assert.ErrorContains(t, err, "failed to open issues file")
+}
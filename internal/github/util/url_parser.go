@@ -9,20 +9,36 @@ import (
 	"github.com/naag/gh-project-toolkit/internal/github"
 )
 
-func ParseProjectURL(projectURL string) (*github.ProjectInfo, error) {
+// ParseProjectURL parses the URL of a project on the given GitHub host, which defaults to
+// github.com if empty. URLs of other hosts are rejected. Errors name the offending part of
+// the URL and the URL itself.
+func ParseProjectURL(projectURL string, host string) (*github.ProjectInfo, error) {
 	u, err := url.Parse(projectURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	if u.Host != "github.com" {
-		return nil, fmt.Errorf("not a GitHub URL")
+	if host == "" {
+		host = github.DefaultHost
+	}
+	if !strings.EqualFold(u.Host, host) {
+		if host == github.DefaultHost {
+			return nil, fmt.Errorf("not a GitHub URL: %s", projectURL)
+		}
+		return nil, fmt.Errorf("not a URL of GitHub host %s: %s", host, projectURL)
 	}
 
-	// Split path into components
+	// Split path into components, ignoring a trailing view (e.g. /views/2) as
+	// copied from the browser. The query string is not part of the path.
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) == 6 && parts[4] == "views" {
+		if _, err := strconv.Atoi(parts[5]); err != nil {
+			return nil, fmt.Errorf("invalid view number %q in %s", parts[5], projectURL)
+		}
+		parts = parts[:4]
+	}
 	if len(parts) != 4 {
-		return nil, fmt.Errorf("invalid project URL format")
+		return nil, fmt.Errorf("invalid project URL format: %s (expected https://%s/orgs/OWNER/projects/NUMBER or https://%s/users/OWNER/projects/NUMBER)", projectURL, host, host)
 	}
 
 	// Check if it's an org or user project
@@ -33,17 +49,18 @@ func ParseProjectURL(projectURL string) (*github.ProjectInfo, error) {
 	case "users":
 		ownerType = github.ProjectOwnerTypeUser
 	default:
-		return nil, fmt.Errorf("invalid owner type in URL: %s", parts[0])
+		return nil, fmt.Errorf("invalid owner type %q in %s (expected orgs or users)", parts[0], projectURL)
 	}
 
 	// Parse project number
 	if parts[2] != "projects" {
-		return nil, fmt.Errorf("invalid URL format: expected 'projects' as third component")
+		return nil, fmt.Errorf("invalid URL format: expected 'projects' as third component, got %q in %s", parts[2], projectURL)
 	}
 
+	// Project numbers start at 1, so reject others before they are looked up
 	projectNum, err := strconv.Atoi(parts[3])
-	if err != nil {
-		return nil, fmt.Errorf("invalid project number: %w", err)
+	if err != nil || projectNum < 1 {
+		return nil, fmt.Errorf("invalid project number %q in %s (expected a positive number)", parts[3], projectURL)
 	}
 
 	return &github.ProjectInfo{
@@ -52,3 +69,91 @@ func ParseProjectURL(projectURL string) (*github.ProjectInfo, error) {
 		ProjectNumber: projectNum,
 	}, nil
 }
+
+// ValidateIssueURL checks that a URL is the URL of an issue on the given GitHub host, which
+// defaults to github.com if empty, such as https://github.com/owner/repo/issues/1
+func ValidateIssueURL(issueURL string, host string) error {
+	u, err := url.Parse(issueURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if host == "" {
+		host = github.DefaultHost
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("not an absolute URL")
+	}
+	if !strings.EqualFold(u.Host, host) {
+		return fmt.Errorf("not a URL of GitHub host %s", host)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] != "issues" {
+		return fmt.Errorf("invalid issue URL format: expected https://%s/OWNER/REPO/issues/NUMBER", host)
+	}
+	if number, err := strconv.Atoi(parts[3]); err != nil || number < 1 {
+		return fmt.Errorf("invalid issue number %q", parts[3])
+	}
+	return nil
+}
+
+// ParseIssueRepository returns the repository of an issue or pull request URL as owner/name,
+// such as org/repo for https://github.com/org/repo/issues/1
+func ParseIssueRepository(issueURL string) (string, error) {
+	u, err := url.Parse(issueURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || (parts[2] != "issues" && parts[2] != "pull") {
+		return "", fmt.Errorf("not an issue URL: %s", issueURL)
+	}
+	return parts[0] + "/" + parts[1], nil
+}
+
+// CanonicalizeIssueURL returns the canonical form of an issue URL for comparisons, so that
+// variants of the URL of one issue compare equal: the host, owner and repository are
+// lowercased, trailing slashes, query strings and fragments are dropped, and REST API URLs
+// such as https://api.github.com/repos/owner/repo/issues/1 are mapped to the web URL. Pull
+// request URLs are canonicalized the same way. Other URLs, such as the synthetic URLs of
+// draft issues, are returned unchanged.
+func CanonicalizeIssueURL(issueURL string) string {
+	u, err := url.Parse(strings.TrimSpace(issueURL))
+	if err != nil || u.Host == "" {
+		return issueURL
+	}
+
+	host, parts := webIssuePath(strings.ToLower(u.Host), strings.Split(strings.Trim(u.Path, "/"), "/"))
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" {
+		return issueURL
+	}
+	kind := parts[2]
+	switch kind {
+	case "issues", "pull":
+	case "pulls":
+		// REST API URLs of pull requests
+		kind = "pull"
+	default:
+		return issueURL
+	}
+	if _, err := strconv.Atoi(parts[3]); err != nil {
+		return issueURL
+	}
+	return "https://" + host + "/" + strings.ToLower(parts[0]) + "/" + strings.ToLower(parts[1]) + "/" + kind + "/" + parts[3]
+}
+
+// webIssuePath maps the host and path segments of a REST API URL to those of the web URL,
+// and returns those of other URLs unchanged
+func webIssuePath(host string, parts []string) (string, []string) {
+	switch {
+	case host == "api."+github.DefaultHost && len(parts) > 0 && parts[0] == "repos":
+		return github.DefaultHost, parts[1:]
+	case len(parts) > 3 && parts[0] == "api" && parts[1] == "v3" && parts[2] == "repos":
+		// REST API URLs of GitHub Enterprise Server
+		return host, parts[3:]
+	default:
+		return host, parts
+	}
+}
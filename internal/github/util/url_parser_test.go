@@ -11,6 +11,7 @@ func TestParseProjectURL(t *testing.T) {
 	tests := []struct {
 		name    string
 		url     string
+		host    string
 		want    *github.ProjectInfo
 		wantErr string
 	}{
@@ -32,6 +33,43 @@ func TestParseProjectURL(t *testing.T) {
 				ProjectNumber: 456,
 			},
 		},
+		{
+			name: "project URL with view",
+			url:  "https://github.com/orgs/testorg/projects/5/views/2",
+			want: &github.ProjectInfo{
+				OwnerType:     github.ProjectOwnerTypeOrg,
+				OwnerLogin:    "testorg",
+				ProjectNumber: 5,
+			},
+		},
+		{
+			name: "project URL with query parameters",
+			url:  "https://github.com/users/testuser/projects/456?query=is%3Aopen+sort%3Aupdated-desc&pane=issue",
+			want: &github.ProjectInfo{
+				OwnerType:     github.ProjectOwnerTypeUser,
+				OwnerLogin:    "testuser",
+				ProjectNumber: 456,
+			},
+		},
+		{
+			name: "project URL with view and query parameters",
+			url:  "https://github.com/orgs/testorg/projects/5/views/2/?filterQuery=status%3ADone",
+			want: &github.ProjectInfo{
+				OwnerType:     github.ProjectOwnerTypeOrg,
+				OwnerLogin:    "testorg",
+				ProjectNumber: 5,
+			},
+		},
+		{
+			name:    "invalid view number",
+			url:     "https://github.com/orgs/testorg/projects/5/views/abc",
+			wantErr: "invalid view number",
+		},
+		{
+			name:    "unknown trailing segments",
+			url:     "https://github.com/orgs/testorg/projects/5/settings/fields",
+			wantErr: "invalid project URL format",
+		},
 		{
 			name:    "invalid URL",
 			url:     ":", // Using a clearly invalid URL
@@ -47,6 +85,27 @@ func TestParseProjectURL(t *testing.T) {
 			url:     "https://github.com/orgs/test/wrong/123",
 			wantErr: "invalid URL format",
 		},
+		{
+			name: "enterprise server project URL",
+			url:  "https://github.example.com/orgs/testorg/projects/7",
+			host: "github.example.com",
+			want: &github.ProjectInfo{
+				OwnerType:     github.ProjectOwnerTypeOrg,
+				OwnerLogin:    "testorg",
+				ProjectNumber: 7,
+			},
+		},
+		{
+			name:    "github.com URL with enterprise server host",
+			url:     "https://github.com/orgs/testorg/projects/7",
+			host:    "github.example.com",
+			wantErr: "not a URL of GitHub host github.example.com",
+		},
+		{
+			name:    "enterprise server URL without configured host",
+			url:     "https://github.example.com/orgs/testorg/projects/7",
+			wantErr: "not a GitHub URL",
+		},
 		{
 			name:    "invalid owner type",
 			url:     "https://github.com/wrong/test/projects/123",
@@ -55,13 +114,28 @@ func TestParseProjectURL(t *testing.T) {
 		{
 			name:    "invalid project number",
 			url:     "https://github.com/orgs/test/projects/abc",
-			wantErr: "invalid project number",
+			wantErr: `invalid project number "abc" in https://github.com/orgs/test/projects/abc`,
+		},
+		{
+			name:    "zero project number",
+			url:     "https://github.com/orgs/test/projects/0",
+			wantErr: `invalid project number "0" in https://github.com/orgs/test/projects/0 (expected a positive number)`,
+		},
+		{
+			name:    "negative project number",
+			url:     "https://github.com/users/test/projects/-3",
+			wantErr: `invalid project number "-3" in https://github.com/users/test/projects/-3 (expected a positive number)`,
+		},
+		{
+			name:    "error names the owner type",
+			url:     "https://github.com/teams/test/projects/1",
+			wantErr: `invalid owner type "teams" in https://github.com/teams/test/projects/1 (expected orgs or users)`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ParseProjectURL(tt.url)
+			got, err := ParseProjectURL(tt.url, tt.host)
 			if tt.wantErr != "" {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.wantErr)
@@ -72,3 +146,32 @@ func TestParseProjectURL(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalizeIssueURL(t *testing.T) {
+	canonical := "https://github.com/org/repo/issues/1"
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "canonical", url: canonical, want: canonical},
+		{name: "mixed-case owner and repository", url: "https://github.com/Org/Repo/issues/1", want: canonical},
+		{name: "mixed-case host", url: "https://GitHub.com/org/repo/issues/1", want: canonical},
+		{name: "trailing slash", url: "https://github.com/org/repo/issues/1/", want: canonical},
+		{name: "query and fragment", url: "https://github.com/org/repo/issues/1?pane=issue#issuecomment-2", want: canonical},
+		{name: "http", url: "http://github.com/org/repo/issues/1", want: canonical},
+		{name: "API URL", url: "https://api.github.com/repos/Org/repo/issues/1", want: canonical},
+		{name: "Enterprise Server API URL", url: "https://github.example.com/api/v3/repos/org/repo/issues/1", want: "https://github.example.com/org/repo/issues/1"},
+		{name: "draft issue", url: "draft:Plan the release", want: "draft:Plan the release"},
+		{name: "pull request", url: "https://github.com/org/repo/pull/2", want: "https://github.com/org/repo/pull/2"},
+		{name: "mixed-case pull request", url: "https://github.com/Org/Repo/pull/2/", want: "https://github.com/org/repo/pull/2"},
+		{name: "pull request API URL", url: "https://api.github.com/repos/org/repo/pulls/2", want: "https://github.com/org/repo/pull/2"},
+		{name: "not a URL", url: "issue 1", want: "issue 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CanonicalizeIssueURL(tt.url))
+		})
+	}
+}
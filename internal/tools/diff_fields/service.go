@@ -0,0 +1,155 @@
+package diff_fields
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+type Service struct {
+	client client.Client
+}
+
+func NewService(client client.Client) *Service {
+	return &Service{
+		client: client,
+	}
+}
+
+// Diff describes how the fields of two projects differ
+type Diff struct {
+	// SourceOnly and TargetOnly list the fields that exist in only one of the projects
+	SourceOnly []github.ProjectFieldConfig `json:"source_only"`
+	TargetOnly []github.ProjectFieldConfig `json:"target_only"`
+	// Mismatches lists the fields of both projects whose type or options differ
+	Mismatches []FieldMismatch `json:"mismatches"`
+}
+
+// FieldMismatch describes a field of both projects whose type or options differ
+type FieldMismatch struct {
+	Name       string `json:"name"`
+	SourceType string `json:"source_type"`
+	TargetType string `json:"target_type"`
+	// SourceOnlyOptions and TargetOnlyOptions list the single select options of only one
+	// of the fields, if both are single select fields
+	SourceOnlyOptions []string `json:"source_only_options,omitempty"`
+	TargetOnlyOptions []string `json:"target_only_options,omitempty"`
+}
+
+// HasDifferences reports whether the fields of the projects differ
+func (d Diff) HasDifferences() bool {
+	return len(d.SourceOnly) > 0 || len(d.TargetOnly) > 0 || len(d.Mismatches) > 0
+}
+
+// DiffFields compares the field configurations of two projects by field name. Of several
+// fields sharing a name, the first one listed by the project is compared.
+func (s *Service) DiffFields(ctx context.Context, sourceProjectURL, targetProjectURL string) (Diff, error) {
+	sourceConfigs, err := s.fieldConfigs(ctx, sourceProjectURL)
+	if err != nil {
+		return Diff{}, fmt.Errorf("source project: %w", err)
+	}
+	targetConfigs, err := s.fieldConfigs(ctx, targetProjectURL)
+	if err != nil {
+		return Diff{}, fmt.Errorf("target project: %w", err)
+	}
+
+	return diffFieldConfigs(sourceConfigs, targetConfigs), nil
+}
+
+// fieldConfigs returns the field configurations of a project
+func (s *Service) fieldConfigs(ctx context.Context, projectURL string) ([]github.ProjectFieldConfig, error) {
+	projectInfo, err := util.ParseProjectURL(projectURL, s.client.Host())
+	if err != nil {
+		return nil, fmt.Errorf("invalid project URL: %w", err)
+	}
+
+	projectID, err := s.client.GetProjectID(ctx, projectInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project ID: %w", err)
+	}
+
+	configs, err := s.client.GetProjectFieldConfigs(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project fields: %w", err)
+	}
+	return configs, nil
+}
+
+// diffFieldConfigs compares two lists of field configurations, keeping the order of the
+// source fields and then of the target fields
+func diffFieldConfigs(sourceConfigs, targetConfigs []github.ProjectFieldConfig) Diff {
+	diff := Diff{
+		SourceOnly: []github.ProjectFieldConfig{},
+		TargetOnly: []github.ProjectFieldConfig{},
+		Mismatches: []FieldMismatch{},
+	}
+
+	sourceByName := indexByName(sourceConfigs)
+	targetByName := indexByName(targetConfigs)
+
+	seen := make(map[string]bool, len(sourceConfigs))
+	for _, source := range sourceConfigs {
+		if seen[source.Name] {
+			continue
+		}
+		seen[source.Name] = true
+
+		target, ok := targetByName[source.Name]
+		if !ok {
+			diff.SourceOnly = append(diff.SourceOnly, source)
+			continue
+		}
+
+		mismatch := FieldMismatch{
+			Name:       source.Name,
+			SourceType: source.DataType,
+			TargetType: target.DataType,
+		}
+		if source.DataType == "SINGLE_SELECT" && target.DataType == "SINGLE_SELECT" {
+			mismatch.SourceOnlyOptions = missingOptions(source.Options, target.Options)
+			mismatch.TargetOnlyOptions = missingOptions(target.Options, source.Options)
+		}
+		if source.DataType != target.DataType || len(mismatch.SourceOnlyOptions) > 0 || len(mismatch.TargetOnlyOptions) > 0 {
+			diff.Mismatches = append(diff.Mismatches, mismatch)
+		}
+	}
+
+	for _, target := range targetConfigs {
+		if _, ok := sourceByName[target.Name]; !ok && !seen[target.Name] {
+			seen[target.Name] = true
+			diff.TargetOnly = append(diff.TargetOnly, target)
+		}
+	}
+
+	return diff
+}
+
+// indexByName maps field names to the first field of that name
+func indexByName(configs []github.ProjectFieldConfig) map[string]github.ProjectFieldConfig {
+	index := make(map[string]github.ProjectFieldConfig, len(configs))
+	for _, config := range configs {
+		if _, ok := index[config.Name]; !ok {
+			index[config.Name] = config
+		}
+	}
+	return index
+}
+
+// missingOptions returns the names of the options missing in others, in their order
+func missingOptions(options, others []github.ProjectFieldOption) []string {
+	names := make(map[string]bool, len(others))
+	for _, option := range others {
+		names[option.Name] = true
+	}
+
+	var missing []string
+	for _, option := range options {
+		if !names[option.Name] {
+			missing = append(missing, option.Name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,81 @@
+package diff_fields
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+func TestDiffFields(t *testing.T) {
+	sourceFields := []github.ProjectFieldConfig{
+		{ID: "s1", Name: "Start date", DataType: "DATE"},
+		{ID: "s2", Name: "Estimate", DataType: "NUMBER"},
+		{ID: "s3", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{
+			{ID: "o1", Name: "Todo"}, {ID: "o2", Name: "WIP"}, {ID: "o3", Name: "Done"},
+		}},
+		{ID: "s4", Name: "Due", DataType: "DATE"},
+		{ID: "s5", Name: "Title", DataType: "TITLE"},
+	}
+	targetFields := []github.ProjectFieldConfig{
+		{ID: "t1", Name: "Title", DataType: "TITLE"},
+		{ID: "t2", Name: "Start date", DataType: "DATE"},
+		{ID: "t3", Name: "Estimate", DataType: "TEXT"},
+		{ID: "t4", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{
+			{ID: "o4", Name: "Todo"}, {ID: "o5", Name: "In Progress"}, {ID: "o6", Name: "Done"},
+		}},
+		{ID: "t5", Name: "Sprint", DataType: "ITERATION"},
+	}
+
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			if projectInfo.ProjectNumber == 1 {
+				return "PVT_1", nil
+			}
+			return "PVT_2", nil
+		},
+		GetProjectFieldConfigsFunc: func(ctx context.Context, projectID string) ([]github.ProjectFieldConfig, error) {
+			if projectID == "PVT_1" {
+				return sourceFields, nil
+			}
+			return targetFields, nil
+		},
+	}
+
+	diff, err := NewService(mockClient).DiffFields(context.Background(),
+		"https://github.com/orgs/myorg/projects/1",
+		"https://github.com/orgs/myorg/projects/2",
+	)
+	require.NoError(t, err)
+
+	assert.True(t, diff.HasDifferences())
+	assert.Equal(t, []github.ProjectFieldConfig{sourceFields[3]}, diff.SourceOnly)
+	assert.Equal(t, []github.ProjectFieldConfig{targetFields[4]}, diff.TargetOnly)
+	assert.Equal(t, []FieldMismatch{
+		{Name: "Estimate", SourceType: "NUMBER", TargetType: "TEXT"},
+		{
+			Name:              "Status",
+			SourceType:        "SINGLE_SELECT",
+			TargetType:        "SINGLE_SELECT",
+			SourceOnlyOptions: []string{"WIP"},
+			TargetOnlyOptions: []string{"In Progress"},
+		},
+	}, diff.Mismatches)
+}
+
+func TestDiffFieldsWithoutDifferences(t *testing.T) {
+	fields := []github.ProjectFieldConfig{
+		{ID: "f1", Name: "Start date", DataType: "DATE"},
+		{ID: "f2", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "o1", Name: "Todo"}}},
+	}
+
+	diff := diffFieldConfigs(fields, fields)
+	assert.False(t, diff.HasDifferences())
+	assert.Empty(t, diff.SourceOnly)
+	assert.Empty(t, diff.TargetOnly)
+	assert.Empty(t, diff.Mismatches)
+}
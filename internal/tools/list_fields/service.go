@@ -0,0 +1,40 @@
+package list_fields
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+type Service struct {
+	client client.Client
+}
+
+func NewService(client client.Client) *Service {
+	return &Service{
+		client: client,
+	}
+}
+
+// ListFields returns the field configurations of a project
+func (s *Service) ListFields(ctx context.Context, projectURL string) ([]github.ProjectFieldConfig, error) {
+	projectInfo, err := util.ParseProjectURL(projectURL, s.client.Host())
+	if err != nil {
+		return nil, fmt.Errorf("invalid project URL: %w", err)
+	}
+
+	projectID, err := s.client.GetProjectID(ctx, projectInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project ID: %w", err)
+	}
+
+	configs, err := s.client.GetProjectFieldConfigs(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	return configs, nil
+}
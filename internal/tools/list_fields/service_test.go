@@ -0,0 +1,38 @@
+package list_fields
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+func TestListFields(t *testing.T) {
+	fields := []github.ProjectFieldConfig{
+		{ID: "PVTF_1", Name: "Start date", Type: "ProjectV2Field", DataType: "DATE"},
+		{
+			ID:       "PVTSSF_1",
+			Name:     "Status",
+			Type:     "ProjectV2SingleSelectField",
+			DataType: "SINGLE_SELECT",
+			Options:  []github.ProjectFieldOption{{ID: "opt_1", Name: "Todo"}},
+		},
+	}
+
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			return "PVT_1", nil
+		},
+		GetProjectFieldConfigsFunc: func(ctx context.Context, projectID string) ([]github.ProjectFieldConfig, error) {
+			assert.Equal(t, "PVT_1", projectID)
+			return fields, nil
+		},
+	}
+
+	got, err := NewService(mockClient).ListFields(context.Background(), "https://github.com/orgs/myorg/projects/1")
+	assert.NoError(t, err)
+	assert.Equal(t, fields, got)
+}
@@ -0,0 +1,58 @@
+package list_issues
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+// Issue is an issue found in a project
+type Issue struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+type Service struct {
+	client client.Client
+}
+
+func NewService(client client.Client) *Service {
+	return &Service{
+		client: client,
+	}
+}
+
+// ListIssues returns the issues of a project with their titles. A limit of zero or less returns all issues.
+func (s *Service) ListIssues(ctx context.Context, projectURL string, limit int) ([]Issue, error) {
+	projectInfo, err := util.ParseProjectURL(projectURL, s.client.Host())
+	if err != nil {
+		return nil, fmt.Errorf("invalid project URL: %w", err)
+	}
+
+	projectID, err := s.client.GetProjectID(ctx, projectInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project ID: %w", err)
+	}
+
+	issueURLs, err := s.client.GetProjectIssues(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project issues: %w", err)
+	}
+
+	if limit > 0 && len(issueURLs) > limit {
+		issueURLs = issueURLs[:limit]
+	}
+
+	issues := make([]Issue, 0, len(issueURLs))
+	for _, issueURL := range issueURLs {
+		title, err := s.client.GetIssueTitle(ctx, issueURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issue title for %s: %w", issueURL, err)
+		}
+		issues = append(issues, Issue{URL: issueURL, Title: title})
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,53 @@
+package list_issues
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+func TestListIssues(t *testing.T) {
+	titles := map[string]string{
+		"https://github.com/org/repo/issues/1": "First issue",
+		"https://github.com/org/repo/issues/2": "Second issue",
+		"https://github.com/org/repo/issues/3": "Third issue",
+	}
+
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			return "PVT_1", nil
+		},
+		GetProjectIssuesFunc: func(ctx context.Context, projectID string) ([]string, error) {
+			assert.Equal(t, "PVT_1", projectID)
+			return []string{
+				"https://github.com/org/repo/issues/1",
+				"https://github.com/org/repo/issues/2",
+				"https://github.com/org/repo/issues/3",
+			}, nil
+		},
+		GetIssueTitleFunc: func(ctx context.Context, issueURL string) (string, error) {
+			return titles[issueURL], nil
+		},
+	}
+
+	t.Run("all issues", func(t *testing.T) {
+		got, err := NewService(mockClient).ListIssues(context.Background(), "https://github.com/orgs/myorg/projects/1", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []Issue{
+			{URL: "https://github.com/org/repo/issues/1", Title: "First issue"},
+			{URL: "https://github.com/org/repo/issues/2", Title: "Second issue"},
+			{URL: "https://github.com/org/repo/issues/3", Title: "Third issue"},
+		}, got)
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		got, err := NewService(mockClient).ListIssues(context.Background(), "https://github.com/orgs/myorg/projects/1", 2)
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "https://github.com/org/repo/issues/2", got[1].URL)
+	})
+}
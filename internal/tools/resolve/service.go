@@ -0,0 +1,72 @@
+package resolve
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+// Project holds the resolved node IDs of a project
+type Project struct {
+	URL    string  `json:"url"`
+	ID     string  `json:"id"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Field holds the resolved node ID of a project field
+type Field struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Service struct {
+	client client.Client
+}
+
+func NewService(client client.Client) *Service {
+	return &Service{
+		client: client,
+	}
+}
+
+// Resolve resolves the node IDs of the given projects, optionally including their fields
+func (s *Service) Resolve(ctx context.Context, projectURLs []string, includeFields bool) ([]Project, error) {
+	projects := make([]Project, 0, len(projectURLs))
+	for _, projectURL := range projectURLs {
+		projectInfo, err := util.ParseProjectURL(projectURL, s.client.Host())
+		if err != nil {
+			return nil, fmt.Errorf("invalid project URL %s: %w", projectURL, err)
+		}
+
+		projectID, err := s.client.GetProjectID(ctx, projectInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project ID for %s: %w", projectURL, err)
+		}
+
+		project := Project{
+			URL: projectURL,
+			ID:  projectID,
+		}
+
+		if includeFields {
+			configs, err := s.client.GetProjectFieldConfigs(ctx, projectID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get fields for %s: %w", projectURL, err)
+			}
+			for _, config := range configs {
+				project.Fields = append(project.Fields, Field{
+					ID:   config.ID,
+					Name: config.Name,
+					Type: config.Type,
+				})
+			}
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
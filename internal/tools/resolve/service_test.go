@@ -0,0 +1,64 @@
+package resolve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+func TestResolve(t *testing.T) {
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			if projectInfo.ProjectNumber == 1 {
+				return "PVT_1", nil
+			}
+			return "PVT_2", nil
+		},
+		GetProjectFieldConfigsFunc: func(ctx context.Context, projectID string) ([]github.ProjectFieldConfig, error) {
+			return []github.ProjectFieldConfig{
+				{ID: "PVTF_" + projectID, Name: "Start date", Type: "ProjectV2Field"},
+			}, nil
+		},
+	}
+
+	service := NewService(mockClient)
+
+	t.Run("project IDs only", func(t *testing.T) {
+		projects, err := service.Resolve(context.Background(), []string{
+			"https://github.com/orgs/myorg/projects/1",
+			"https://github.com/users/myuser/projects/2",
+		}, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Project{
+			{URL: "https://github.com/orgs/myorg/projects/1", ID: "PVT_1"},
+			{URL: "https://github.com/users/myuser/projects/2", ID: "PVT_2"},
+		}, projects)
+	})
+
+	t.Run("with fields", func(t *testing.T) {
+		projects, err := service.Resolve(context.Background(), []string{
+			"https://github.com/orgs/myorg/projects/1",
+		}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Project{
+			{
+				URL: "https://github.com/orgs/myorg/projects/1",
+				ID:  "PVT_1",
+				Fields: []Field{
+					{ID: "PVTF_PVT_1", Name: "Start date", Type: "ProjectV2Field"},
+				},
+			},
+		}, projects)
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := service.Resolve(context.Background(), []string{"https://example.com/foo"}, false)
+		assert.ErrorContains(t, err, "invalid project URL")
+	})
+}
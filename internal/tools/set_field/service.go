@@ -0,0 +1,225 @@
+package set_field
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+const (
+	// IterationCurrent names the iteration of an iteration field that includes today
+	IterationCurrent = "@current"
+	// IterationNext names the first iteration of an iteration field starting after today
+	IterationNext = "@next"
+)
+
+type Service struct {
+	client client.Client
+	dryRun bool
+}
+
+func NewService(client client.Client, dryRun bool) *Service {
+	return &Service{
+		client: client,
+		dryRun: dryRun,
+	}
+}
+
+// SetField sets a field of an issue in a project to a literal value, which is parsed
+// according to the type of the field
+func (s *Service) SetField(ctx context.Context, projectURL, issueURL, fieldName, value string) error {
+	projectID, config, err := s.loadField(ctx, projectURL, issueURL, fieldName)
+	if err != nil {
+		return err
+	}
+
+	fieldValue, err := ParseFieldValue(config, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for field %s: %w", config.Name, err)
+	}
+
+	field := github.ProjectField{Name: config.Name, Value: fieldValue}
+	if err := s.client.UpdateProjectField(ctx, projectID, issueURL, field, s.dryRun); err != nil {
+		return fmt.Errorf("failed to set field %s of %s: %w", config.Name, issueURL, err)
+	}
+	return nil
+}
+
+// ClearField removes the value of a field of an issue in a project
+func (s *Service) ClearField(ctx context.Context, projectURL, issueURL, fieldName string) error {
+	projectID, config, err := s.loadField(ctx, projectURL, issueURL, fieldName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.ClearProjectField(ctx, projectID, issueURL, github.ProjectField{ID: config.ID, Name: config.Name}, s.dryRun); err != nil {
+		return fmt.Errorf("failed to clear field %s of %s: %w", config.Name, issueURL, err)
+	}
+	return nil
+}
+
+// loadField loads a project with all its items, which updates look the issue up in, and
+// returns its ID and the configuration of the field. It fails if the issue is not in the
+// project or the field does not exist.
+func (s *Service) loadField(ctx context.Context, projectURL, issueURL, fieldName string) (string, github.ProjectFieldConfig, error) {
+	projectInfo, err := util.ParseProjectURL(projectURL, s.client.Host())
+	if err != nil {
+		return "", github.ProjectFieldConfig{}, fmt.Errorf("invalid project URL: %w", err)
+	}
+
+	projectID, err := s.client.GetProjectID(ctx, projectInfo)
+	if err != nil {
+		return "", github.ProjectFieldConfig{}, fmt.Errorf("failed to get project ID: %w", err)
+	}
+
+	configs, _, issues, _, err := s.client.GetProjectFieldConfigsAndIssues(ctx, projectID, projectID)
+	if err != nil {
+		return "", github.ProjectFieldConfig{}, fmt.Errorf("failed to get project fields and issues: %w", err)
+	}
+	// Issues are compared by their canonical URL, like the client looks them up
+	canonicalURL := util.CanonicalizeIssueURL(issueURL)
+	if !slices.ContainsFunc(issues, func(issue string) bool { return util.CanonicalizeIssueURL(issue) == canonicalURL }) {
+		return "", github.ProjectFieldConfig{}, &client.NotFoundError{Kind: client.ErrIssueNotFound, Name: issueURL}
+	}
+
+	config, ok := findFieldConfig(configs, fieldName)
+	if !ok {
+		return "", github.ProjectFieldConfig{}, &client.NotFoundError{Kind: client.ErrFieldNotFound, Name: fieldName}
+	}
+	return projectID, config, nil
+}
+
+// findFieldConfig finds a field by name, preferring an exact match over a
+// case-insensitive one
+func findFieldConfig(configs []github.ProjectFieldConfig, name string) (github.ProjectFieldConfig, bool) {
+	for _, config := range configs {
+		if config.Name == name {
+			return config, true
+		}
+	}
+	for _, config := range configs {
+		if strings.EqualFold(config.Name, name) {
+			return config, true
+		}
+	}
+	return github.ProjectFieldConfig{}, false
+}
+
+// ParseFieldValue parses a literal value according to the data type of a field. Dates are
+// written as YYYY-MM-DD, single select values name an option of the field, assignees and
+// labels are comma-separated logins and label names, and iterations are given by title or
+// as @current or @next.
+func ParseFieldValue(config github.ProjectFieldConfig, value string) (github.ProjectFieldValue, error) {
+	value = strings.TrimSpace(value)
+
+	switch config.DataType {
+	case "DATE":
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return github.ProjectFieldValue{}, fmt.Errorf("expected a date in the format YYYY-MM-DD, got %q", value)
+		}
+		return github.ProjectFieldValue{Date: &date}, nil
+	case "NUMBER":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return github.ProjectFieldValue{}, fmt.Errorf("expected a number, got %q", value)
+		}
+		return github.ProjectFieldValue{Number: &number}, nil
+	case "SINGLE_SELECT":
+		return parseOption(config, value)
+	case "ASSIGNEES":
+		logins := splitList(value, "@")
+		if len(logins) == 0 {
+			return github.ProjectFieldValue{}, fmt.Errorf("expected comma-separated logins")
+		}
+		return github.ProjectFieldValue{Users: logins}, nil
+	case "LABELS":
+		labels := splitList(value, "")
+		if len(labels) == 0 {
+			return github.ProjectFieldValue{}, fmt.Errorf("expected comma-separated labels")
+		}
+		return github.ProjectFieldValue{Labels: labels}, nil
+	case "MILESTONE":
+		if value == "" {
+			return github.ProjectFieldValue{}, fmt.Errorf("expected a milestone title")
+		}
+		return github.ProjectFieldValue{Milestone: &value}, nil
+	case "ITERATION":
+		iteration, err := findIteration(config, value, time.Now())
+		if err != nil {
+			return github.ProjectFieldValue{}, err
+		}
+		return github.ProjectFieldValue{Iteration: &iteration.Title, IterationID: iteration.ID}, nil
+	default:
+		return github.ProjectFieldValue{}, fmt.Errorf("fields of type %s cannot be set", config.DataType)
+	}
+}
+
+// parseOption finds the option of a single select field named by a value, ignoring case
+func parseOption(config github.ProjectFieldConfig, value string) (github.ProjectFieldValue, error) {
+	names := make([]string, 0, len(config.Options))
+	for _, option := range config.Options {
+		if strings.EqualFold(option.Name, value) {
+			return github.ProjectFieldValue{Text: &option.Name}, nil
+		}
+		names = append(names, option.Name)
+	}
+	return github.ProjectFieldValue{}, fmt.Errorf("no option %q, expected one of %s", value, strings.Join(names, ", "))
+}
+
+// splitList splits a comma-separated list, trimming the entries and the given prefix of
+// each entry, such as the @ of logins, and dropping empty entries
+func splitList(value, prefix string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimPrefix(strings.TrimSpace(entry), prefix); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// findIteration finds an iteration of an iteration field by title, or resolves the keywords
+// IterationCurrent and IterationNext against the given time. Iterations are dated in days,
+// so only the date of now is compared.
+func findIteration(config github.ProjectFieldConfig, value string, now time.Time) (github.ProjectFieldIteration, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch strings.ToLower(value) {
+	case IterationCurrent:
+		for _, iteration := range config.Iterations {
+			if !today.Before(iteration.StartDate) && today.Before(iteration.EndDate()) {
+				return iteration, nil
+			}
+		}
+		return github.ProjectFieldIteration{}, fmt.Errorf("no current iteration in field %s", config.Name)
+	case IterationNext:
+		var next *github.ProjectFieldIteration
+		for i, iteration := range config.Iterations {
+			if iteration.StartDate.After(today) && (next == nil || iteration.StartDate.Before(next.StartDate)) {
+				next = &config.Iterations[i]
+			}
+		}
+		if next == nil {
+			return github.ProjectFieldIteration{}, fmt.Errorf("no next iteration in field %s", config.Name)
+		}
+		return *next, nil
+	}
+
+	titles := make([]string, 0, len(config.Iterations)+2)
+	for _, iteration := range config.Iterations {
+		if strings.EqualFold(iteration.Title, value) {
+			return iteration, nil
+		}
+		titles = append(titles, iteration.Title)
+	}
+	titles = append(titles, IterationCurrent, IterationNext)
+	return github.ProjectFieldIteration{}, fmt.Errorf("no iteration %q, expected one of %s", value, strings.Join(titles, ", "))
+}
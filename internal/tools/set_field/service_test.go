@@ -0,0 +1,186 @@
+package set_field
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+var testConfigs = []github.ProjectFieldConfig{
+	{ID: "f1", Name: "Start date", DataType: "DATE"},
+	{ID: "f2", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{
+		{ID: "o1", Name: "Todo"},
+		{ID: "o2", Name: "In Progress"},
+	}},
+	{ID: "f3", Name: "Estimate", DataType: "NUMBER"},
+	{ID: "f4", Name: "Assignees", DataType: "ASSIGNEES"},
+	{ID: "f5", Name: "Notes", DataType: "TEXT"},
+	{ID: "f6", Name: "Labels", DataType: "LABELS"},
+}
+
+func TestParseFieldValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   int
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "date", field: 0, value: "2024-03-01", want: "2024-03-01"},
+		{name: "invalid date", field: 0, value: "March 1st", wantErr: `expected a date in the format YYYY-MM-DD, got "March 1st"`},
+		{name: "single select", field: 1, value: "in progress", want: "In Progress"},
+		{name: "unknown option", field: 1, value: "Done", wantErr: `no option "Done", expected one of Todo, In Progress`},
+		{name: "number", field: 2, value: "2.5", want: "2.5"},
+		{name: "invalid number", field: 2, value: "two", wantErr: `expected a number, got "two"`},
+		{name: "assignees", field: 3, value: "@octocat, hubot", want: "octocat, hubot"},
+		{name: "labels", field: 5, value: "bug, team-a", want: "bug, team-a"},
+		{name: "no labels", field: 5, value: " , ", wantErr: "expected comma-separated labels"},
+		{name: "unsupported type", field: 4, value: "note", wantErr: "fields of type TEXT cannot be set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := ParseFieldValue(testConfigs[tt.field], tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value.String())
+		})
+	}
+}
+
+func TestFindIteration(t *testing.T) {
+	config := github.ProjectFieldConfig{Name: "Sprint", DataType: "ITERATION", Iterations: []github.ProjectFieldIteration{
+		{ID: "it_2", Title: "Sprint 2", StartDate: time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), Duration: 14},
+		{ID: "it_1", Title: "Sprint 1", StartDate: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Duration: 14},
+		{ID: "it_3", Title: "Sprint 3", StartDate: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Duration: 14},
+	}}
+	// The last day of Sprint 1, late in the evening
+	now := time.Date(2024, 3, 17, 23, 30, 0, 0, time.FixedZone("UTC-8", -8*60*60))
+
+	tests := []struct {
+		name    string
+		value   string
+		now     time.Time
+		want    string
+		wantErr string
+	}{
+		{name: "current", value: "@current", now: now, want: "it_1"},
+		{name: "next", value: "@Next", now: now, want: "it_2"},
+		{name: "current on the first day", value: "@current", now: time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), want: "it_2"},
+		{name: "title", value: "sprint 3", now: now, want: "it_3"},
+		{name: "no current iteration", value: "@current", now: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), wantErr: "no current iteration in field Sprint"},
+		{name: "no next iteration", value: "@next", now: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), wantErr: "no next iteration in field Sprint"},
+		{name: "unknown title", value: "Sprint 9", now: now, wantErr: `no iteration "Sprint 9", expected one of Sprint 2, Sprint 1, Sprint 3, @current, @next`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iteration, err := findIteration(config, tt.value, tt.now)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, iteration.ID)
+		})
+	}
+}
+
+func TestSetField(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var updates []github.ProjectField
+	var dryRuns []bool
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			return "PVT_1", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			return testConfigs, testConfigs, []string{issueURL}, []string{issueURL}, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			assert.Equal(t, "PVT_1", projectID)
+			updates = append(updates, field)
+			dryRuns = append(dryRuns, dryRun)
+			return nil
+		},
+	}
+	projectURL := "https://github.com/orgs/myorg/projects/1"
+
+	t.Run("sets the parsed value", func(t *testing.T) {
+		updates, dryRuns = nil, nil
+		err := NewService(mockClient, true).SetField(context.Background(), projectURL, issueURL, "status", "todo")
+		require.NoError(t, err)
+		require.Len(t, updates, 1)
+		assert.Equal(t, "Status", updates[0].Name)
+		assert.Equal(t, "Todo", updates[0].Value.String())
+		assert.Equal(t, []bool{true}, dryRuns)
+	})
+
+	t.Run("matches variants of the issue URL", func(t *testing.T) {
+		updates = nil
+		err := NewService(mockClient, false).SetField(context.Background(), projectURL, "https://github.com/Org/Repo/issues/1/", "Status", "Todo")
+		require.NoError(t, err)
+		assert.Len(t, updates, 1)
+	})
+
+	t.Run("unknown issue", func(t *testing.T) {
+		updates = nil
+		err := NewService(mockClient, false).SetField(context.Background(), projectURL, "https://github.com/org/repo/issues/2", "Status", "Todo")
+		assert.ErrorIs(t, err, client.ErrIssueNotFound)
+		assert.Empty(t, updates)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		err := NewService(mockClient, false).SetField(context.Background(), projectURL, issueURL, "Priority", "High")
+		assert.ErrorIs(t, err, client.ErrFieldNotFound)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		err := NewService(mockClient, false).SetField(context.Background(), projectURL, issueURL, "Start date", "tomorrow")
+		assert.ErrorContains(t, err, "invalid value for field Start date")
+	})
+}
+
+func TestClearField(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+
+	var cleared []string
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			return "PVT_1", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			return testConfigs, testConfigs, []string{issueURL}, []string{issueURL}, nil
+		},
+		ClearProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			cleared = append(cleared, field.Name)
+			return nil
+		},
+	}
+	projectURL := "https://github.com/orgs/myorg/projects/1"
+
+	err := NewService(mockClient, false).ClearField(context.Background(), projectURL, issueURL, "start date")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Start date"}, cleared)
+
+	err = NewService(mockClient, false).ClearField(context.Background(), projectURL, "https://github.com/ORG/repo/issues/1", "Start date")
+	require.NoError(t, err, "expected variants of the issue URL to match")
+	cleared = cleared[:1]
+
+	err = NewService(mockClient, false).ClearField(context.Background(), projectURL, "https://github.com/org/repo/issues/2", "Start date")
+	assert.ErrorIs(t, err, client.ErrIssueNotFound)
+
+	err = NewService(mockClient, false).ClearField(context.Background(), projectURL, issueURL, "Priority")
+	assert.ErrorIs(t, err, client.ErrFieldNotFound)
+	assert.Len(t, cleared, 1)
+}
@@ -0,0 +1,59 @@
+package sync_fields
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// LabelMatchAll keeps issues carrying all of the filter labels
+	LabelMatchAll = "all"
+	// LabelMatchAny keeps issues carrying at least one of the filter labels
+	LabelMatchAny = "any"
+)
+
+// validateLabelMatch checks that the label match mode is known
+func validateLabelMatch(match string) error {
+	switch match {
+	case LabelMatchAll, LabelMatchAny:
+		return nil
+	default:
+		return fmt.Errorf("invalid label match %q, must be %s or %s", match, LabelMatchAll, LabelMatchAny)
+	}
+}
+
+// filterIssuesByLabels keeps the issues whose labels match the filter labels. Labels are
+// compared case-insensitively, like GitHub does.
+func (s *Service) filterIssuesByLabels(ctx context.Context, issues []string) ([]string, error) {
+	var filtered []string
+	for _, issueURL := range issues {
+		labels, err := s.client.GetIssueLabels(ctx, issueURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get labels of %s: %w", issueURL, err)
+		}
+		if matchLabels(labels, s.filterLabels, s.labelMatch) {
+			filtered = append(filtered, issueURL)
+		}
+	}
+	return filtered, nil
+}
+
+// matchLabels checks if the labels of an issue contain all or any of the wanted labels
+func matchLabels(labels, wanted []string, match string) bool {
+	has := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		has[strings.ToLower(label)] = true
+	}
+
+	for _, label := range wanted {
+		found := has[strings.ToLower(label)]
+		if match == LabelMatchAny && found {
+			return true
+		}
+		if match == LabelMatchAll && !found {
+			return false
+		}
+	}
+	return match == LabelMatchAll
+}
@@ -0,0 +1,97 @@
+package sync_fields
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMatchLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		wanted []string
+		match  string
+		want   bool
+	}{
+		{name: "all present", labels: []string{"bug", "ui"}, wanted: []string{"bug", "ui"}, match: LabelMatchAll, want: true},
+		{name: "all with one missing", labels: []string{"bug"}, wanted: []string{"bug", "ui"}, match: LabelMatchAll, want: false},
+		{name: "any with one present", labels: []string{"ui"}, wanted: []string{"bug", "ui"}, match: LabelMatchAny, want: true},
+		{name: "any with none present", labels: []string{"docs"}, wanted: []string{"bug", "ui"}, match: LabelMatchAny, want: false},
+		{name: "case insensitive", labels: []string{"Bug"}, wanted: []string{"bug"}, match: LabelMatchAll, want: true},
+		{name: "no labels", labels: nil, wanted: []string{"bug"}, match: LabelMatchAny, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchLabels(tt.labels, tt.wanted, tt.match); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsFiltersIssuesByLabels(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}
+	labels := map[string][]string{
+		issues[0]: {"bug", "ui"},
+		issues[1]: {"bug"},
+		issues[2]: {"docs"},
+	}
+
+	tests := []struct {
+		match string
+		want  []string
+	}{
+		{match: LabelMatchAll, want: []string{issues[0]}},
+		{match: LabelMatchAny, want: []string{issues[0], issues[1]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.match, func(t *testing.T) {
+			mockClient := newSyncMockClient(issues, time.Now())
+			mockClient.GetIssueLabelsFunc = func(ctx context.Context, issueURL string) ([]string, error) {
+				return labels[issueURL], nil
+			}
+
+			service := NewService(mockClient, Options{DryRun: true, FilterLabels: []string{"bug", "ui"}, LabelMatch: tt.match})
+			err := service.SyncFields(
+				context.Background(),
+				"https://github.com/orgs/myorg/projects/824",
+				"https://github.com/orgs/myorg/projects/825",
+				nil,
+				[]string{"start=Start date"},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var synced []string
+			for _, change := range service.Result().Changes {
+				synced = append(synced, change.IssueURL)
+			}
+			if !reflect.DeepEqual(synced, tt.want) {
+				t.Errorf("expected synced issues %v, got %v", tt.want, synced)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsRejectsUnknownLabelMatch(t *testing.T) {
+	service := NewService(newSyncMockClient(nil, time.Now()), Options{FilterLabels: []string{"bug"}, LabelMatch: "some"})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil {
+		t.Fatal("expected an error for an unknown label match")
+	}
+}
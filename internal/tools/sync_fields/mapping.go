@@ -2,25 +2,491 @@ package sync_fields
 
 import (
 	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// Direction is the direction values of a field mapping flow in
+type Direction int
+
+const (
+	// DirectionForward copies the value of the source project field to the target project
+	DirectionForward Direction = iota
+	// DirectionReverse copies the value of the target project field back to the source project
+	DirectionReverse
 )
 
 type FieldMapping struct {
 	SourceField string
 	TargetField string
+	// Direction is DirectionReverse for mappings written as 'source<-target'. SyncFields
+	// swaps the fields of reverse mappings once they are validated, so that SourceField is
+	// always the field values are read from.
+	Direction Direction
+	// Priority orders the updates of an issue: mappings with a priority are applied first,
+	// lowest first, followed by mappings without a priority (zero) in the given order
+	Priority int
+	// DateOffset shifts date values by this number of days before they are written
+	DateOffset int
+	// ValueMap renames single select values before they are written. Values without an
+	// entry are written unchanged.
+	ValueMap map[string]string
+	// OptionIDs selects target single select options by ID for the source values mapped to
+	// '#ID' in the value map, for target options sharing a name. The value map holds the
+	// names of these options once they are resolved with resolveTargetOptions.
+	OptionIDs map[string]string
+	// FieldType is the data type declared for the target field, as in 'start=Start:date',
+	// such as DATE or SINGLE_SELECT. It must match the target field and decides how values
+	// are written to it instead of the field configuration. Empty if not declared.
+	FieldType string
+	// TargetFieldID is the ID of the target field, resolved from the target project, so that
+	// the field is updated by ID even if other fields share its name
+	TargetFieldID string
+	// ReplacedLabels are the labels a mapping from a single select field to a labels field
+	// replaces with the label of the source value: the options of the source field, renamed
+	// by the value map. Set by resolveLabelMappings.
+	ReplacedLabels []string
 }
 
+// ParseFieldMappings parses mappings in the format 'source=target'. The target may be followed
+// by a date offset in days or weeks as in 'source=target:+7d' or 'source=target:-2w', or by a
+// value map as in 'Status=Status{WIP:In Progress,Done:Complete}', and finally by a priority
+// as in 'source=target:+7d@1'. The data type of the target field may be declared right after
+// its name, as in 'source=target:date:+7d', with one of the types in fieldTypes. Value maps
+// may select a target option by ID instead of by name with a leading '#', as in
+// 'Status=Status{Done:#PVTSSO_1}'. Mappings written as 'source<-target' copy the target
+// field to the source field instead, applying the date offset and value map to the values
+// of the target field.
 func ParseFieldMappings(fieldMappings []string) ([]FieldMapping, error) {
 	mappings := make([]FieldMapping, 0, len(fieldMappings))
 	for _, mapping := range fieldMappings {
-		parts := strings.Split(mapping, "=")
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid field mapping format: %s", mapping)
+		parsed, err := parseFieldMapping(mapping)
+		if err != nil {
+			return nil, err
 		}
-		mappings = append(mappings, FieldMapping{
-			SourceField: strings.TrimSpace(parts[0]),
-			TargetField: strings.TrimSpace(parts[1]),
-		})
+		mappings = append(mappings, parsed)
 	}
 	return mappings, nil
 }
+
+// parseFieldMapping parses a single mapping in the format of ParseFieldMappings. The
+// suffixes of the target are cut off from the end: priority, value map, date offset and type.
+func parseFieldMapping(mapping string) (FieldMapping, error) {
+	direction := DirectionForward
+	parts := strings.Split(mapping, "=")
+	if len(parts) == 1 {
+		if source, target, ok := strings.Cut(mapping, "<-"); ok {
+			parts, direction = []string{source, target}, DirectionReverse
+		}
+	}
+	if len(parts) != 2 || direction == DirectionForward && strings.Contains(parts[0], "<-") {
+		return FieldMapping{}, fmt.Errorf("invalid field mapping format: %s", mapping)
+	}
+
+	target, priority, err := cutPriority(mapping, parts[1])
+	if err != nil {
+		return FieldMapping{}, err
+	}
+	target, valueMap, optionIDs, err := cutValueMap(mapping, target)
+	if err != nil {
+		return FieldMapping{}, err
+	}
+
+	offset := 0
+	if i := strings.LastIndex(target, ":"); i >= 0 && isDateOffset(target[i+1:]) {
+		days, err := parseDateOffset(strings.TrimSpace(target[i+1:]))
+		if err != nil {
+			return FieldMapping{}, fmt.Errorf("invalid date offset in field mapping %s: %w", mapping, err)
+		}
+		target, offset = target[:i], days
+	}
+
+	fieldType := ""
+	if i := strings.LastIndex(target, ":"); i >= 0 {
+		if dataType, ok := fieldTypes[strings.ToLower(strings.TrimSpace(target[i+1:]))]; ok {
+			target, fieldType = target[:i], dataType
+		}
+	}
+
+	return FieldMapping{
+		SourceField: strings.TrimSpace(parts[0]),
+		TargetField: strings.TrimSpace(target),
+		Direction:   direction,
+		Priority:    priority,
+		DateOffset:  offset,
+		ValueMap:    valueMap,
+		OptionIDs:   optionIDs,
+		FieldType:   fieldType,
+	}, nil
+}
+
+// cutPriority cuts a priority as in 'target@1' off the target of a mapping. A '@' within
+// a value map is part of a value, not a priority.
+func cutPriority(mapping, target string) (string, int, error) {
+	i := strings.LastIndex(target, "@")
+	if i < 0 || i < strings.LastIndex(target, "}") {
+		return target, 0, nil
+	}
+	priority, err := strconv.Atoi(strings.TrimSpace(target[i+1:]))
+	if err != nil || priority < 1 {
+		return "", 0, fmt.Errorf("invalid priority in field mapping %s: expected a positive number", mapping)
+	}
+	return target[:i], priority, nil
+}
+
+// cutValueMap cuts a value map as in 'target{WIP:In Progress}' off the target of a mapping
+// and returns it along with the option IDs selected by '#ID' values
+func cutValueMap(mapping, target string) (string, map[string]string, map[string]string, error) {
+	trimmed := strings.TrimSpace(target)
+	if !strings.HasSuffix(trimmed, "}") {
+		return target, nil, nil, nil
+	}
+	i := strings.Index(trimmed, "{")
+	if i < 0 {
+		return "", nil, nil, fmt.Errorf("invalid value map in field mapping %s: missing {", mapping)
+	}
+	valueMap, err := parseValueMap(trimmed[i+1 : len(trimmed)-1])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid value map in field mapping %s: %w", mapping, err)
+	}
+
+	var optionIDs map[string]string
+	for from, to := range valueMap {
+		id, ok := strings.CutPrefix(to, "#")
+		if !ok {
+			continue
+		}
+		if id == "" {
+			return "", nil, nil, fmt.Errorf("invalid value map in field mapping %s: missing option ID for %q", mapping, from)
+		}
+		if optionIDs == nil {
+			optionIDs = make(map[string]string)
+		}
+		optionIDs[from] = id
+	}
+	return trimmed[:i], valueMap, optionIDs, nil
+}
+
+// fieldTypes maps the field types that can be declared in field mappings to their data types
+var fieldTypes = map[string]string{
+	"date":          "DATE",
+	"number":        "NUMBER",
+	"single_select": "SINGLE_SELECT",
+	"iteration":     "ITERATION",
+}
+
+// parseValueMap parses comma-separated 'from:to' pairs of single select values
+func parseValueMap(entries string) (map[string]string, error) {
+	valueMap := make(map[string]string)
+	for _, entry := range strings.Split(entries, ",") {
+		from, to, ok := strings.Cut(entry, ":")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("expected entries in the format 'from:to', got %q", strings.TrimSpace(entry))
+		}
+		if _, exists := valueMap[from]; exists {
+			return nil, fmt.Errorf("duplicate entry for %q", from)
+		}
+		valueMap[from] = to
+	}
+	return valueMap, nil
+}
+
+// transform applies the date offset and value map of the mapping to a source value
+func (m FieldMapping) transform(value github.ProjectFieldValue) (github.ProjectFieldValue, error) {
+	if m.DateOffset != 0 && !value.IsEmpty() {
+		if value.Date == nil {
+			return value, fmt.Errorf("date offset in field mapping %s=%s requires a date field, but %s holds %q", m.SourceField, m.TargetField, m.SourceField, value)
+		}
+		shifted := value.Date.AddDate(0, 0, m.DateOffset)
+		value.Date = &shifted
+	}
+
+	// Iterations are chosen by title in the target project, as their IDs differ
+	value.IterationID = ""
+	if value.Text != nil {
+		// Option IDs of the source project are meaningless in the target project
+		value.OptionID = m.OptionIDs[*value.Text]
+		mapped := m.mapValue(*value.Text)
+		value.Text = &mapped
+	}
+	return value, nil
+}
+
+// mapValue renames a single select value according to the value map of the mapping
+func (m FieldMapping) mapValue(value string) string {
+	if mapped, ok := m.ValueMap[value]; ok {
+		return mapped
+	}
+	return value
+}
+
+// reversed returns a reverse mapping with its fields swapped, so that it is applied like a
+// forward mapping from the target to the source project
+func (m FieldMapping) reversed() FieldMapping {
+	m.SourceField, m.TargetField = m.TargetField, m.SourceField
+	return m
+}
+
+// splitByDirection splits mappings into forward and reverse mappings, keeping their order
+func splitByDirection(mappings []FieldMapping) (forward, reverse []FieldMapping) {
+	for _, mapping := range mappings {
+		if mapping.Direction == DirectionReverse {
+			reverse = append(reverse, mapping)
+		} else {
+			forward = append(forward, mapping)
+		}
+	}
+	return forward, reverse
+}
+
+// isDateOffset checks if the suffix after a colon is meant as a date offset, which always
+// starts with a sign. Other colons are part of the field name.
+func isDateOffset(suffix string) bool {
+	suffix = strings.TrimSpace(suffix)
+	return strings.HasPrefix(suffix, "+") || strings.HasPrefix(suffix, "-")
+}
+
+// parseDateOffset parses a signed offset in days (d) or weeks (w), such as +7d or -2w
+func parseDateOffset(offset string) (int, error) {
+	if len(offset) < 3 {
+		return 0, fmt.Errorf("expected a signed number of days or weeks such as +7d or -2w")
+	}
+
+	unit := 1
+	switch offset[len(offset)-1] {
+	case 'd':
+	case 'w':
+		unit = 7
+	default:
+		return 0, fmt.Errorf("unknown unit in %s, expected d (days) or w (weeks)", offset)
+	}
+
+	n, err := strconv.Atoi(offset[:len(offset)-1])
+	if err != nil {
+		return 0, fmt.Errorf("expected a signed number of days or weeks such as +7d or -2w")
+	}
+	return n * unit, nil
+}
+
+// validateMappingFields checks that the source and target fields of all mappings exist,
+// returning a single error that lists every unknown field
+func validateMappingFields(mappings []FieldMapping, sourceFieldConfigs, targetFieldConfigs []github.ProjectFieldConfig) error {
+	var problems []string
+	for _, mapping := range mappings {
+		if problem := unknownField("source", mapping.SourceField, sourceFieldConfigs); problem != "" {
+			problems = append(problems, problem)
+		}
+		if problem := unknownField("target", mapping.TargetField, targetFieldConfigs); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("field mappings refer to unknown fields:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// unknownField describes a field missing in a project, suggesting the most similar field
+// name, or returns an empty string if the field exists
+func unknownField(project, name string, configs []github.ProjectFieldConfig) string {
+	best, bestSimilarity := "", 0.0
+	for _, config := range configs {
+		if config.Name == name {
+			return ""
+		}
+		if similarity := nameSimilarity(name, config.Name); similarity > bestSimilarity {
+			best, bestSimilarity = config.Name, similarity
+		}
+	}
+
+	if bestSimilarity >= minSuggestionSimilarity {
+		return fmt.Sprintf("%s field %q not found in the %s project, did you mean %q?", project, name, project, best)
+	}
+	return fmt.Sprintf("%s field %q not found in the %s project", project, name, project)
+}
+
+// resolveTargetFieldIDs sets the ID of the target field of each mapping. Of several fields
+// sharing a name, such as a custom field named like the built-in Status field, the first
+// one listed by the project is used.
+func resolveTargetFieldIDs(mappings []FieldMapping, targetFieldConfigs []github.ProjectFieldConfig) []FieldMapping {
+	resolved := make([]FieldMapping, len(mappings))
+	for i, mapping := range mappings {
+		resolved[i] = mapping
+		var matches int
+		for _, config := range targetFieldConfigs {
+			if config.Name != mapping.TargetField {
+				continue
+			}
+			if matches == 0 {
+				resolved[i].TargetFieldID = config.ID
+			}
+			matches++
+		}
+		if matches > 1 {
+			slog.Warn("several target fields share the name of a mapped field, using the first one",
+				"field", mapping.TargetField,
+				"field_id", resolved[i].TargetFieldID,
+				"count", matches,
+			)
+		}
+	}
+	return resolved
+}
+
+// resolveTargetOptions looks up the target options selected by ID in the value maps of the
+// mappings, and replaces the IDs in the value maps with the names of the options
+func resolveTargetOptions(mappings []FieldMapping, targetFieldConfigs []github.ProjectFieldConfig) ([]FieldMapping, error) {
+	resolved := make([]FieldMapping, len(mappings))
+	for i, mapping := range mappings {
+		resolved[i] = mapping
+		if len(mapping.OptionIDs) == 0 {
+			continue
+		}
+
+		var config *github.ProjectFieldConfig
+		for j := range targetFieldConfigs {
+			if targetFieldConfigs[j].ID == mapping.TargetFieldID {
+				config = &targetFieldConfigs[j]
+				break
+			}
+		}
+		if config == nil || config.DataType != "SINGLE_SELECT" {
+			return nil, fmt.Errorf("field mapping %s=%s selects options by ID, but %s is not a single select field of the target project", mapping.SourceField, mapping.TargetField, mapping.TargetField)
+		}
+
+		valueMap := make(map[string]string, len(mapping.ValueMap))
+		for from, to := range mapping.ValueMap {
+			valueMap[from] = to
+		}
+		// Report unknown IDs in a stable order
+		froms := make([]string, 0, len(mapping.OptionIDs))
+		for from := range mapping.OptionIDs {
+			froms = append(froms, from)
+		}
+		sort.Strings(froms)
+		for _, from := range froms {
+			id := mapping.OptionIDs[from]
+			name := ""
+			for _, option := range config.Options {
+				if option.ID == id {
+					name = option.Name
+					break
+				}
+			}
+			if name == "" {
+				return nil, fmt.Errorf("field mapping %s=%s selects option ID %s, which is not an option of the target field", mapping.SourceField, mapping.TargetField, id)
+			}
+			valueMap[from] = name
+		}
+		resolved[i].ValueMap = valueMap
+	}
+	return resolved, nil
+}
+
+// resolveLabelMappings sets the labels replaced by mappings from single select fields to
+// labels fields, so that changing the source value swaps its label instead of adding another
+func resolveLabelMappings(mappings []FieldMapping, sourceFieldConfigs, targetFieldConfigs []github.ProjectFieldConfig) []FieldMapping {
+	resolved := make([]FieldMapping, len(mappings))
+	for i, mapping := range mappings {
+		resolved[i] = mapping
+		target := slices.IndexFunc(targetFieldConfigs, func(config github.ProjectFieldConfig) bool {
+			return config.ID == mapping.TargetFieldID && config.DataType == "LABELS"
+		})
+		source := slices.IndexFunc(sourceFieldConfigs, func(config github.ProjectFieldConfig) bool {
+			return config.Name == mapping.SourceField && config.DataType == "SINGLE_SELECT"
+		})
+		if target < 0 || source < 0 {
+			continue
+		}
+
+		replaced := make([]string, 0, len(sourceFieldConfigs[source].Options))
+		for _, option := range sourceFieldConfigs[source].Options {
+			replaced = append(replaced, mapping.mapValue(option.Name))
+		}
+		resolved[i].ReplacedLabels = replaced
+	}
+	return resolved
+}
+
+// labelsFor returns the labels to write to a labels field for a single select value: the
+// current labels without those the mapping replaces, plus the label of the value. Other
+// values are returned unchanged.
+func (m FieldMapping) labelsFor(current, value github.ProjectFieldValue) github.ProjectFieldValue {
+	if m.ReplacedLabels == nil || value.Text == nil {
+		return value
+	}
+
+	// Label names are compared ignoring case, like GitHub does
+	matches := func(name string) func(string) bool {
+		return func(label string) bool { return strings.EqualFold(label, name) }
+	}
+	labels := slices.DeleteFunc(slices.Clone(current.Labels), func(label string) bool {
+		return !strings.EqualFold(label, *value.Text) && slices.ContainsFunc(m.ReplacedLabels, matches(label))
+	})
+	if !slices.ContainsFunc(labels, matches(*value.Text)) {
+		labels = append(labels, *value.Text)
+	}
+	return github.ProjectFieldValue{Labels: labels}
+}
+
+// validateDateOffsets checks that date offsets are only applied to mappings between date fields
+func validateDateOffsets(mappings []FieldMapping, sourceFieldConfigs, targetFieldConfigs []github.ProjectFieldConfig) error {
+	dataTypes := func(configs []github.ProjectFieldConfig) map[string]string {
+		types := make(map[string]string, len(configs))
+		for _, config := range configs {
+			types[config.Name] = config.DataType
+		}
+		return types
+	}
+	sourceTypes, targetTypes := dataTypes(sourceFieldConfigs), dataTypes(targetFieldConfigs)
+
+	for _, mapping := range mappings {
+		if mapping.DateOffset == 0 {
+			continue
+		}
+		if dataType, ok := sourceTypes[mapping.SourceField]; ok && dataType != "DATE" {
+			return fmt.Errorf("date offset in field mapping %s=%s requires a date field, but source field %q is of type %s", mapping.SourceField, mapping.TargetField, mapping.SourceField, dataType)
+		}
+		if dataType, ok := targetTypes[mapping.TargetField]; ok && dataType != "DATE" {
+			return fmt.Errorf("date offset in field mapping %s=%s requires a date field, but target field %q is of type %s", mapping.SourceField, mapping.TargetField, mapping.TargetField, dataType)
+		}
+	}
+	return nil
+}
+
+// validateFieldTypes checks that the declared types of the target fields of all mappings
+// match the types of the fields in the target project
+func validateFieldTypes(mappings []FieldMapping, targetFieldConfigs []github.ProjectFieldConfig) error {
+	for _, mapping := range mappings {
+		if mapping.FieldType == "" {
+			continue
+		}
+		for _, config := range targetFieldConfigs {
+			if config.Name == mapping.TargetField && config.DataType != mapping.FieldType {
+				return fmt.Errorf("field mapping %s=%s declares type %s, but target field %q is of type %s", mapping.SourceField, mapping.TargetField, mapping.FieldType, mapping.TargetField, config.DataType)
+			}
+		}
+	}
+	return nil
+}
+
+// sortByPriority returns the mappings in the order they are applied
+func sortByPriority(mappings []FieldMapping) []FieldMapping {
+	sorted := make([]FieldMapping, len(mappings))
+	copy(sorted, mappings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Priority, sorted[j].Priority
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+	return sorted
+}
@@ -0,0 +1,56 @@
+package sync_fields
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadFieldMappingsFile reads field mappings from a file, one per line in the format of
+// --field-mapping. Blank lines and lines starting with # are ignored. All malformed lines
+// are reported in a single error.
+func ReadFieldMappingsFile(path string) ([]string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to open field mapping file: %w", err)
+	}
+	defer f.Close()
+
+	mappings, err := ParseFieldMappingList(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field mapping file %s: %w", path, err)
+	}
+	return mappings, nil
+}
+
+// ParseFieldMappingList parses a list of field mappings, one per line, ignoring blank lines
+// and lines starting with #. Each mapping is validated with ParseFieldMappings.
+func ParseFieldMappingList(r io.Reader) ([]string, error) {
+	var mappings []string
+	var malformed []string
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := ParseFieldMappings([]string{line}); err != nil {
+			malformed = append(malformed, fmt.Sprintf("  line %d: %v", lineNumber, err))
+			continue
+		}
+		mappings = append(mappings, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(malformed) > 0 {
+		return nil, fmt.Errorf("%d malformed field mappings:\n%s", len(malformed), strings.Join(malformed, "\n"))
+	}
+	return mappings, nil
+}
@@ -0,0 +1,58 @@
+package sync_fields
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFieldMappingList(t *testing.T) {
+	input := strings.Join([]string{
+		"# Standard mappings of the platform team",
+		"Start date=Start",
+		"",
+		"  Status=Status{WIP:In Progress}@1  ",
+		"Due",
+		"End=End:+7x",
+	}, "\n")
+
+	_, err := ParseFieldMappingList(strings.NewReader(input))
+	if err == nil {
+		t.Fatal("expected an error for malformed lines")
+	}
+	want := "2 malformed field mappings:\n" +
+		"  line 5: invalid field mapping format: Due\n" +
+		"  line 6: invalid date offset in field mapping End=End:+7x: unknown unit in +7x, expected d (days) or w (weeks)"
+	if err.Error() != want {
+		t.Errorf("unexpected error:\n%s\nwant:\n%s", err, want)
+	}
+
+	mappings, err := ParseFieldMappingList(strings.NewReader(strings.Join(strings.Split(input, "\n")[:4], "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"Start date=Start", "Status=Status{WIP:In Progress}@1"}; !reflect.DeepEqual(mappings, want) {
+		t.Errorf("got mappings %v, want %v", mappings, want)
+	}
+}
+
+func TestReadFieldMappingsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mappings.txt")
+	if err := os.WriteFile(path, []byte("Start date=Start\nEnd date=End\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mappings, err := ReadFieldMappingsFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"Start date=Start", "End date=End"}; !reflect.DeepEqual(mappings, want) {
+		t.Errorf("got mappings %v, want %v", mappings, want)
+	}
+
+	if _, err := ReadFieldMappingsFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil || !strings.Contains(err.Error(), "failed to open field mapping file") {
+		t.Errorf("expected an open error, got %v", err)
+	}
+}
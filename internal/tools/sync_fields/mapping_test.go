@@ -0,0 +1,280 @@
+package sync_fields
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestParseFieldMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []string
+		want     []FieldMapping
+		wantErr  string
+	}{
+		{
+			name:     "without priority",
+			mappings: []string{"Start date = Start"},
+			want:     []FieldMapping{{SourceField: "Start date", TargetField: "Start"}},
+		},
+		{
+			name:     "with priority",
+			mappings: []string{"Status=Status@1", "Due=Due date @ 2"},
+			want: []FieldMapping{
+				{SourceField: "Status", TargetField: "Status", Priority: 1},
+				{SourceField: "Due", TargetField: "Due date", Priority: 2},
+			},
+		},
+		{
+			name:     "reverse",
+			mappings: []string{"Done <- Completed:+1d@1"},
+			want:     []FieldMapping{{SourceField: "Done", TargetField: "Completed", Direction: DirectionReverse, DateOffset: 1, Priority: 1}},
+		},
+		{
+			name:     "reverse and forward",
+			mappings: []string{"Done<-Completed=Finished"},
+			wantErr:  "invalid field mapping format",
+		},
+		{
+			name:     "missing target",
+			mappings: []string{"Status"},
+			wantErr:  "invalid field mapping format",
+		},
+		{
+			name:     "invalid priority",
+			mappings: []string{"Status=Status@first"},
+			wantErr:  "invalid priority",
+		},
+		{
+			name:     "zero priority",
+			mappings: []string{"Status=Status@0"},
+			wantErr:  "invalid priority",
+		},
+		{
+			name:     "with date offsets",
+			mappings: []string{"start=Start date:+7d", "end=End:-2w@1", "Phase:1=Phase:2"},
+			want: []FieldMapping{
+				{SourceField: "start", TargetField: "Start date", DateOffset: 7},
+				{SourceField: "end", TargetField: "End", DateOffset: -14, Priority: 1},
+				{SourceField: "Phase:1", TargetField: "Phase:2"},
+			},
+		},
+		{
+			name:     "with field types",
+			mappings: []string{"start=Start date:date", "end=End:Date:+1d@1", "Status=Status:single_select{WIP:In Progress}", "Phase=Phase:later"},
+			want: []FieldMapping{
+				{SourceField: "start", TargetField: "Start date", FieldType: "DATE"},
+				{SourceField: "end", TargetField: "End", FieldType: "DATE", DateOffset: 1, Priority: 1},
+				{SourceField: "Status", TargetField: "Status", FieldType: "SINGLE_SELECT", ValueMap: map[string]string{"WIP": "In Progress"}},
+				{SourceField: "Phase", TargetField: "Phase:later"},
+			},
+		},
+		{
+			name:     "with value map",
+			mappings: []string{"Status=Status{WIP:In Progress, Done:Complete}@2"},
+			want: []FieldMapping{{
+				SourceField: "Status",
+				TargetField: "Status",
+				Priority:    2,
+				ValueMap:    map[string]string{"WIP": "In Progress", "Done": "Complete"},
+			}},
+		},
+		{
+			name:     "with option IDs",
+			mappings: []string{"Status=Status{WIP:In Progress,Done:#PVTSSO_2}"},
+			want: []FieldMapping{{
+				SourceField: "Status",
+				TargetField: "Status",
+				ValueMap:    map[string]string{"WIP": "In Progress", "Done": "#PVTSSO_2"},
+				OptionIDs:   map[string]string{"Done": "PVTSSO_2"},
+			}},
+		},
+		{
+			name:     "option ID missing",
+			mappings: []string{"Status=Status{Done:#}"},
+			wantErr:  `missing option ID for "Done"`,
+		},
+		{
+			name:     "value map entry without target value",
+			mappings: []string{"Status=Status{WIP}"},
+			wantErr:  "invalid value map",
+		},
+		{
+			name:     "value map with duplicate entry",
+			mappings: []string{"Status=Status{WIP:Doing,WIP:In Progress}"},
+			wantErr:  "duplicate entry",
+		},
+		{
+			name:     "date offset with unknown unit",
+			mappings: []string{"start=Start:+7m"},
+			wantErr:  "invalid date offset",
+		},
+		{
+			name:     "date offset without number",
+			mappings: []string{"start=Start:+d"},
+			wantErr:  "invalid date offset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFieldMappings(tt.mappings)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateMappingFields(t *testing.T) {
+	sourceConfigs := []github.ProjectFieldConfig{{Name: "Start date"}, {Name: "Status"}}
+	targetConfigs := []github.ProjectFieldConfig{{Name: "Start"}, {Name: "Status"}}
+
+	if err := validateMappingFields([]FieldMapping{{SourceField: "Start date", TargetField: "Start"}}, sourceConfigs, targetConfigs); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := validateMappingFields([]FieldMapping{
+		{SourceField: "Strat date", TargetField: "Start"},
+		{SourceField: "Status", TargetField: "Priority"},
+	}, sourceConfigs, targetConfigs)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	want := "field mappings refer to unknown fields:\n" +
+		"  source field \"Strat date\" not found in the source project, did you mean \"Start date\"?\n" +
+		"  target field \"Priority\" not found in the target project"
+	if err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err.Error())
+	}
+}
+
+func TestValidateFieldTypes(t *testing.T) {
+	targetConfigs := []github.ProjectFieldConfig{
+		{Name: "Start", DataType: "DATE"},
+		{Name: "Status", DataType: "SINGLE_SELECT"},
+	}
+
+	if err := validateFieldTypes([]FieldMapping{
+		{SourceField: "start", TargetField: "Start", FieldType: "DATE"},
+		{SourceField: "Status", TargetField: "Status"},
+	}, targetConfigs); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := validateFieldTypes([]FieldMapping{{SourceField: "Status", TargetField: "Status", FieldType: "DATE"}}, targetConfigs)
+	want := `field mapping Status=Status declares type DATE, but target field "Status" is of type SINGLE_SELECT`
+	if err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+}
+
+func TestResolveTargetFieldIDs(t *testing.T) {
+	mappings := []FieldMapping{
+		{SourceField: "Status", TargetField: "Status"},
+		{SourceField: "Start", TargetField: "Start date"},
+		{SourceField: "Owner", TargetField: "Missing"},
+	}
+	targetFieldConfigs := []github.ProjectFieldConfig{
+		{ID: "field_status", Name: "Status", DataType: "SINGLE_SELECT"},
+		{ID: "field_custom_status", Name: "Status", DataType: "SINGLE_SELECT"},
+		{ID: "field_start", Name: "Start date", DataType: "DATE"},
+	}
+
+	resolved := resolveTargetFieldIDs(mappings, targetFieldConfigs)
+
+	var ids []string
+	for _, mapping := range resolved {
+		ids = append(ids, mapping.TargetFieldID)
+	}
+	if want := []string{"field_status", "field_start", ""}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected target field IDs %v, got %v", want, ids)
+	}
+	if mappings[0].TargetFieldID != "" {
+		t.Error("expected the given mappings to be unchanged")
+	}
+}
+
+func TestResolveTargetOptions(t *testing.T) {
+	targetFieldConfigs := []github.ProjectFieldConfig{
+		{ID: "field_status", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{
+			{ID: "opt_done_1", Name: "Done"},
+			{ID: "opt_done_2", Name: "Done"},
+		}},
+		{ID: "field_start", Name: "Start date", DataType: "DATE"},
+	}
+	mapping := FieldMapping{
+		SourceField:   "Status",
+		TargetField:   "Status",
+		TargetFieldID: "field_status",
+		ValueMap:      map[string]string{"Closed": "#opt_done_2", "WIP": "In Progress"},
+		OptionIDs:     map[string]string{"Closed": "opt_done_2"},
+	}
+
+	resolved, err := resolveTargetOptions([]FieldMapping{mapping}, targetFieldConfigs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]string{"Closed": "Done", "WIP": "In Progress"}; !reflect.DeepEqual(resolved[0].ValueMap, want) {
+		t.Errorf("expected value map %v, got %v", want, resolved[0].ValueMap)
+	}
+	if mapping.ValueMap["Closed"] != "#opt_done_2" {
+		t.Error("expected the given mapping to be unchanged")
+	}
+
+	// The option is written by ID, and source option IDs are dropped
+	closed, wip := "Closed", "WIP"
+	value, err := resolved[0].transform(github.ProjectFieldValue{Text: &closed, OptionID: "source_opt"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *value.Text != "Done" || value.OptionID != "opt_done_2" {
+		t.Errorf("expected option Done with ID opt_done_2, got %q with ID %q", *value.Text, value.OptionID)
+	}
+	value, _ = resolved[0].transform(github.ProjectFieldValue{Text: &wip, OptionID: "source_opt"})
+	if *value.Text != "In Progress" || value.OptionID != "" {
+		t.Errorf("expected option In Progress without ID, got %q with ID %q", *value.Text, value.OptionID)
+	}
+
+	unknown := mapping
+	unknown.OptionIDs = map[string]string{"Closed": "opt_missing"}
+	if _, err := resolveTargetOptions([]FieldMapping{unknown}, targetFieldConfigs); err == nil || !strings.Contains(err.Error(), "option ID opt_missing") {
+		t.Errorf("expected an error for the unknown option ID, got %v", err)
+	}
+
+	dateField := mapping
+	dateField.TargetField, dateField.TargetFieldID = "Start date", "field_start"
+	if _, err := resolveTargetOptions([]FieldMapping{dateField}, targetFieldConfigs); err == nil || !strings.Contains(err.Error(), "is not a single select field") {
+		t.Errorf("expected an error for the date field, got %v", err)
+	}
+}
+
+func TestTransformChoosesIterationsByTitle(t *testing.T) {
+	sprint := "Sprint 1"
+	value, err := FieldMapping{SourceField: "Sprint", TargetField: "Sprint"}.transform(github.ProjectFieldValue{Iteration: &sprint, IterationID: "source_it"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *value.Iteration != "Sprint 1" || value.IterationID != "" {
+		t.Errorf("expected iteration Sprint 1 without ID, got %q with ID %q", *value.Iteration, value.IterationID)
+	}
+
+	// Iterations of both projects are compared by title
+	target := github.ProjectField{Value: github.ProjectFieldValue{Iteration: &sprint, IterationID: "target_it"}}
+	if !fieldsEqual(github.ProjectField{Value: value}, target) {
+		t.Error("expected iterations of the same title to be equal")
+	}
+}
@@ -0,0 +1,40 @@
+package sync_fields
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Metrics are the counters of a sync run exported in the Prometheus text format
+type Metrics struct {
+	// Summary holds the issue and field counters of the run
+	Summary Summary
+	// APICalls counts the GraphQL requests sent, including retries
+	APICalls int
+	// Duration is the time the sync took
+	Duration time.Duration
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format, as read by
+// the textfile collector of the node exporter
+func (m Metrics) WritePrometheus(w io.Writer) error {
+	metrics := []struct {
+		name, help, kind string
+		value            float64
+	}{
+		{"gh_sync_issues_total", "Number of issues the field mappings were applied to.", "counter", float64(m.Summary.IssuesProcessed)},
+		{"gh_sync_fields_updated_total", "Number of target fields updated, or planned to be updated in dry run mode.", "counter", float64(m.Summary.FieldsUpdated)},
+		{"gh_sync_fields_skipped_total", "Number of target fields that already had the source value.", "counter", float64(m.Summary.FieldsSkipped)},
+		{"gh_sync_api_calls_total", "Number of GitHub GraphQL API requests, including retries.", "counter", float64(m.APICalls)},
+		{"gh_sync_duration_seconds", "Duration of the sync in seconds.", "gauge", m.Duration.Seconds()},
+	}
+
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n",
+			metric.name, metric.help, metric.name, metric.kind, metric.name, metric.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package sync_fields
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsWritePrometheus(t *testing.T) {
+	metrics := Metrics{
+		Summary: Summary{
+			IssuesProcessed: 12,
+			FieldsUpdated:   5,
+			FieldsSkipped:   19,
+		},
+		APICalls: 31,
+		Duration: 2500 * time.Millisecond,
+	}
+
+	var out strings.Builder
+	if err := metrics.WritePrometheus(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `# HELP gh_sync_issues_total Number of issues the field mappings were applied to.
+# TYPE gh_sync_issues_total counter
+gh_sync_issues_total 12
+# HELP gh_sync_fields_updated_total Number of target fields updated, or planned to be updated in dry run mode.
+# TYPE gh_sync_fields_updated_total counter
+gh_sync_fields_updated_total 5
+# HELP gh_sync_fields_skipped_total Number of target fields that already had the source value.
+# TYPE gh_sync_fields_skipped_total counter
+gh_sync_fields_skipped_total 19
+# HELP gh_sync_api_calls_total Number of GitHub GraphQL API requests, including retries.
+# TYPE gh_sync_api_calls_total counter
+gh_sync_api_calls_total 31
+# HELP gh_sync_duration_seconds Duration of the sync in seconds.
+# TYPE gh_sync_duration_seconds gauge
+gh_sync_duration_seconds 2.5
+`
+	if out.String() != expected {
+		t.Errorf("unexpected metrics:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
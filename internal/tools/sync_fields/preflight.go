@@ -0,0 +1,137 @@
+package sync_fields
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// UnresolvedOptionsError lists the distinct source values that match no option of their
+// single select target field, grouped by target field
+type UnresolvedOptionsError struct {
+	// Values maps target field names to the sorted source values without a matching option
+	Values map[string][]string
+}
+
+func (e *UnresolvedOptionsError) Error() string {
+	fields := make([]string, 0, len(e.Values))
+	count := 0
+	for field, values := range e.Values {
+		fields = append(fields, field)
+		count += len(values)
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d source values have no matching option in the target project:", count)
+	for _, field := range fields {
+		quoted := make([]string, len(e.Values[field]))
+		for i, value := range e.Values[field] {
+			quoted[i] = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", field, strings.Join(quoted, ", "))
+	}
+	b.WriteString("\nadd the missing options to the target project or use --create-missing-options")
+	return b.String()
+}
+
+// preflightOptions checks that every source value mapped to a single select target field
+// resolves to an option of that field, so that all missing options are reported at once
+// before anything is written
+func (s *Service) preflightOptions(ctx context.Context, sourceProjectID string, issues []string, sourceFieldConfigs, targetFieldConfigs []github.ProjectFieldConfig, mappings []FieldMapping) error {
+	targetOptions := s.targetOptions(targetFieldConfigs)
+
+	var selectMappings []FieldMapping
+	for _, mapping := range mappings {
+		if _, ok := targetOptions[fieldKey(mapping.TargetFieldID, mapping.TargetField)]; ok {
+			selectMappings = append(selectMappings, mapping)
+		}
+	}
+	if len(selectMappings) == 0 {
+		return nil
+	}
+
+	unresolved := make(map[string]map[string]bool)
+	for _, issueURL := range issues {
+		// Field values are served from the cached project, so this does not query the API per issue
+		sourceFields, err := s.client.GetProjectFieldValues(ctx, sourceProjectID, issueURL, sourceFieldConfigs)
+		if err != nil {
+			err = fmt.Errorf("failed to get source field values for %s: %w", issueURL, err)
+			// Missing issues are skipped and recorded when their fields are synced
+			if s.skippable(err) {
+				continue
+			}
+			return err
+		}
+
+		for _, mapping := range selectMappings {
+			for _, field := range sourceFields {
+				if field.Name != mapping.SourceField || field.Value.Text == nil {
+					continue
+				}
+				value := mapping.mapValue(*field.Value.Text)
+				if targetOptions[fieldKey(mapping.TargetFieldID, mapping.TargetField)][s.optionKey(value)] {
+					continue
+				}
+				if unresolved[mapping.TargetField] == nil {
+					unresolved[mapping.TargetField] = make(map[string]bool)
+				}
+				unresolved[mapping.TargetField][value] = true
+			}
+		}
+	}
+
+	if len(unresolved) == 0 {
+		return nil
+	}
+	return newUnresolvedOptionsError(unresolved)
+}
+
+// targetOptions returns the options of the single select target fields by fieldKey, as
+// several fields may share a name
+func (s *Service) targetOptions(targetFieldConfigs []github.ProjectFieldConfig) map[string]map[string]bool {
+	targetOptions := make(map[string]map[string]bool)
+	for _, config := range targetFieldConfigs {
+		if config.DataType != "SINGLE_SELECT" {
+			continue
+		}
+		options := make(map[string]bool, len(config.Options))
+		for _, option := range config.Options {
+			options[s.optionKey(option.Name)] = true
+		}
+		targetOptions[fieldKey(config.ID, config.Name)] = options
+	}
+	return targetOptions
+}
+
+// fieldKey identifies a target field by its ID, or by its name if it is yet to be created
+func fieldKey(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return name
+}
+
+// optionKey returns the name options are resolved by. With normalization, options are
+// resolved by their normalized names like in the client.
+func (s *Service) optionKey(name string) string {
+	if s.normalize {
+		return github.NormalizeOptionName(name)
+	}
+	return name
+}
+
+// newUnresolvedOptionsError lists the given sets of source values by target field, sorted
+func newUnresolvedOptionsError(unresolved map[string]map[string]bool) *UnresolvedOptionsError {
+	err := &UnresolvedOptionsError{Values: make(map[string][]string, len(unresolved))}
+	for field, values := range unresolved {
+		for value := range values {
+			err.Values[field] = append(err.Values[field], value)
+		}
+		sort.Strings(err.Values[field])
+	}
+	return err
+}
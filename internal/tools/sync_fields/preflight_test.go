@@ -0,0 +1,257 @@
+package sync_fields
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+func TestSyncFieldsReportsUnresolvedOptionsBeforeUpdating(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}
+	sourceValues := map[string]map[string]string{
+		issues[0]: {"Status": "Blocked", "Size": "XL"},
+		issues[1]: {"Status": "In review", "Size": "M"},
+		issues[2]: {"Status": "Blocked", "Size": "XXL"},
+	}
+
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			if projectInfo.ProjectNumber == 824 {
+				return "project_1", nil
+			}
+			return "project_2", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			return []github.ProjectFieldConfig{
+					{ID: "1", Name: "Status", DataType: "SINGLE_SELECT"},
+					{ID: "2", Name: "Size", DataType: "SINGLE_SELECT"},
+				},
+				[]github.ProjectFieldConfig{
+					{ID: "3", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "o1", Name: "Done"}}},
+					{ID: "4", Name: "Size", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "o2", Name: "M"}}},
+				},
+				issues,
+				issues,
+				nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			if projectID != "project_1" {
+				return []github.ProjectField{}, nil
+			}
+			var fields []github.ProjectField
+			for _, name := range []string{"Status", "Size"} {
+				value := sourceValues[issueURL][name]
+				fields = append(fields, github.ProjectField{Name: name, Value: github.ProjectFieldValue{Text: &value}})
+			}
+			return fields, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			t.Errorf("expected no updates, got %s=%s for %s", field.Name, field.Value, issueURL)
+			return nil
+		},
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"Status=Status", "Size=Size"},
+	)
+
+	var unresolvedErr *UnresolvedOptionsError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("expected an unresolved options error, got %v", err)
+	}
+
+	want := map[string][]string{
+		"Status": {"Blocked", "In review"},
+		"Size":   {"XL", "XXL"},
+	}
+	if !reflect.DeepEqual(unresolvedErr.Values, want) {
+		t.Errorf("expected unresolved values %v, got %v", want, unresolvedErr.Values)
+	}
+	if !strings.Contains(err.Error(), "4 source values have no matching option") {
+		t.Errorf("expected the number of unresolved values in %q", err.Error())
+	}
+}
+
+func TestSyncFieldsSkipsOptionPreflightWhenCreatingOptions(t *testing.T) {
+	value := "Blocked"
+	var updated []string
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			if projectInfo.ProjectNumber == 824 {
+				return "project_1", nil
+			}
+			return "project_2", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			issues := []string{"https://github.com/org/repo/issues/1"}
+			return []github.ProjectFieldConfig{{ID: "1", Name: "Status", DataType: "SINGLE_SELECT"}},
+				[]github.ProjectFieldConfig{{ID: "2", Name: "Status", DataType: "SINGLE_SELECT"}},
+				issues,
+				issues,
+				nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			if projectID != "project_1" {
+				return []github.ProjectField{}, nil
+			}
+			return []github.ProjectField{{Name: "Status", Value: github.ProjectFieldValue{Text: &value}}}, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			updated = append(updated, field.Value.String())
+			return nil
+		},
+	}
+
+	service := NewService(mockClient, Options{CreateMissingOptions: true})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"Status=Status"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated) != 1 || updated[0] != value {
+		t.Errorf("expected %q to be written, got %v", value, updated)
+	}
+}
+
+func TestSyncFieldsNormalizeSelect(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	sourceValue := "🚧 In Progress"
+	targetValue := "In  Progress "
+
+	newClient := func(updates *int) *client.MockClient {
+		return &client.MockClient{
+			GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+				if projectInfo.ProjectNumber == 824 {
+					return "project_1", nil
+				}
+				return "project_2", nil
+			},
+			GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+				return []github.ProjectFieldConfig{
+						{ID: "1", Name: "Status", DataType: "SINGLE_SELECT"},
+					},
+					[]github.ProjectFieldConfig{
+						{ID: "2", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "o1", Name: "In Progress"}}},
+					},
+					[]string{issueURL},
+					[]string{issueURL},
+					nil
+			},
+			GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+				value := targetValue
+				if projectID == "project_1" {
+					value = sourceValue
+				}
+				return []github.ProjectField{{Name: "Status", Value: github.ProjectFieldValue{Text: &value}}}, nil
+			},
+			UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+				*updates++
+				return nil
+			},
+		}
+	}
+
+	t.Run("exact match by default", func(t *testing.T) {
+		var updates int
+		service := NewService(newClient(&updates), Options{})
+		err := service.SyncFields(context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/orgs/myorg/projects/825",
+			nil,
+			[]string{"Status=Status"},
+		)
+
+		var unresolvedErr *UnresolvedOptionsError
+		if !errors.As(err, &unresolvedErr) {
+			t.Fatalf("expected an unresolved options error, got %v", err)
+		}
+		if updates != 0 {
+			t.Errorf("expected no updates, got %d", updates)
+		}
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		var updates int
+		service := NewService(newClient(&updates), Options{NormalizeSelect: true})
+		err := service.SyncFields(context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/orgs/myorg/projects/825",
+			nil,
+			[]string{"Status=Status"},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if updates != 0 {
+			t.Errorf("expected the values to be equal, got %d updates", updates)
+		}
+		if skipped := service.Result().FieldsSkipped; skipped != 1 {
+			t.Errorf("expected 1 skipped field, got %d", skipped)
+		}
+	})
+}
+
+func TestSyncFieldsPreflightsTheMappedFieldOfSharedNames(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	done := "Done"
+
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{{ID: "1", Name: "Status", DataType: "SINGLE_SELECT"}},
+			// The first of the fields sharing a name is written to, so only its options count
+			[]github.ProjectFieldConfig{
+				{ID: "3", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "o1", Name: done}}},
+				{ID: "4", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "o2", Name: "Todo"}}},
+			},
+			issues,
+			issues,
+			nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID != "project_1" {
+			return nil, nil
+		}
+		return []github.ProjectField{{ID: "1", Name: "Status", Value: github.ProjectFieldValue{Text: &done}}}, nil
+	}
+
+	var updated []string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updated = append(updated, field.ID)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"Status=Status"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(updated, []string{"3"}) {
+		t.Errorf("expected the first Status field to be updated, got %v", updated)
+	}
+}
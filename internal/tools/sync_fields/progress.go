@@ -0,0 +1,90 @@
+package sync_fields
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultProgressInterval is the default minimum delay between two progress log lines
+const DefaultProgressInterval = 5 * time.Second
+
+// progressBarWidth is the number of characters of a rendered progress bar
+const progressBarWidth = 30
+
+// progressReporter reports how many issues of a sync have been processed. It renders a
+// progress bar when writing to a terminal and logs throttled progress lines otherwise.
+type progressReporter struct {
+	total    int
+	terminal io.Writer
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	processed int
+	lastLog   time.Time
+}
+
+// newProgressReporter creates a progress reporter for the given number of issues. If terminal
+// is set, a progress bar is rendered to it instead of logging progress lines.
+func newProgressReporter(total int, terminal io.Writer, interval time.Duration) *progressReporter {
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	p := &progressReporter{
+		total:    total,
+		terminal: terminal,
+		interval: interval,
+		now:      time.Now,
+	}
+	p.lastLog = p.now()
+	return p
+}
+
+// issueProcessed records a processed issue and reports the progress if due
+func (p *progressReporter) issueProcessed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.processed++
+	if p.terminal != nil {
+		p.render()
+		return
+	}
+
+	if now := p.now(); now.Sub(p.lastLog) >= p.interval {
+		p.lastLog = now
+		p.log()
+	}
+}
+
+// finish reports the final progress and ends the progress bar
+func (p *progressReporter) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.terminal != nil {
+		p.render()
+		fmt.Fprintln(p.terminal)
+		return
+	}
+	p.log()
+}
+
+// log logs the current progress
+func (p *progressReporter) log() {
+	slog.Info(fmt.Sprintf("processed %d/%d issues", p.processed, p.total))
+}
+
+// render redraws the progress bar on the current terminal line
+func (p *progressReporter) render() {
+	filled := progressBarWidth
+	if p.total > 0 {
+		filled = min(progressBarWidth, p.processed*progressBarWidth/p.total)
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	fmt.Fprintf(p.terminal, "\r\033[K[%s] %d/%d issues", bar, p.processed, p.total)
+}
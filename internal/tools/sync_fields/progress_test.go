@@ -0,0 +1,58 @@
+package sync_fields
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReporterLogsThrottled(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := newProgressReporter(4, nil, time.Minute)
+	p.now = func() time.Time { return now }
+	p.lastLog = now
+
+	p.issueProcessed()
+	now = now.Add(30 * time.Second)
+	p.issueProcessed()
+	now = now.Add(30 * time.Second)
+	p.issueProcessed()
+	p.issueProcessed()
+	p.finish()
+
+	want := "level=INFO msg=\"processed 3/4 issues\"\nlevel=INFO msg=\"processed 4/4 issues\"\n"
+	if buf.String() != want {
+		t.Errorf("expected logs %q, got %q", want, buf.String())
+	}
+}
+
+func TestProgressReporterRendersBar(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressReporter(3, &buf, 0)
+
+	p.issueProcessed()
+	if !strings.HasSuffix(buf.String(), "["+strings.Repeat("=", 10)+strings.Repeat(" ", 20)+"] 1/3 issues") {
+		t.Errorf("unexpected progress bar %q", buf.String())
+	}
+
+	p.issueProcessed()
+	p.issueProcessed()
+	p.finish()
+	if !strings.HasSuffix(buf.String(), "["+strings.Repeat("=", 30)+"] 3/3 issues\n") {
+		t.Errorf("expected a completed progress bar, got %q", buf.String())
+	}
+}
@@ -0,0 +1,123 @@
+package sync_fields
+
+const (
+	// ActionUpdated marks a target field that was written, or planned to be written in dry run mode
+	ActionUpdated = "updated"
+	// ActionUnchanged marks a target field that already had the source value
+	ActionUnchanged = "unchanged"
+	// ActionKept marks a target field whose differing value was kept as only empty fields are filled
+	ActionKept = "kept"
+)
+
+// Report is the structured outcome of a sync run, grouped by issue
+type Report struct {
+	DryRun          bool          `json:"dry_run"`
+	SourceProjectID string        `json:"source_project_id"`
+	TargetProjectID string        `json:"target_project_id,omitempty"`
+	Issues          []IssueReport `json:"issues"`
+	PrunedIssues    []string      `json:"pruned_issues,omitempty"`
+	AddedIssues     []string      `json:"added_issues,omitempty"`
+	CreatedFields   []string      `json:"created_fields,omitempty"`
+	// Targets holds the report of each target project after syncing to several of them
+	Targets []TargetReport `json:"targets,omitempty"`
+}
+
+// TargetReport is the report of the sync of one of several target projects
+type TargetReport struct {
+	TargetProject string `json:"target_project"`
+	// Error is the error that ended the sync of the target project, if any
+	Error string `json:"error,omitempty"`
+	Report
+}
+
+// IssueReport lists the actions taken for the fields of a single issue
+type IssueReport struct {
+	IssueURL string        `json:"issue_url"`
+	Title    string        `json:"title,omitempty"`
+	Fields   []FieldAction `json:"fields"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// FieldAction describes what was done to a single target field
+type FieldAction struct {
+	Field    string `json:"field"`
+	Action   string `json:"action"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// Report returns the outcome of the last sync run, with one entry per selected issue in
+// the order the issues were given or detected. After syncing to several target projects,
+// the issues are listed per target project in Targets.
+func (s *Service) Report() Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.targets) == 0 {
+		return s.report(s.result)
+	}
+
+	report := Report{DryRun: s.dryRun, Issues: []IssueReport{}}
+	for _, target := range s.targets {
+		targetReport := TargetReport{TargetProject: target.TargetProjectURL, Report: s.report(target.Result)}
+		if target.Err != nil {
+			targetReport.Error = target.Err.Error()
+		}
+		if report.SourceProjectID == "" {
+			report.SourceProjectID = target.Result.SourceProjectID
+		}
+		report.Targets = append(report.Targets, targetReport)
+	}
+	return report
+}
+
+// report groups the outcome of syncing one target project by issue. The caller must hold the lock.
+func (s *Service) report(result Result) Report {
+	issues := make([]IssueReport, len(result.Issues))
+	position := make(map[string]int, len(result.Issues))
+	for i, issueURL := range result.Issues {
+		issues[i] = IssueReport{IssueURL: issueURL, Title: s.titles[issueURL], Fields: []FieldAction{}}
+		position[issueURL] = i
+	}
+
+	addField := func(change FieldChange, action string) {
+		i, ok := position[change.IssueURL]
+		if !ok {
+			return
+		}
+		issues[i].Title = change.Title
+		issues[i].Fields = append(issues[i].Fields, FieldAction{
+			Field:    change.Field,
+			Action:   action,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+	for _, change := range result.Changes {
+		addField(change, ActionUpdated)
+	}
+	for _, change := range result.Unchanged {
+		addField(change, ActionUnchanged)
+	}
+	for _, change := range result.Kept {
+		addField(change, ActionKept)
+	}
+	for _, issueErr := range result.Errors {
+		if i, ok := position[issueErr.IssueURL]; ok {
+			issues[i].Error = issueErr.Error
+			if issueErr.Title != "" {
+				issues[i].Title = issueErr.Title
+			}
+		}
+	}
+
+	return Report{
+		DryRun:          s.dryRun,
+		SourceProjectID: result.SourceProjectID,
+		TargetProjectID: result.TargetProjectID,
+		Issues:          issues,
+		PrunedIssues:    result.PrunedIssues,
+		AddedIssues:     result.AddedIssues,
+		CreatedFields:   result.CreatedFields,
+	}
+}
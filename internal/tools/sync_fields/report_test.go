@@ -0,0 +1,130 @@
+package sync_fields
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestSyncFieldsReport(t *testing.T) {
+	now := time.Now()
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}
+
+	mockClient := newSyncMockClient(issues, now)
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &now}}}, nil
+		}
+		if issueURL == issues[0] {
+			return []github.ProjectField{{ID: "2", Name: "Start date", Value: github.ProjectFieldValue{Date: &now}}}, nil
+		}
+		return []github.ProjectField{}, nil
+	}
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		if issueURL == issues[1] {
+			return errors.New("update failed")
+		}
+		return nil
+	}
+
+	service := NewService(mockClient, Options{DryRun: true, Concurrency: 3})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	report := service.Report()
+	if !report.DryRun || report.SourceProjectID != "project_1" || report.TargetProjectID != "project_2" {
+		t.Errorf("unexpected report header: %+v", report)
+	}
+	if len(report.Issues) != len(issues) {
+		t.Fatalf("expected %d issues, got %d", len(issues), len(report.Issues))
+	}
+
+	want := []struct {
+		action string
+		err    bool
+	}{
+		{action: ActionUnchanged},
+		{err: true},
+		{action: ActionUpdated},
+	}
+	for i, issue := range report.Issues {
+		checkReportIssue(t, issue, issues[i], want[i].action, want[i].err)
+	}
+}
+
+// checkReportIssue checks the URL, error and field action of an issue of a sync report. An
+// empty action expects no field actions.
+func checkReportIssue(t *testing.T, issue IssueReport, issueURL, action string, wantErr bool) {
+	t.Helper()
+
+	if issue.IssueURL != issueURL {
+		t.Errorf("expected issue to be %s, got %s", issueURL, issue.IssueURL)
+	}
+	if (issue.Error != "") != wantErr {
+		t.Errorf("unexpected error for %s: %q", issue.IssueURL, issue.Error)
+	}
+	if action == "" {
+		if len(issue.Fields) != 0 {
+			t.Errorf("expected no field actions for %s, got %v", issue.IssueURL, issue.Fields)
+		}
+		return
+	}
+	if len(issue.Fields) != 1 || issue.Fields[0].Action != action || issue.Fields[0].Field != "Start date" {
+		t.Errorf("expected the start date of %s to be %s, got %v", issue.IssueURL, action, issue.Fields)
+	}
+}
+
+func TestSyncFieldsReportPerTarget(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	targets := []string{
+		"https://github.com/orgs/myorg/projects/825",
+		"https://github.com/orgs/myorg/projects/826",
+	}
+
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectIDFunc = func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+		if projectInfo.ProjectNumber == 824 {
+			return "project_1", nil
+		}
+		return fmt.Sprintf("project_%d", projectInfo.ProjectNumber), nil
+	}
+
+	service := NewService(mockClient, Options{DryRun: true})
+	err := service.SyncFieldsToTargets(context.Background(), "https://github.com/orgs/myorg/projects/824", targets, nil, []string{"start=Start date"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := service.Report()
+	if report.SourceProjectID != "project_1" || report.TargetProjectID != "" || len(report.Issues) != 0 {
+		t.Errorf("unexpected report header: %+v", report)
+	}
+	if len(report.Targets) != len(targets) {
+		t.Fatalf("expected a report per target project, got %+v", report.Targets)
+	}
+	for i, target := range report.Targets {
+		if target.TargetProject != targets[i] || target.TargetProjectID != fmt.Sprintf("project_%d", 825+i) {
+			t.Errorf("unexpected target project of report %d: %+v", i, target)
+		}
+		if len(target.Issues) != 1 {
+			t.Fatalf("expected 1 issue in report of %s, got %+v", target.TargetProject, target.Issues)
+		}
+		checkReportIssue(t, target.Issues[0], issues[0], ActionUpdated, false)
+	}
+}
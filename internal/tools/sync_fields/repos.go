@@ -0,0 +1,38 @@
+package sync_fields
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/naag/gh-project-toolkit/internal/github/util"
+)
+
+// validateRepos checks that the repository filters are in the format owner/name
+func validateRepos(repos []string) error {
+	for _, repo := range repos {
+		owner, name, ok := strings.Cut(repo, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("invalid repository %q, expected owner/name", repo)
+		}
+	}
+	return nil
+}
+
+// filterIssuesByRepos keeps the issues of the given repositories. Repositories are compared
+// case-insensitively, like GitHub does, and issues without a repository such as draft
+// issues are dropped.
+func filterIssuesByRepos(issues []string, repos []string) []string {
+	wanted := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		wanted[strings.ToLower(repo)] = true
+	}
+
+	var filtered []string
+	for _, issueURL := range issues {
+		repo, err := util.ParseIssueRepository(issueURL)
+		if err == nil && wanted[strings.ToLower(repo)] {
+			filtered = append(filtered, issueURL)
+		}
+	}
+	return filtered
+}
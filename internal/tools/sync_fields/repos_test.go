@@ -0,0 +1,69 @@
+package sync_fields
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilterIssuesByRepos(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/api/issues/1",
+		"https://github.com/org/web/issues/2",
+		"https://github.com/Org/API/issues/3",
+		"draft:Plan the launch",
+	}
+
+	got := filterIssuesByRepos(issues, []string{"org/api"})
+	want := []string{issues[0], issues[2]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestSyncFieldsFiltersIssuesByRepos(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/api/issues/1",
+		"https://github.com/org/web/issues/2",
+		"https://github.com/org/docs/issues/3",
+	}
+
+	service := NewService(newSyncMockClient(issues, time.Now()), Options{DryRun: true, Repos: []string{"org/api", "org/docs"}})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var synced []string
+	for _, change := range service.Result().Changes {
+		synced = append(synced, change.IssueURL)
+	}
+	want := []string{issues[0], issues[2]}
+	if !reflect.DeepEqual(synced, want) {
+		t.Errorf("expected synced issues %v, got %v", want, synced)
+	}
+	if filtered := service.Summary().FilteredByRepo; filtered != 1 {
+		t.Errorf("expected 1 issue filtered by repository, got %d", filtered)
+	}
+}
+
+func TestSyncFieldsRejectsInvalidRepo(t *testing.T) {
+	service := NewService(newSyncMockClient(nil, time.Now()), Options{Repos: []string{"org"}})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil || err.Error() != `invalid repository "org", expected owner/name` {
+		t.Errorf("expected an invalid repository error, got %v", err)
+	}
+}
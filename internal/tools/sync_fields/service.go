@@ -2,69 +2,922 @@ package sync_fields
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/naag/gh-project-toolkit/internal/github"
 	"github.com/naag/gh-project-toolkit/internal/github/client"
 	"github.com/naag/gh-project-toolkit/internal/github/util"
 )
 
+// DefaultConcurrency is the default number of issues processed in parallel
+const DefaultConcurrency = 4
+
+// Options configures the sync service
+type Options struct {
+	// DryRun disables all mutations
+	DryRun bool
+	// Preview compares the source and target values without calling the client to update
+	// fields, not even in dry run mode, and implies DryRun
+	Preview bool
+	// Concurrency is the number of issues processed in parallel
+	Concurrency int
+	// FailFast aborts the sync on the first failed issue instead of continuing with the rest
+	FailFast bool
+	// AllowSameProject allows the source and target project to be the same project
+	AllowSameProject bool
+	// PruneTargetItems removes target project items whose issue is not in the source project
+	PruneTargetItems bool
+	// CreateMissingOptions skips the check for source values without a matching single
+	// select option in the target, as the client creates missing options
+	CreateMissingOptions bool
+	// LenientMappings only warns about field mappings naming fields missing in the projects,
+	// instead of failing before anything is synced
+	LenientMappings bool
+	// Repos restricts the sync to issues of these repositories, given as owner/name
+	Repos []string
+	// FilterLabels restricts the sync to issues carrying these labels
+	FilterLabels []string
+	// LabelMatch is LabelMatchAll (default) to require all filter labels, or LabelMatchAny
+	LabelMatch string
+	// IssueState is IssueStateAll (default) to sync issues regardless of their state, or
+	// IssueStateOpen or IssueStateClosed to only sync issues in that state
+	IssueState string
+	// IssueFilter restricts the detected common issues to the source project items matching
+	// this project filter expression, applied on the client. Unused if issues are given.
+	IssueFilter string
+	// Since restricts the sync to issues updated at or after this time, if not zero
+	Since time.Time
+	// MaxIssues caps the number of issues synced after filtering, if positive
+	MaxIssues int
+	// NormalizeSelect compares single select values ignoring leading emoji and differences
+	// in whitespace, which must match the setting of the client
+	NormalizeSelect bool
+	// RequireSourceValue fails issues without a value in a mapped source field, which are
+	// skipped otherwise. Fields missing in the source project fail the sync either way.
+	RequireSourceValue bool
+	// OnlyFillEmpty only writes target fields without a value, so that existing target
+	// values are never overwritten
+	OnlyFillEmpty bool
+	// AddMissingToTarget adds the selected issues of the source project missing in the target
+	// project to it, and syncs their fields in the same run. In dry run mode, the issues are
+	// only recorded, and their fields are not synced as they have no target item yet.
+	AddMissingToTarget bool
+	// CreateMissingFields creates the target fields of forward mappings missing in the target
+	// project, with the type and single select options of their source field. In dry run
+	// mode, the fields are only recorded, and the mappings to them are skipped.
+	CreateMissingFields bool
+	// ReportOrphans logs the issues that are in only one of the projects and adds them to
+	// the result and summary
+	ReportOrphans bool
+	// FailOnOrphans fails the sync after processing the common issues if any issue is in
+	// only one of the projects, and implies ReportOrphans
+	FailOnOrphans bool
+	// ProgressBar is the terminal to render a progress bar on. If nil, the progress is
+	// logged periodically instead.
+	ProgressBar io.Writer
+	// ProgressInterval is the minimum delay between two progress log lines
+	ProgressInterval time.Duration
+}
+
 type Service struct {
-	client client.Client
-	dryRun bool
+	client        client.Client
+	dryRun        bool
+	preview       bool
+	concurrency   int
+	failFast      bool
+	allowSame     bool
+	prune         bool
+	createOptions bool
+	lenient       bool
+	repos         []string
+	filterLabels  []string
+	labelMatch    string
+	issueFilter   string
+	issueState    string
+	since         time.Time
+	maxIssues     int
+	normalize     bool
+	requireValue  bool
+	addMissing    bool
+	createFields  bool
+	onlyFillEmpty bool
+	reportOrphans bool
+	failOnOrphans bool
+
+	progressBar      io.Writer
+	progressInterval time.Duration
+
+	mu     sync.Mutex
+	result Result
+	// titles holds the issue titles prefetched in dry run mode, for the report
+	titles map[string]string
+	// targets holds the results per target project of SyncFieldsToTargets
+	targets []TargetResult
+}
+
+// Result summarizes the outcome of a sync run
+type Result struct {
+	// IssuesWithoutSourceValues lists issues that had no value in any mapped source field
+	IssuesWithoutSourceValues []string `json:"issues_without_source_values"`
+	// Changes lists the field changes applied, or planned in dry run mode
+	Changes []FieldChange `json:"changes"`
+	// Errors lists the issues that failed to sync
+	Errors []IssueError `json:"errors"`
+	// PrunedIssues lists the issues removed from the target project, or planned to be removed in dry run mode
+	PrunedIssues []string `json:"pruned_issues,omitempty"`
+	// AddedIssues lists the issues added to the target project, or planned to be added in dry run mode
+	AddedIssues []string `json:"added_issues,omitempty"`
+	// CreatedFields lists the target fields created, or planned to be created in dry run mode
+	CreatedFields []string `json:"created_fields,omitempty"`
+	// UnprocessedIssues lists the issues left unprocessed as the API call budget of the client
+	// was exhausted, in the order they would have been processed
+	UnprocessedIssues []string `json:"unprocessed_issues,omitempty"`
+	// IssuesProcessed counts the issues the field mappings were applied to
+	IssuesProcessed int `json:"issues_processed"`
+	// FieldsSkipped counts the target fields that already had the source value
+	FieldsSkipped int `json:"fields_skipped"`
+	// FieldsKept counts the target fields whose differing value was kept as only empty
+	// fields are filled
+	FieldsKept int `json:"fields_kept"`
+	// FieldsCleared counts the target fields whose value was removed
+	FieldsCleared int `json:"fields_cleared"`
+	// IssuesFilteredByRepo counts the issues skipped as they belong to other repositories
+	IssuesFilteredByRepo int `json:"issues_filtered_by_repo"`
+	// IssuesFilteredByState counts the issues skipped as they are not in the wanted state
+	IssuesFilteredByState int `json:"issues_filtered_by_state"`
+	// DuplicateIssues counts the duplicate items of issues dropped from the projects
+	DuplicateIssues int `json:"duplicate_issues"`
+	// Unchanged lists the target fields that already had the source value
+	Unchanged []FieldChange `json:"unchanged,omitempty"`
+	// Kept lists the target fields whose differing value was kept as only empty fields are filled
+	Kept []FieldChange `json:"kept,omitempty"`
+	// SourceProjectID and TargetProjectID are the node IDs of the synced projects
+	SourceProjectID string `json:"source_project_id"`
+	TargetProjectID string `json:"target_project_id"`
+	// Issues lists the issues selected for the sync
+	Issues []string `json:"issues"`
+	// SourceOnlyIssues lists the issues of the source project missing in the target project,
+	// and TargetOnlyIssues the reverse, if orphans are reported
+	SourceOnlyIssues []string `json:"source_only_issues,omitempty"`
+	TargetOnlyIssues []string `json:"target_only_issues,omitempty"`
 }
 
-func NewService(client client.Client, dryRun bool) *Service {
+// Summary is a machine-readable summary of a sync run
+type Summary struct {
+	DryRun          bool         `json:"dry_run"`
+	IssuesProcessed int          `json:"issues_processed"`
+	FieldsUpdated   int          `json:"fields_updated"`
+	FieldsSkipped   int          `json:"fields_skipped"`
+	FieldsKept      int          `json:"fields_kept"`
+	FieldsCleared   int          `json:"fields_cleared"`
+	DuplicateIssues int          `json:"duplicate_issues"`
+	FilteredByRepo  int          `json:"issues_filtered_by_repo"`
+	FilteredByState int          `json:"issues_filtered_by_state"`
+	Errors          []IssueError `json:"errors"`
+	// QueryCost is the summed up rate limit cost of the queries of the run, if tracked by
+	// the client
+	QueryCost int `json:"query_cost,omitempty"`
+	// SourceOnlyIssues and TargetOnlyIssues list the issues in only one of the projects, if
+	// orphans are reported
+	SourceOnlyIssues []string `json:"source_only_issues,omitempty"`
+	TargetOnlyIssues []string `json:"target_only_issues,omitempty"`
+	// UnprocessedIssues lists the issues left unprocessed as the API call budget was
+	// exhausted, to resume the sync with in a later run
+	UnprocessedIssues []string `json:"unprocessed_issues,omitempty"`
+	// Targets summarizes each target project when syncing to several target projects, in
+	// which case the other counters are the totals of all target projects
+	Targets []TargetSummary `json:"targets,omitempty"`
+}
+
+// TargetSummary summarizes the sync of one of several target projects
+type TargetSummary struct {
+	TargetProject string `json:"target_project"`
+	// Error is the error that ended the sync of the target project, if any
+	Error string `json:"error,omitempty"`
+	Summary
+}
+
+// TargetResult is the outcome of syncing one of several target projects
+type TargetResult struct {
+	TargetProjectURL string
+	Result           Result
+	// Err is the error that ended the sync of the target project, if any
+	Err error
+}
+
+// IssueError describes an issue that failed to sync
+type IssueError struct {
+	IssueURL string `json:"issue_url"`
+	Title    string `json:"title"`
+	Error    string `json:"error"`
+}
+
+// FieldChange describes a single field value written to the target project
+type FieldChange struct {
+	IssueURL string `json:"issue_url"`
+	Title    string `json:"title"`
+	Field    string `json:"field"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+func NewService(client client.Client, opts Options) *Service {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	labelMatch := opts.LabelMatch
+	if labelMatch == "" {
+		labelMatch = LabelMatchAll
+	}
+
+	issueState := opts.IssueState
+	if issueState == "" {
+		issueState = IssueStateAll
+	}
+
 	return &Service{
-		client: client,
-		dryRun: dryRun,
+		client:        client,
+		dryRun:        opts.DryRun || opts.Preview,
+		preview:       opts.Preview,
+		concurrency:   concurrency,
+		failFast:      opts.FailFast,
+		allowSame:     opts.AllowSameProject,
+		prune:         opts.PruneTargetItems,
+		createOptions: opts.CreateMissingOptions,
+		lenient:       opts.LenientMappings,
+		repos:         opts.Repos,
+		filterLabels:  opts.FilterLabels,
+		labelMatch:    labelMatch,
+		issueFilter:   opts.IssueFilter,
+		issueState:    issueState,
+		since:         opts.Since,
+		maxIssues:     opts.MaxIssues,
+		normalize:     opts.NormalizeSelect,
+		requireValue:  opts.RequireSourceValue,
+		addMissing:    opts.AddMissingToTarget,
+		createFields:  opts.CreateMissingFields,
+		onlyFillEmpty: opts.OnlyFillEmpty,
+		reportOrphans: opts.ReportOrphans || opts.FailOnOrphans,
+		failOnOrphans: opts.FailOnOrphans,
+
+		progressBar:      opts.ProgressBar,
+		progressInterval: opts.ProgressInterval,
+	}
+}
+
+// Result returns the outcome of the last sync run
+func (s *Service) Result() Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.result
+}
+
+// HasChanges reports whether the sync changed any field, created any field or added or removed any item, or
+// would have in dry run mode
+func (r Result) HasChanges() bool {
+	return len(r.Changes) > 0 || len(r.PrunedIssues) > 0 || len(r.AddedIssues) > 0 || len(r.CreatedFields) > 0
+}
+
+// Summary summarizes the last sync run. In dry run mode, updated fields are the planned updates.
+// After syncing to several target projects, the counters are summed up over all of them.
+func (s *Service) Summary() Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.targets) == 0 {
+		summary := s.summarize(s.result)
+		summary.QueryCost = s.client.QueryCost()
+		return summary
+	}
+
+	total := Summary{DryRun: s.dryRun, Errors: []IssueError{}, QueryCost: s.client.QueryCost()}
+	for _, target := range s.targets {
+		summary := s.summarize(target.Result)
+		total.IssuesProcessed += summary.IssuesProcessed
+		total.FieldsUpdated += summary.FieldsUpdated
+		total.FieldsSkipped += summary.FieldsSkipped
+		total.FieldsKept += summary.FieldsKept
+		total.FieldsCleared += summary.FieldsCleared
+		total.DuplicateIssues += summary.DuplicateIssues
+		total.FilteredByRepo += summary.FilteredByRepo
+		total.FilteredByState += summary.FilteredByState
+		total.Errors = append(total.Errors, summary.Errors...)
+		total.SourceOnlyIssues = append(total.SourceOnlyIssues, summary.SourceOnlyIssues...)
+		total.TargetOnlyIssues = append(total.TargetOnlyIssues, summary.TargetOnlyIssues...)
+		total.UnprocessedIssues = append(total.UnprocessedIssues, summary.UnprocessedIssues...)
+
+		targetSummary := TargetSummary{TargetProject: target.TargetProjectURL, Summary: summary}
+		if target.Err != nil {
+			targetSummary.Error = target.Err.Error()
+		}
+		total.Targets = append(total.Targets, targetSummary)
+	}
+	return total
+}
+
+// summarize summarizes the result of syncing one target project
+func (s *Service) summarize(result Result) Summary {
+	errs := result.Errors
+	if errs == nil {
+		errs = []IssueError{}
+	}
+	return Summary{
+		DryRun:          s.dryRun,
+		IssuesProcessed: result.IssuesProcessed,
+		FieldsUpdated:   len(result.Changes),
+		FieldsSkipped:   result.FieldsSkipped,
+		FieldsKept:      result.FieldsKept,
+		FieldsCleared:   result.FieldsCleared,
+		DuplicateIssues: result.DuplicateIssues,
+		FilteredByRepo:  result.IssuesFilteredByRepo,
+		FilteredByState: result.IssuesFilteredByState,
+		Errors:          errs,
+
+		SourceOnlyIssues:  result.SourceOnlyIssues,
+		TargetOnlyIssues:  result.TargetOnlyIssues,
+		UnprocessedIssues: result.UnprocessedIssues,
+	}
+}
+
+// TargetResults returns the outcome per target project of the last SyncFieldsToTargets run
+func (s *Service) TargetResults() []TargetResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.targets
+}
+
+// recordIssueProcessed counts an issue the field mappings were applied to
+func (s *Service) recordIssueProcessed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result.IssuesProcessed++
+}
+
+// recordFieldSkipped adds a target field that already had the source value to the result
+func (s *Service) recordFieldSkipped(field FieldChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result.FieldsSkipped++
+	s.result.Unchanged = append(s.result.Unchanged, field)
+}
+
+// recordFieldKept adds a target field whose value was kept as only empty fields are filled
+// to the result
+func (s *Service) recordFieldKept(field FieldChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result.FieldsKept++
+	s.result.Kept = append(s.result.Kept, field)
+}
+
+// recordChange adds a field change to the result, counting changes that remove the value
+// of the target field as cleared
+func (s *Service) recordChange(change FieldChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result.Changes = append(s.result.Changes, change)
+	if change.OldValue != "" && change.NewValue == "" {
+		s.result.FieldsCleared++
+	}
+}
+
+// recordError adds a failed issue to the result
+func (s *Service) recordError(issueURL, title string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result.Errors = append(s.result.Errors, IssueError{
+		IssueURL: issueURL,
+		Title:    title,
+		Error:    err.Error(),
+	})
+}
+
+// recordIssueWithoutSourceValues adds an issue without mapped source values to the result
+func (s *Service) recordIssueWithoutSourceValues(issueURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result.IssuesWithoutSourceValues = append(s.result.IssuesWithoutSourceValues, issueURL)
+}
+
+// SyncFieldsToTargets syncs the fields of one source project to each of the target projects
+// in turn. The client reuses the loaded source project, so it is only read once. A failed
+// target project does not stop the sync of the others, unless FailFast is set.
+func (s *Service) SyncFieldsToTargets(ctx context.Context, sourceProjectURL string, targetProjectURLs []string, issues []string, fieldMappings []string) error {
+	s.mu.Lock()
+	s.targets = nil
+	s.mu.Unlock()
+
+	if len(targetProjectURLs) == 1 {
+		return s.SyncFields(ctx, sourceProjectURL, targetProjectURLs[0], issues, fieldMappings)
+	}
+
+	var failed int
+	for i, targetProjectURL := range targetProjectURLs {
+		slog.Info("syncing target project",
+			"target_project", targetProjectURL,
+			"target", i+1,
+			"targets", len(targetProjectURLs),
+		)
+
+		result, err := s.SyncFieldsResult(ctx, sourceProjectURL, targetProjectURL, issues, fieldMappings)
+		s.mu.Lock()
+		s.targets = append(s.targets, TargetResult{
+			TargetProjectURL: targetProjectURL,
+			Result:           result,
+			Err:              err,
+		})
+		s.mu.Unlock()
+
+		if err == nil {
+			continue
+		}
+		// Without API calls left, the remaining target projects would fail as well
+		if s.failFast || ctx.Err() != nil || errors.Is(err, client.ErrAPIBudgetExhausted) {
+			return fmt.Errorf("failed to sync target project %s: %w", targetProjectURL, err)
+		}
+		failed++
+		slog.Error("failed to sync target project, continuing with the next one",
+			"target_project", targetProjectURL,
+			"error", err,
+		)
 	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to sync %d of %d target projects", failed, len(targetProjectURLs))
+	}
+	return nil
 }
 
+// SyncFieldsResult syncs like SyncFields and returns the outcome of the run along with its
+// error, so that the issues processed and fields updated, skipped and cleared are available
+// even if the sync failed partway
+func (s *Service) SyncFieldsResult(ctx context.Context, sourceProjectURL, targetProjectURL string, issues []string, fieldMappings []string) (Result, error) {
+	err := s.SyncFields(ctx, sourceProjectURL, targetProjectURL, issues, fieldMappings)
+	return s.Result(), err
+}
+
+// SyncFields syncs the mapped fields of the given issues, or of the issues of both projects,
+// from the source to the target project. Use Result or Summary for the outcome of the run.
 func (s *Service) SyncFields(ctx context.Context, sourceProjectURL, targetProjectURL string, issues []string, fieldMappings []string) error {
-	// Parse project URLs and field mappings
-	sourceProject, targetProject, mappings, err := s.parseInputs(sourceProjectURL, targetProjectURL, fieldMappings)
+	s.mu.Lock()
+	s.result = Result{}
+	s.titles = nil
+	s.mu.Unlock()
+
+	if err := s.validateOptions(); err != nil {
+		return err
+	}
+
+	plan, err := s.loadPlan(ctx, sourceProjectURL, targetProjectURL, fieldMappings)
 	if err != nil {
 		return err
 	}
 
-	// Get project IDs
-	sourceProjectID, targetProjectID, err := s.getProjectIDs(ctx, sourceProject, targetProject)
+	issues, err = s.selectIssues(ctx, plan, issues)
 	if err != nil {
 		return err
 	}
 
-	// Get field configurations and issues
-	sourceFieldConfigs, targetFieldConfigs, sourceIssues, targetIssues, err := s.client.GetProjectFieldConfigsAndIssues(ctx, sourceProjectID, targetProjectID)
+	// Report all source values without a matching target option before anything is written.
+	// A preview lists such values as changes instead, as it writes nothing. Values of reverse
+	// mappings without a matching option fail their issue instead.
+	if !s.createOptions && !s.preview {
+		if err := s.preflightOptions(ctx, plan.sourceProjectID, issues, plan.sourceFieldConfigs, plan.targetFieldConfigs, plan.forward); err != nil {
+			return err
+		}
+	}
+
+	if issues, err = s.prepareTarget(ctx, plan, issues); err != nil {
+		return err
+	}
+
+	// Dry runs produce a report, so look up the titles of all issues up front, including
+	// those not in the project cache
+	if s.dryRun {
+		s.prefetchTitles(ctx, issues)
+	}
+
+	if err := s.syncIssues(ctx, plan, issues); err != nil {
+		return err
+	}
+	return s.finishSync(ctx, plan)
+}
+
+// syncPlan is what a sync run loaded about its projects: their IDs, field configurations
+// and issues, and the field mappings resolved against them
+type syncPlan struct {
+	sourceProjectID    string
+	targetProjectID    string
+	sourceFieldConfigs []github.ProjectFieldConfig
+	targetFieldConfigs []github.ProjectFieldConfig
+	sourceIssues       []string
+	targetIssues       []string
+	// missingFields holds the target fields to create, which are part of targetFieldConfigs
+	// without an ID until they are created
+	missingFields []github.ProjectFieldConfig
+	// parsed holds the mappings as given, mappings all mappings to apply resolved against the
+	// fields of the projects and forward those writing to the target project
+	parsed   []FieldMapping
+	mappings []FieldMapping
+	forward  []FieldMapping
+	// orphans is the number of issues in only one of the projects, if reported
+	orphans int
+}
+
+// validateOptions checks the options of the service before anything is loaded
+func (s *Service) validateOptions() error {
+	if err := validateLabelMatch(s.labelMatch); err != nil {
+		return err
+	}
+	if err := validateRepos(s.repos); err != nil {
+		return err
+	}
+	if err := validateIssueState(s.issueState); err != nil {
+		return err
+	}
+	// The source issues of a server filter are not all issues of the source project, so
+	// pruning would remove the target items of the issues outside the filter
+	if s.prune && s.client.ServerFilter() != "" {
+		return fmt.Errorf("pruning target items cannot be combined with a server filter, as it would remove the items of all issues outside the filter")
+	}
+	return nil
+}
+
+// loadPlan resolves the projects of a sync run, loads their fields and issues and resolves
+// the field mappings against them
+func (s *Service) loadPlan(ctx context.Context, sourceProjectURL, targetProjectURL string, fieldMappings []string) (*syncPlan, error) {
+	sourceProject, targetProject, mappings, err := s.parseInputs(sourceProjectURL, targetProjectURL, fieldMappings)
 	if err != nil {
-		return fmt.Errorf("failed to get project field configs and issues: %w", err)
+		return nil, err
 	}
 
-	// If no issues were provided, find common issues
+	plan := &syncPlan{}
+	plan.sourceProjectID, plan.targetProjectID, err = s.getProjectIDs(ctx, sourceProject, targetProject)
+	if err != nil {
+		return nil, err
+	}
+	if plan.sourceProjectID == plan.targetProjectID {
+		if err := s.checkSameProject(plan.sourceProjectID, sourceProjectURL, targetProjectURL, mappings); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	s.result.SourceProjectID = plan.sourceProjectID
+	s.result.TargetProjectID = plan.targetProjectID
+	s.mu.Unlock()
+
+	plan.sourceFieldConfigs, plan.targetFieldConfigs, plan.sourceIssues, plan.targetIssues, err = s.client.GetProjectFieldConfigsAndIssues(ctx, plan.sourceProjectID, plan.targetProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project field configs and issues: %w", err)
+	}
+
+	duplicates := s.client.DuplicateIssueCount()
+	s.mu.Lock()
+	s.result.DuplicateIssues = duplicates
+	s.mu.Unlock()
+
+	if s.reportOrphans {
+		plan.orphans = s.reportOrphanIssues(plan.sourceIssues, plan.targetIssues)
+	}
+
+	// Missing fields are only created once the preflight passed, so the mappings are resolved
+	// against the fields as they will be created until then
+	if s.createFields {
+		if plan.missingFields, err = findMissingFields(mappings, plan.sourceFieldConfigs, plan.targetFieldConfigs); err != nil {
+			return nil, err
+		}
+		plan.targetFieldConfigs = append(plan.targetFieldConfigs, plan.missingFields...)
+	}
+
+	plan.parsed = mappings
+	if err := s.resolveMappings(plan, mappings); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// checkSameProject checks whether fields may be copied within a project, which is the source
+// and the target of a sync run
+func (s *Service) checkSameProject(projectID, sourceProjectURL, targetProjectURL string, mappings []FieldMapping) error {
+	if !s.allowSame {
+		return fmt.Errorf("source and target are the same project (%s), use --allow-same-project to copy fields within a project", projectID)
+	}
+	slog.Warn("source and target are the same project, copying fields within it",
+		"project_id", projectID,
+		"source_project", sourceProjectURL,
+		"target_project", targetProjectURL,
+	)
+	for _, mapping := range mappings {
+		if mapping.SourceField == mapping.TargetField {
+			return fmt.Errorf("field mapping %s=%s copies a field onto itself within the same project", mapping.SourceField, mapping.TargetField)
+		}
+	}
+	return nil
+}
+
+// resolveMappings validates the field mappings against the fields of both projects and
+// resolves their target fields and options
+func (s *Service) resolveMappings(plan *syncPlan, mappings []FieldMapping) error {
+	if err := validateMappingFields(mappings, plan.sourceFieldConfigs, plan.targetFieldConfigs); err != nil {
+		if !s.lenient {
+			return err
+		}
+		slog.Warn("continuing despite invalid field mappings", "error", err)
+	}
+
+	if err := validateDateOffsets(mappings, plan.sourceFieldConfigs, plan.targetFieldConfigs); err != nil {
+		return err
+	}
+	if err := validateFieldTypes(mappings, plan.targetFieldConfigs); err != nil {
+		return err
+	}
+	forward, reverse := splitByDirection(mappings)
+	forward = resolveTargetFieldIDs(forward, plan.targetFieldConfigs)
+	forward, err := resolveTargetOptions(forward, plan.targetFieldConfigs)
+	if err != nil {
+		return err
+	}
+	forward = resolveLabelMappings(forward, plan.sourceFieldConfigs, plan.targetFieldConfigs)
+
+	// Reverse mappings write to the source project, so their fields are swapped and resolved
+	// against the source project
+	for i, mapping := range reverse {
+		reverse[i] = mapping.reversed()
+	}
+	reverse = resolveTargetFieldIDs(reverse, plan.sourceFieldConfigs)
+	if reverse, err = resolveTargetOptions(reverse, plan.sourceFieldConfigs); err != nil {
+		return err
+	}
+	reverse = resolveLabelMappings(reverse, plan.targetFieldConfigs, plan.sourceFieldConfigs)
+
+	plan.forward = forward
+	plan.mappings = append(forward, reverse...)
+	return nil
+}
+
+// selectIssues returns the issues to sync: the given issues, or else the issues of both
+// projects, narrowed down by the issue filters and capped by the maximum number of issues
+func (s *Service) selectIssues(ctx context.Context, plan *syncPlan, issues []string) ([]string, error) {
+	var err error
 	if len(issues) == 0 {
-		issues = findCommonIssues(sourceIssues, targetIssues)
-		if len(issues) == 0 {
-			return fmt.Errorf("no common issues found between source and target projects")
+		if issues, err = s.findIssuesToSync(ctx, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	if issues, err = s.filterIssues(ctx, issues); err != nil {
+		return nil, err
+	}
+
+	if s.maxIssues > 0 && len(issues) > s.maxIssues {
+		slog.Warn("capped the number of issues to sync",
+			"max_issues", s.maxIssues,
+			"count", len(issues),
+			"unprocessed", len(issues)-s.maxIssues,
+		)
+		issues = issues[:s.maxIssues]
+	}
+	return issues, nil
+}
+
+// prepareTarget creates the target fields missing in the target project and adds the issues
+// missing in it, if enabled, and records the issues to sync. It runs after the preflight, so
+// that nothing is written if the preflight fails.
+func (s *Service) prepareTarget(ctx context.Context, plan *syncPlan, issues []string) ([]string, error) {
+	if err := s.createMissingFields(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	if s.addMissing {
+		// Only issues of the source project are added, issues given explicitly may be in neither
+		missing := findTargetOnlyIssues(plan.targetIssues, findCommonIssues(issues, plan.sourceIssues))
+		var err error
+		if issues, err = s.addMissingIssues(ctx, plan.targetProjectID, issues, missing); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	s.result.Issues = issues
+	s.mu.Unlock()
+	return issues, nil
+}
+
+// findIssuesToSync finds the issues of both projects, or the issues of the source project
+// if missing issues are added to the target project
+func (s *Service) findIssuesToSync(ctx context.Context, plan *syncPlan) ([]string, error) {
+	candidates := plan.sourceIssues
+	if s.issueFilter != "" {
+		var err error
+		candidates, err = s.client.GetProjectIssuesFiltered(ctx, plan.sourceProjectID, s.issueFilter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter source issues: %w", err)
+		}
+		slog.Info("filtered source issues",
+			"filter", s.issueFilter,
+			"count", len(candidates),
+			"skipped", len(plan.sourceIssues)-len(candidates),
+		)
+	}
+
+	issues := findCommonIssues(candidates, plan.targetIssues)
+	if s.addMissing {
+		// Issues missing in the target project are added after filtering
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("no issues found in source project")
 		}
 		slog.Info("found common issues",
 			"count", len(issues),
-			"source_issues", len(sourceIssues),
-			"target_issues", len(targetIssues),
+			"missing_in_target", len(candidates)-len(issues),
 		)
+		return candidates, nil
 	}
 
-	return s.processBatches(ctx, sourceProjectID, targetProjectID, issues, sourceFieldConfigs, targetFieldConfigs, mappings)
+	if len(issues) == 0 {
+		return nil, fmt.Errorf("no common issues found between source and target projects")
+	}
+	slog.Info("found common issues",
+		"count", len(issues),
+		"source_issues", len(plan.sourceIssues),
+		"target_issues", len(plan.targetIssues),
+	)
+	return issues, nil
+}
+
+// filterIssues narrows down the issues to sync by repository, state, labels and update time
+func (s *Service) filterIssues(ctx context.Context, issues []string) ([]string, error) {
+	var err error
+	if len(s.repos) > 0 {
+		count := len(issues)
+		issues = filterIssuesByRepos(issues, s.repos)
+		s.mu.Lock()
+		s.result.IssuesFilteredByRepo = count - len(issues)
+		s.mu.Unlock()
+		if len(issues) == 0 {
+			return nil, fmt.Errorf("no issues of the repositories %s found", strings.Join(s.repos, ", "))
+		}
+		slog.Info("filtered issues by repository",
+			"repos", s.repos,
+			"count", len(issues),
+			"skipped", count-len(issues),
+		)
+	}
+
+	if s.issueState != IssueStateAll {
+		count := len(issues)
+		if issues, err = s.filterIssuesByState(ctx, issues); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.result.IssuesFilteredByState = count - len(issues)
+		s.mu.Unlock()
+		if len(issues) == 0 {
+			return nil, fmt.Errorf("no %s issues found", s.issueState)
+		}
+		slog.Info("filtered issues by state",
+			"state", s.issueState,
+			"count", len(issues),
+			"skipped", count-len(issues),
+		)
+	}
+
+	if len(s.filterLabels) > 0 {
+		count := len(issues)
+		if issues, err = s.filterIssuesByLabels(ctx, issues); err != nil {
+			return nil, err
+		}
+		if len(issues) == 0 {
+			return nil, fmt.Errorf("no issues with %s of the labels %s found", s.labelMatch, strings.Join(s.filterLabels, ", "))
+		}
+		slog.Info("filtered issues by labels",
+			"labels", s.filterLabels,
+			"match", s.labelMatch,
+			"count", len(issues),
+			"skipped", count-len(issues),
+		)
+	}
+
+	if !s.since.IsZero() {
+		count := len(issues)
+		if issues, err = s.filterIssuesBySince(ctx, issues); err != nil {
+			return nil, err
+		}
+		slog.Info("filtered issues by update time",
+			"since", s.since.Format(time.RFC3339),
+			"count", len(issues),
+			"skipped", count-len(issues),
+		)
+		if len(issues) == 0 {
+			slog.Info("no issues were updated since", "since", s.since.Format(time.RFC3339))
+		}
+	}
+	return issues, nil
+}
+
+// syncIssues applies the field mappings to the issues in batches and reports the progress
+func (s *Service) syncIssues(ctx context.Context, plan *syncPlan, issues []string) error {
+	progress := newProgressReporter(len(issues), s.progressBar, s.progressInterval)
+	err := s.processBatches(ctx, plan.sourceProjectID, plan.targetProjectID, issues, plan.sourceFieldConfigs, plan.targetFieldConfigs, plan.mappings, progress)
+	progress.finish()
+
+	// Issues are processed in parallel, so restore the issue order for reporting
+	s.mu.Lock()
+	sortByIssueOrder(s.result.IssuesWithoutSourceValues, issues, func(issueURL string) string { return issueURL })
+	sortByIssueOrder(s.result.Changes, issues, func(change FieldChange) string { return change.IssueURL })
+	sortByIssueOrder(s.result.Unchanged, issues, func(change FieldChange) string { return change.IssueURL })
+	sortByIssueOrder(s.result.Kept, issues, func(change FieldChange) string { return change.IssueURL })
+	sortByIssueOrder(s.result.Errors, issues, func(issueErr IssueError) string { return issueErr.IssueURL })
+	s.mu.Unlock()
+	return err
+}
+
+// finishSync prunes the target project if enabled and reports issues without source values
+// and issues in only one of the projects once all issues are synced
+func (s *Service) finishSync(ctx context.Context, plan *syncPlan) error {
+	if s.prune {
+		if err := s.pruneTargetItems(ctx, plan.targetProjectID, findTargetOnlyIssues(plan.sourceIssues, plan.targetIssues)); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	withoutSourceValues := s.result.IssuesWithoutSourceValues
+	s.mu.Unlock()
+	if len(withoutSourceValues) > 0 {
+		slog.Info("some issues had no values in any mapped source field",
+			"count", len(withoutSourceValues),
+		)
+		slog.Debug("issues without mapped source values", "issues", withoutSourceValues)
+	}
+
+	if s.failOnOrphans && plan.orphans > 0 {
+		return fmt.Errorf("%d issues are in only one of the projects", plan.orphans)
+	}
+	return nil
+}
+
+// reportOrphanIssues logs the issues in only one of the projects and adds them to the
+// result. It returns their number. With a server filter, the source issues are only those
+// matching the filter, so target issues missing in the source project are not reported.
+func (s *Service) reportOrphanIssues(sourceIssues, targetIssues []string) int {
+	sourceOnly := findTargetOnlyIssues(targetIssues, sourceIssues)
+	var targetOnly []string
+	if filter := s.client.ServerFilter(); filter != "" {
+		slog.Info("not reporting target issues missing in the source project, as source items are filtered", "server_filter", filter)
+	} else {
+		targetOnly = findTargetOnlyIssues(sourceIssues, targetIssues)
+	}
+
+	s.mu.Lock()
+	s.result.SourceOnlyIssues = sourceOnly
+	s.result.TargetOnlyIssues = targetOnly
+	s.mu.Unlock()
+
+	for _, issueURL := range sourceOnly {
+		slog.Warn("issue is missing in the target project", "issue", issueURL)
+	}
+	for _, issueURL := range targetOnly {
+		slog.Warn("issue is missing in the source project", "issue", issueURL)
+	}
+	if len(sourceOnly) > 0 || len(targetOnly) > 0 {
+		slog.Info("found issues in only one of the projects",
+			"source_only", len(sourceOnly),
+			"target_only", len(targetOnly),
+		)
+	}
+	return len(sourceOnly) + len(targetOnly)
+}
+
+// sortByIssueOrder stably sorts items by the position of their issue in issues
+func sortByIssueOrder[T any](items []T, issues []string, issueOf func(T) string) {
+	position := make(map[string]int, len(issues))
+	for i, issueURL := range issues {
+		position[issueURL] = i
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return position[issueOf(items[i])] < position[issueOf(items[j])]
+	})
 }
 
 // parseInputs parses and validates the input URLs and field mappings
 func (s *Service) parseInputs(sourceProjectURL, targetProjectURL string, fieldMappings []string) (*github.ProjectInfo, *github.ProjectInfo, []FieldMapping, error) {
-	sourceProject, err := util.ParseProjectURL(sourceProjectURL)
+	sourceProject, err := util.ParseProjectURL(sourceProjectURL, s.client.Host())
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("invalid source project URL: %w", err)
 	}
 
-	targetProject, err := util.ParseProjectURL(targetProjectURL)
+	targetProject, err := util.ParseProjectURL(targetProjectURL, s.client.Host())
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("invalid target project URL: %w", err)
 	}
@@ -79,21 +932,33 @@ func (s *Service) parseInputs(sourceProjectURL, targetProjectURL string, fieldMa
 
 // getProjectIDs retrieves the project IDs for both source and target projects
 func (s *Service) getProjectIDs(ctx context.Context, sourceProject, targetProject *github.ProjectInfo) (string, string, error) {
-	sourceProjectID, err := s.client.GetProjectID(ctx, sourceProject)
+	sourceProjectID, targetProjectID, err := s.client.GetProjectIDs(ctx, sourceProject, targetProject)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get source project ID: %w", err)
+		return "", "", fmt.Errorf("failed to get project IDs: %w", err)
 	}
+	return sourceProjectID, targetProjectID, nil
+}
 
-	targetProjectID, err := s.client.GetProjectID(ctx, targetProject)
+// prefetchTitles looks up the titles of all issues with batched queries, so that every issue
+// of the report has a title. Failures only leave titles out.
+func (s *Service) prefetchTitles(ctx context.Context, issues []string) {
+	titles, err := s.client.GetIssueTitles(ctx, issues)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get target project ID: %w", err)
+		slog.Warn("failed to prefetch issue titles", "error", err)
+		return
+	}
+	if unresolved := len(issues) - len(titles); unresolved > 0 {
+		slog.Debug("some issue titles could not be resolved", "count", unresolved)
 	}
 
-	return sourceProjectID, targetProjectID, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.titles = titles
 }
 
 // processBatches processes issues in batches to avoid too many concurrent requests
-func (s *Service) processBatches(ctx context.Context, sourceProjectID, targetProjectID string, issues []string, sourceFieldConfigs, targetFieldConfigs []github.ProjectFieldConfig, mappings []FieldMapping) error {
+func (s *Service) processBatches(ctx context.Context, sourceProjectID, targetProjectID string, issues []string, sourceFieldConfigs, targetFieldConfigs []github.ProjectFieldConfig, mappings []FieldMapping, progress *progressReporter) error {
+	var failures []error
 	batchSize := 10
 	for i := 0; i < len(issues); i += batchSize {
 		end := i + batchSize
@@ -103,50 +968,245 @@ func (s *Service) processBatches(ctx context.Context, sourceProjectID, targetPro
 		batch := issues[i:end]
 
 		// Get field values for all issues in the batch from both projects
-		sourceValues, targetValues, err := s.getFieldValuesForBatch(ctx, sourceProjectID, targetProjectID, batch, sourceFieldConfigs, targetFieldConfigs)
+		sourceValues, targetValues, missing, err := s.getFieldValuesForBatch(ctx, sourceProjectID, targetProjectID, batch, sourceFieldConfigs, targetFieldConfigs)
+		if errors.Is(err, client.ErrAPIBudgetExhausted) {
+			return s.stopOnExhaustedBudget(issues[i:], len(issues))
+		}
 		if err != nil {
 			return err
 		}
+		if len(missing) > 0 {
+			failures = append(failures, missing...)
+			batch = slices.DeleteFunc(slices.Clone(batch), func(issueURL string) bool {
+				_, ok := targetValues[issueURL]
+				return !ok
+			})
+		}
 
-		// Process all issues in the batch
-		for _, issueURL := range batch {
-			sourceFields := sourceValues[issueURL]
-			targetFields := targetValues[issueURL]
-
-			// Get issue title for logging
-			title, err := s.client.GetIssueTitle(ctx, issueURL)
-			if err != nil {
-				slog.Warn("failed to get issue title", "issue", issueURL, "error", err)
-				title = "<unknown>"
+		// Prefetch the titles of the batch, so that they are not looked up one by one. Dry
+		// runs have prefetched all titles already.
+		if !s.dryRun {
+			if _, err := s.client.GetIssueTitles(ctx, batch); err != nil {
+				slog.Warn("failed to prefetch issue titles", "error", err)
 			}
-			slog.Info("processing issue", "url", issueURL, "title", title)
+		}
 
-			// Create a map of target fields by name for easy lookup
-			targetFieldMap := make(map[string]github.ProjectField)
-			for _, field := range targetFields {
-				targetFieldMap[field.Name] = field
+		// Process all issues in the batch in parallel, keeping track of the issues that were
+		// completed before the API call budget was exhausted
+		var completedMu sync.Mutex
+		completed := make(map[string]bool, len(batch))
+		err = s.forEachIssue(ctx, batch, func(ctx context.Context, issueURL string) error {
+			defer progress.issueProcessed()
+			err := s.processIssue(ctx, sourceProjectID, targetProjectID, issueURL, sourceValues[issueURL], targetValues[issueURL], mappings)
+			if !errors.Is(err, client.ErrAPIBudgetExhausted) {
+				completedMu.Lock()
+				completed[issueURL] = true
+				completedMu.Unlock()
 			}
-
-			// Apply field mappings
-			if err := s.applyFieldMappings(ctx, targetProjectID, issueURL, sourceFields, targetFieldMap, mappings); err != nil {
+			return err
+		})
+		if errors.Is(err, client.ErrAPIBudgetExhausted) {
+			unprocessed := slices.DeleteFunc(slices.Clone(batch), func(issueURL string) bool {
+				return completed[issueURL]
+			})
+			return s.stopOnExhaustedBudget(append(unprocessed, issues[end:]...), len(issues))
+		}
+		if err != nil {
+			if s.failFast || ctx.Err() != nil {
 				return err
 			}
+			failures = append(failures, err)
 		}
 	}
 
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to sync %d of %d issues: %w", len(s.Result().Errors), len(issues), errors.Join(failures...))
+	}
+
 	return nil
 }
 
-// findCommonIssues finds common issues between two lists
+// stopOnExhaustedBudget records the issues left unprocessed once the API call budget of the
+// client is exhausted, so that a later run can resume with them, and returns the error that
+// ends the sync
+func (s *Service) stopOnExhaustedBudget(unprocessed []string, total int) error {
+	s.mu.Lock()
+	s.result.UnprocessedIssues = unprocessed
+	s.mu.Unlock()
+
+	slog.Warn("API call budget exhausted, stopping the sync",
+		"unprocessed", len(unprocessed),
+		"issues", total,
+	)
+	slog.Debug("unprocessed issues", "issues", unprocessed)
+	return fmt.Errorf("stopped with %d of %d issues unprocessed: %w", len(unprocessed), total, client.ErrAPIBudgetExhausted)
+}
+
+// forEachIssue calls fn for every issue using a bounded pool of workers. All errors are
+// returned joined, unless fail-fast is enabled, where the first error cancels the remaining work.
+func (s *Service) forEachIssue(ctx context.Context, issues []string, fn func(ctx context.Context, issueURL string) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	work := make(chan string)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	for i := 0; i < s.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for issueURL := range work {
+				// Skip the remaining issues once the work has been canceled
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := fn(ctx, issueURL); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					if s.failFast {
+						cancel()
+					}
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, issueURL := range issues {
+		select {
+		case work <- issueURL:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	switch {
+	case len(errs) == 0:
+		return ctx.Err()
+	case s.failFast:
+		return errs[0]
+	default:
+		return errors.Join(errs...)
+	}
+}
+
+// processIssue applies the field mappings to a single issue. Forward mappings update the
+// target project, and reverse mappings, whose fields are swapped, update the source project.
+func (s *Service) processIssue(ctx context.Context, sourceProjectID, targetProjectID string, issueURL string, sourceFields, targetFields []github.ProjectField, mappings []FieldMapping) error {
+	// Get issue title for logging, falling back to the URL
+	title, err := s.client.GetIssueTitle(ctx, issueURL)
+	if err != nil {
+		slog.Warn("failed to get issue title", "issue", issueURL, "error", err)
+		title = issueURL
+	}
+	slog.Info("processing issue", "url", issueURL, "title", title)
+	s.recordIssueProcessed()
+
+	forward, reverse := splitByDirection(mappings)
+
+	if s.requireValue {
+		fields := append(missingSourceValues(sourceFields, forward), missingSourceValues(targetFields, reverse)...)
+		if len(fields) > 0 {
+			err := fmt.Errorf("no value in source field %s", strings.Join(fields, ", "))
+			slog.Error("failed to sync issue", "url", issueURL, "title", title, "error", err)
+			s.recordError(issueURL, title, err)
+			return err
+		}
+	}
+
+	if !hasMappedSourceValues(sourceFields, forward) && !hasMappedSourceValues(targetFields, reverse) {
+		slog.Debug("no values in any mapped source field, nothing to sync", "url", issueURL)
+		s.recordIssueWithoutSourceValues(issueURL)
+		return nil
+	}
+
+	// Apply field mappings in both directions
+	err = s.applyFieldMappings(ctx, targetProjectID, issueURL, title, sourceFields, fieldsByName(targetFields), forward)
+	if err == nil && len(reverse) > 0 {
+		err = s.applyFieldMappings(ctx, sourceProjectID, issueURL, title, targetFields, fieldsByName(sourceFields), reverse)
+	}
+	if err != nil {
+		// Issues stopped by the exhausted API call budget are reported as unprocessed instead
+		if !errors.Is(err, client.ErrAPIBudgetExhausted) {
+			slog.Error("failed to sync issue", "url", issueURL, "title", title, "error", err)
+			s.recordError(issueURL, title, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// fieldsByName maps fields by their name for easy lookup
+func fieldsByName(fields []github.ProjectField) map[string]github.ProjectField {
+	byName := make(map[string]github.ProjectField, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+	return byName
+}
+
+// hasMappedSourceValues checks if any mapped source field has a value
+func hasMappedSourceValues(sourceFields []github.ProjectField, mappings []FieldMapping) bool {
+	for _, mapping := range mappings {
+		for _, sourceField := range sourceFields {
+			if sourceField.Name == mapping.SourceField && !sourceField.Value.IsEmpty() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasMappingToTarget reports whether any of the mappings writes to the target field
+func hasMappingToTarget(mappings []FieldMapping, targetField string) bool {
+	for _, mapping := range mappings {
+		if mapping.TargetField == targetField {
+			return true
+		}
+	}
+	return false
+}
+
+// missingSourceValues returns the mapped source fields without a value, each once. Source
+// fields of fallback mappings are only missing if no mapping to their target field has a value.
+func missingSourceValues(sourceFields []github.ProjectField, mappings []FieldMapping) []string {
+	filled := make(map[string]bool)
+	for _, mapping := range mappings {
+		if hasMappedSourceValues(sourceFields, []FieldMapping{mapping}) {
+			filled[mapping.TargetField] = true
+		}
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, mapping := range mappings {
+		if seen[mapping.SourceField] || filled[mapping.TargetField] {
+			continue
+		}
+		seen[mapping.SourceField] = true
+		if !hasMappedSourceValues(sourceFields, []FieldMapping{mapping}) {
+			missing = append(missing, mapping.SourceField)
+		}
+	}
+	return missing
+}
+
+// findCommonIssues finds common issues between two lists, comparing canonical issue URLs so
+// that variants of the URL of an issue match. The URLs of the source list are returned.
 func findCommonIssues(sourceIssues, targetIssues []string) []string {
 	issueMap := make(map[string]bool)
 	for _, issue := range targetIssues {
-		issueMap[issue] = true
+		issueMap[util.CanonicalizeIssueURL(issue)] = true
 	}
 
 	var commonIssues []string
 	for _, issue := range sourceIssues {
-		if issueMap[issue] {
+		if issueMap[util.CanonicalizeIssueURL(issue)] {
 			commonIssues = append(commonIssues, issue)
 		}
 	}
@@ -154,66 +1214,411 @@ func findCommonIssues(sourceIssues, targetIssues []string) []string {
 	return commonIssues
 }
 
-// getFieldValuesForBatch retrieves field values for a batch of issues from both projects
-func (s *Service) getFieldValuesForBatch(ctx context.Context, sourceProjectID string, targetProjectID string, batch []string, sourceFieldConfigs []github.ProjectFieldConfig, targetFieldConfigs []github.ProjectFieldConfig) (map[string][]github.ProjectField, map[string][]github.ProjectField, error) {
-	sourceValues := make(map[string][]github.ProjectField)
-	targetValues := make(map[string][]github.ProjectField)
+// findTargetOnlyIssues finds the issues of the target project that are not in the source project,
+// comparing canonical issue URLs. With the arguments swapped, it finds the source-only issues.
+func findTargetOnlyIssues(sourceIssues, targetIssues []string) []string {
+	issueMap := make(map[string]bool)
+	for _, issue := range sourceIssues {
+		issueMap[util.CanonicalizeIssueURL(issue)] = true
+	}
 
-	for _, issueURL := range batch {
-		// Get source values using cached data
-		sourceFields, err := s.client.GetProjectFieldValues(ctx, sourceProjectID, issueURL, sourceFieldConfigs)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get source field values for %s: %w", issueURL, err)
+	var targetOnlyIssues []string
+	for _, issue := range targetIssues {
+		if !issueMap[util.CanonicalizeIssueURL(issue)] {
+			targetOnlyIssues = append(targetOnlyIssues, issue)
+		}
+	}
+
+	return targetOnlyIssues
+}
+
+// addMissingIssues adds the missing issues to the target project and returns the issues to
+// sync. Issues that failed to be added are recorded as failed and dropped, unless fail-fast is
+// enabled. In dry run mode, the missing issues are only looked up and recorded, and dropped as
+// they have no target item to sync. Draft issues cannot be added and are dropped as well.
+func (s *Service) addMissingIssues(ctx context.Context, targetProjectID string, issues, missing []string) ([]string, error) {
+	if len(missing) == 0 {
+		return issues, nil
+	}
+
+	slog.Info("adding issues missing in the target project",
+		"count", len(missing),
+		"dry_run", s.dryRun,
+	)
+
+	dropped := make(map[string]bool)
+	for _, issueURL := range missing {
+		// Draft issues only exist in their project, so there is no issue to add to another one
+		if client.IsDraftIssueURL(issueURL) {
+			slog.Warn("skipping draft issue missing in the target project", "url", issueURL)
+			dropped[issueURL] = true
+			continue
+		}
+		if _, err := s.client.AddIssueToProject(ctx, targetProjectID, issueURL, s.dryRun); err != nil {
+			err = fmt.Errorf("failed to add %s to target project: %w", issueURL, err)
+			if s.failFast {
+				return nil, err
+			}
+			slog.Error("failed to add issue to target project", "url", issueURL, "error", err)
+			s.recordError(issueURL, "", err)
+			dropped[issueURL] = true
+			continue
+		}
+
+		slog.Info("added issue to target project", "url", issueURL, "dry_run", s.dryRun)
+		s.mu.Lock()
+		s.result.AddedIssues = append(s.result.AddedIssues, issueURL)
+		s.mu.Unlock()
+		if s.dryRun {
+			dropped[issueURL] = true
+		}
+	}
+
+	return slices.DeleteFunc(slices.Clone(issues), func(issueURL string) bool {
+		return dropped[issueURL]
+	}), nil
+}
+
+// findMissingFields returns the target fields of forward mappings missing in the target
+// project, configured like their source fields but without IDs
+func findMissingFields(mappings []FieldMapping, sourceFieldConfigs, targetFieldConfigs []github.ProjectFieldConfig) ([]github.ProjectFieldConfig, error) {
+	var missing []github.ProjectFieldConfig
+	for _, mapping := range mappings {
+		if mapping.Direction != DirectionForward {
+			continue
+		}
+		if _, ok := findFieldConfig(targetFieldConfigs, mapping.TargetField); ok {
+			continue
+		}
+		if _, ok := findFieldConfig(missing, mapping.TargetField); ok {
+			continue
+		}
+		// Unknown source fields are reported by the validation of the mappings
+		source, ok := findFieldConfig(sourceFieldConfigs, mapping.SourceField)
+		if !ok {
+			continue
+		}
+		if mapping.FieldType != "" && mapping.FieldType != source.DataType {
+			return nil, fmt.Errorf("field mapping %s=%s declares type %s, but source field %q is of type %s",
+				mapping.SourceField, mapping.TargetField, mapping.FieldType, mapping.SourceField, source.DataType)
 		}
-		sourceValues[issueURL] = sourceFields
 
-		// Get target values using cached data
-		targetFields, err := s.client.GetProjectFieldValues(ctx, targetProjectID, issueURL, targetFieldConfigs)
+		// The options of the created field get new IDs
+		config := source
+		config.ID, config.Name, config.Options = "", mapping.TargetField, nil
+		for _, option := range source.Options {
+			config.Options = append(config.Options, github.ProjectFieldOption{Name: option.Name})
+		}
+		missing = append(missing, config)
+	}
+	return missing, nil
+}
+
+// createMissingFields creates the missing target fields of a plan and resolves its mappings
+// against them. In dry run mode, the fields are only recorded and the mappings to them are
+// dropped, as there are no fields to write to yet.
+func (s *Service) createMissingFields(ctx context.Context, plan *syncPlan) error {
+	if len(plan.missingFields) == 0 {
+		return nil
+	}
+
+	created := make(map[string]bool, len(plan.missingFields))
+	for _, cfg := range plan.missingFields {
+		slog.Info("creating target field missing in the target project",
+			"field", cfg.Name,
+			"data_type", cfg.DataType,
+			"dry_run", s.dryRun,
+		)
+		created[cfg.Name] = true
+		s.mu.Lock()
+		s.result.CreatedFields = append(s.result.CreatedFields, cfg.Name)
+		s.mu.Unlock()
+		if s.dryRun {
+			continue
+		}
+
+		config, err := s.client.CreateProjectField(ctx, plan.targetProjectID, cfg)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get target field values for %s: %w", issueURL, err)
+			return fmt.Errorf("failed to create target field %q: %w", cfg.Name, err)
+		}
+		i := slices.IndexFunc(plan.targetFieldConfigs, func(c github.ProjectFieldConfig) bool {
+			return c.ID == "" && c.Name == cfg.Name
+		})
+		plan.targetFieldConfigs[i] = config
+	}
+
+	if !s.dryRun {
+		return s.resolveMappings(plan, plan.parsed)
+	}
+	plan.targetFieldConfigs = slices.DeleteFunc(slices.Clone(plan.targetFieldConfigs), func(c github.ProjectFieldConfig) bool {
+		return c.ID == ""
+	})
+	toCreated := func(mapping FieldMapping) bool {
+		return mapping.Direction == DirectionForward && created[mapping.TargetField]
+	}
+	plan.forward = slices.DeleteFunc(slices.Clone(plan.forward), toCreated)
+	plan.mappings = slices.DeleteFunc(slices.Clone(plan.mappings), toCreated)
+	return nil
+}
+
+// findFieldConfig finds a field by its name
+func findFieldConfig(configs []github.ProjectFieldConfig, name string) (github.ProjectFieldConfig, bool) {
+	for _, config := range configs {
+		if config.Name == name {
+			return config, true
+		}
+	}
+	return github.ProjectFieldConfig{}, false
+}
+
+// pruneTargetItems removes the given issues from the target project. In dry run mode,
+// the issues are only recorded.
+func (s *Service) pruneTargetItems(ctx context.Context, targetProjectID string, issues []string) error {
+	if len(issues) == 0 {
+		slog.Info("no target items to prune")
+		return nil
+	}
+
+	slog.Warn("pruning target items whose issue is not in the source project",
+		"count", len(issues),
+		"dry_run", s.dryRun,
+	)
+
+	for _, issueURL := range issues {
+		slog.Info("pruning target item", "url", issueURL, "dry_run", s.dryRun)
+		if !s.dryRun {
+			if err := s.client.DeleteProjectItem(ctx, targetProjectID, issueURL); err != nil {
+				return fmt.Errorf("failed to prune %s from target project: %w", issueURL, err)
+			}
 		}
+
+		s.mu.Lock()
+		s.result.PrunedIssues = append(s.result.PrunedIssues, issueURL)
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// getFieldValuesForBatch retrieves field values for a batch of issues from both projects
+// Issues missing in either project are recorded as failed and returned as missing, unless
+// fail-fast is enabled, so that the other issues can still be synced.
+func (s *Service) getFieldValuesForBatch(ctx context.Context, sourceProjectID string, targetProjectID string, batch []string, sourceFieldConfigs []github.ProjectFieldConfig, targetFieldConfigs []github.ProjectFieldConfig) (sourceValues, targetValues map[string][]github.ProjectField, missing []error, err error) {
+	sourceValues = make(map[string][]github.ProjectField)
+	targetValues = make(map[string][]github.ProjectField)
+
+	for _, issueURL := range batch {
+		// The source and target values are independent reads, so fetch them concurrently.
+		// Errors are handled in a fixed order, source first, as if read one after the other.
+		var sourceFields, targetFields []github.ProjectField
+		var sourceErr, targetErr error
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sourceFields, sourceErr = s.client.GetProjectFieldValues(ctx, sourceProjectID, issueURL, sourceFieldConfigs)
+		}()
+		go func() {
+			defer wg.Done()
+			targetFields, targetErr = s.client.GetProjectFieldValues(ctx, targetProjectID, issueURL, targetFieldConfigs)
+		}()
+		wg.Wait()
+
+		if sourceErr != nil {
+			err = fmt.Errorf("failed to get source field values for %s: %w", issueURL, sourceErr)
+			if s.skipMissingIssue(issueURL, err) {
+				missing = append(missing, err)
+				continue
+			}
+			return nil, nil, nil, err
+		}
+		if targetErr != nil {
+			err = fmt.Errorf("failed to get target field values for %s: %w", issueURL, targetErr)
+			if s.skipMissingIssue(issueURL, err) {
+				missing = append(missing, err)
+				continue
+			}
+			return nil, nil, nil, err
+		}
+
+		sourceValues[issueURL] = sourceFields
 		targetValues[issueURL] = targetFields
 	}
 
-	return sourceValues, targetValues, nil
+	return sourceValues, targetValues, missing, nil
 }
 
-// applyFieldMappings applies field mappings for an issue
-func (s *Service) applyFieldMappings(ctx context.Context, targetProjectID string, issueURL string, sourceFields []github.ProjectField, targetFieldMap map[string]github.ProjectField, mappings []FieldMapping) error {
-	for _, mapping := range mappings {
-		for _, sourceField := range sourceFields {
-			if sourceField.Name == mapping.SourceField {
-				// Check if we need to update the target field
-				targetField := github.ProjectField{
-					Name:  mapping.TargetField,
-					Value: sourceField.Value,
-				}
+// skipMissingIssue records an issue that is not in one of the projects as failed and
+// reports whether the sync may continue without it
+func (s *Service) skipMissingIssue(issueURL string, err error) bool {
+	if !s.skippable(err) {
+		return false
+	}
+	slog.Warn("skipping issue missing in project", "url", issueURL, "error", err)
+	s.recordError(issueURL, "", err)
+	return true
+}
 
-				// If the field exists in target and has the same value, skip the update
-				if existingField, ok := targetFieldMap[mapping.TargetField]; ok {
-					if fieldsEqual(existingField, targetField) {
-						continue
-					}
-				}
+// skippable reports whether an error is for an issue missing in one of the projects, which
+// the sync continues without unless fail-fast is enabled
+func (s *Service) skippable(err error) bool {
+	return !s.failFast && errors.Is(err, client.ErrIssueNotFound)
+}
 
-				// Update field in target project
-				if err := s.client.UpdateProjectField(ctx, targetProjectID, issueURL, targetField, s.dryRun); err != nil {
-					return fmt.Errorf("failed to update field for %s: %w", issueURL, err)
-				}
+// applyFieldMappings applies field mappings for an issue
+func (s *Service) applyFieldMappings(ctx context.Context, targetProjectID string, issueURL string, title string, sourceFields []github.ProjectField, targetFieldMap map[string]github.ProjectField, mappings []FieldMapping) error {
+	// Apply the mappings in order of priority, as updates may depend on each other. Mappings
+	// sharing a target field are fallbacks: only the first one whose source field has a value
+	// is applied.
+	sorted := sortByPriority(mappings)
+	applied := make(map[string]bool)
+	for i, mapping := range sorted {
+		if applied[mapping.TargetField] {
+			continue
+		}
+		for _, sourceField := range sourceFields {
+			if sourceField.Name != mapping.SourceField {
+				continue
+			}
+			if sourceField.Value.IsEmpty() && hasMappingToTarget(sorted[i+1:], mapping.TargetField) {
 				break
 			}
+			applied[mapping.TargetField] = true
+			if err := s.applyFieldMapping(ctx, targetProjectID, issueURL, title, mapping, sourceField, targetFieldMap); err != nil {
+				return err
+			}
+			break
 		}
 	}
 	return nil
 }
 
+// applyFieldMapping writes the value of a source field to the target field of a mapping,
+// unless the target field already holds it
+func (s *Service) applyFieldMapping(ctx context.Context, targetProjectID string, issueURL string, title string, mapping FieldMapping, sourceField github.ProjectField, targetFieldMap map[string]github.ProjectField) error {
+	// Apply the transforms of the mapping, such as date offsets and value maps
+	value, err := mapping.transform(sourceField.Value)
+	if err != nil {
+		return err
+	}
+	existingField, ok := targetFieldMap[mapping.TargetField]
+	value = mapping.labelsFor(existingField.Value, value)
+
+	targetField := github.ProjectField{
+		ID:    mapping.TargetFieldID,
+		Name:  mapping.TargetField,
+		Value: value,
+	}
+	// The declared type describes the field of the target project, which reverse
+	// mappings read from
+	if mapping.Direction == DirectionForward {
+		targetField.DataType = mapping.FieldType
+	}
+
+	change := FieldChange{
+		IssueURL: issueURL,
+		Title:    title,
+		Field:    mapping.TargetField,
+		OldValue: existingField.Value.String(),
+		NewValue: targetField.Value.String(),
+	}
+
+	// If the field exists in target and has the same value, skip the update
+	if ok && (fieldsEqual(existingField, targetField) || s.normalize && sameNormalizedOption(existingField, targetField)) {
+		s.recordFieldSkipped(change)
+		return nil
+	}
+
+	// Keep existing target values if only empty fields are filled
+	if s.onlyFillEmpty && ok && !existingField.Value.IsEmpty() {
+		slog.Debug("keeping existing target value",
+			"issue", issueURL,
+			"field", mapping.TargetField,
+			"value", change.OldValue,
+			"source_value", change.NewValue,
+		)
+		s.recordFieldKept(change)
+		return nil
+	}
+
+	// Update field in target project. A preview only compares the values, so
+	// that it works without write access to the target project.
+	if !s.preview {
+		if err := s.client.UpdateProjectField(ctx, targetProjectID, issueURL, targetField, s.dryRun); err != nil {
+			return fmt.Errorf("failed to update field for %s: %w", issueURL, err)
+		}
+	}
+
+	s.recordChange(change)
+	return nil
+}
+
 // fieldsEqual checks if two fields have equal values
 func fieldsEqual(a, b github.ProjectField) bool {
 	if a.Value.Date != nil && b.Value.Date != nil {
 		return a.Value.Date.Equal(*b.Value.Date)
 	}
+	if a.Value.OptionID != "" && b.Value.OptionID != "" {
+		return a.Value.OptionID == b.Value.OptionID
+	}
 	if a.Value.Text != nil && b.Value.Text != nil {
 		return *a.Value.Text == *b.Value.Text
 	}
+	if a.Value.Number != nil && b.Value.Number != nil {
+		return *a.Value.Number == *b.Value.Number
+	}
+	if a.Value.Iteration != nil && b.Value.Iteration != nil {
+		return *a.Value.Iteration == *b.Value.Iteration
+	}
+	return issueValuesEqual(a.Value, b.Value)
+}
+
+// issueValuesEqual checks if two values reflecting the issue itself, such as its assignees,
+// milestone or labels, are equal
+func issueValuesEqual(a, b github.ProjectFieldValue) bool {
+	if a.Users != nil && b.Users != nil {
+		return github.SameLogins(a.Users, b.Users)
+	}
+	if a.Labels != nil || b.Labels != nil {
+		return sameLabels(a, b)
+	}
+	// Milestones are set by title, so they compare equal to single select values of the same name
+	if a.Milestone != nil || b.Milestone != nil {
+		return milestoneTitle(a) != nil && milestoneTitle(b) != nil &&
+			*milestoneTitle(a) == *milestoneTitle(b)
+	}
 	return false
 }
+
+// sameLabels checks if two values hold the same labels. A single select value written to a
+// labels field adds its label, so it is equal to labels that already contain it.
+func sameLabels(a, b github.ProjectFieldValue) bool {
+	if a.Labels != nil && b.Labels != nil {
+		return github.SameLabels(a.Labels, b.Labels)
+	}
+	labels, text := a.Labels, b.Text
+	if labels == nil {
+		labels, text = b.Labels, a.Text
+	}
+	return text != nil && slices.ContainsFunc(labels, func(label string) bool {
+		return strings.EqualFold(label, *text)
+	})
+}
+
+// sameNormalizedOption checks if two single select values name the same option when
+// ignoring leading emoji and differences in whitespace
+func sameNormalizedOption(a, b github.ProjectField) bool {
+	if a.Value.OptionID != "" && b.Value.OptionID != "" {
+		return a.Value.OptionID == b.Value.OptionID
+	}
+	return a.Value.Text != nil && b.Value.Text != nil &&
+		github.NormalizeOptionName(*a.Value.Text) == github.NormalizeOptionName(*b.Value.Text)
+}
+
+// milestoneTitle returns the milestone title or single select name of a value
+func milestoneTitle(value github.ProjectFieldValue) *string {
+	if value.Milestone != nil {
+		return value.Milestone
+	}
+	return value.Text
+}
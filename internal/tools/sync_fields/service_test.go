@@ -1,7 +1,14 @@
 package sync_fields
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -60,7 +67,7 @@ func TestSyncFieldsWithoutDryRun(t *testing.T) {
 		},
 	}
 
-	service := NewService(mockClient, false)
+	service := NewService(mockClient, Options{})
 
 	err := service.SyncFields(
 		context.Background(),
@@ -126,7 +133,7 @@ func TestSyncFieldsWithDryRun(t *testing.T) {
 		},
 	}
 
-	service := NewService(mockClient, true)
+	service := NewService(mockClient, Options{DryRun: true})
 
 	err := service.SyncFields(
 		context.Background(),
@@ -139,4 +146,2000 @@ func TestSyncFieldsWithDryRun(t *testing.T) {
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
+
+	changes := service.Result().Changes
+	expected := FieldChange{
+		IssueURL: "https://github.com/org/repo/issues/1",
+		Title:    "Test Issue",
+		Field:    "Start date",
+		NewValue: now.Format("2006-01-02"),
+	}
+	if len(changes) != 1 || changes[0] != expected {
+		t.Errorf("expected planned changes %v, got %v", []FieldChange{expected}, changes)
+	}
+	if !service.Result().HasChanges() {
+		t.Error("expected the dry run to report pending changes")
+	}
+}
+
+func TestSyncFieldsResult(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		if issueURL == issues[1] {
+			return fmt.Errorf("field is read-only")
+		}
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	result, err := service.SyncFieldsResult(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil {
+		t.Fatal("expected the failed issue to fail the sync")
+	}
+
+	if result.IssuesProcessed != 2 {
+		t.Errorf("expected 2 processed issues, got %d", result.IssuesProcessed)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].IssueURL != issues[0] {
+		t.Errorf("expected a change of %s, got %v", issues[0], result.Changes)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].IssueURL != issues[1] {
+		t.Errorf("expected an error of %s, got %v", issues[1], result.Errors)
+	}
+	if !reflect.DeepEqual(result, service.Result()) {
+		t.Errorf("expected the returned result to match the result of the service")
+	}
+}
+
+func TestResultHasChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		want   bool
+	}{
+		{name: "in sync", result: Result{FieldsSkipped: 2, Unchanged: []FieldChange{{Field: "Start date"}}}},
+		{name: "field change", result: Result{Changes: []FieldChange{{Field: "Start date"}}}, want: true},
+		{name: "pruned item", result: Result{PrunedIssues: []string{"https://github.com/org/repo/issues/1"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.HasChanges(); got != tt.want {
+				t.Errorf("expected HasChanges() = %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsReportsIssuesWithoutSourceValues(t *testing.T) {
+	now := time.Now()
+	issueWithValue := "https://github.com/org/repo/issues/1"
+	issueWithoutValue := "https://github.com/org/repo/issues/2"
+
+	var updatedIssues []string
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			if projectInfo.ProjectNumber == 824 {
+				return "project_1", nil
+			}
+			return "project_2", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
+			return []github.ProjectFieldConfig{
+					{ID: "1", Name: "start", Type: "ProjectV2Field"},
+				},
+				[]github.ProjectFieldConfig{
+					{ID: "2", Name: "Start date", Type: "ProjectV2Field"},
+				},
+				[]string{issueWithValue, issueWithoutValue},
+				[]string{issueWithValue, issueWithoutValue},
+				nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			if projectID == "project_1" && issueURL == issueWithValue {
+				return []github.ProjectField{
+					{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &now}},
+				}, nil
+			}
+			return []github.ProjectField{}, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			updatedIssues = append(updatedIssues, issueURL)
+			return nil
+		},
+	}
+
+	service := NewService(mockClient, Options{})
+
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(updatedIssues) != 1 || updatedIssues[0] != issueWithValue {
+		t.Errorf("expected only %s to be updated, got %v", issueWithValue, updatedIssues)
+	}
+
+	result := service.Result()
+	if len(result.IssuesWithoutSourceValues) != 1 || result.IssuesWithoutSourceValues[0] != issueWithoutValue {
+		t.Errorf("expected %s to be reported without source values, got %v", issueWithoutValue, result.IssuesWithoutSourceValues)
+	}
+}
+
+func TestSyncFieldsRequireSourceValue(t *testing.T) {
+	now := time.Now()
+	issueWithValue := "https://github.com/org/repo/issues/1"
+	issueWithoutValue := "https://github.com/org/repo/issues/2"
+
+	mockClient := newSyncMockClient([]string{issueWithValue, issueWithoutValue}, now)
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" && issueURL == issueWithValue {
+			return []github.ProjectField{
+				{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &now}},
+			}, nil
+		}
+		return []github.ProjectField{}, nil
+	}
+
+	service := NewService(mockClient, Options{RequireSourceValue: true, Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil {
+		t.Fatal("expected an error for the issue without a source value")
+	}
+
+	result := service.Result()
+	if len(result.Errors) != 1 || result.Errors[0].IssueURL != issueWithoutValue {
+		t.Fatalf("expected only %s to fail, got %v", issueWithoutValue, result.Errors)
+	}
+	if want := "no value in source field start"; result.Errors[0].Error != want {
+		t.Errorf("expected error %q, got %q", want, result.Errors[0].Error)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].IssueURL != issueWithValue {
+		t.Errorf("expected %s to be synced, got %v", issueWithValue, result.Changes)
+	}
+}
+
+func TestForEachIssue(t *testing.T) {
+	issues := make([]string, 25)
+	for i := range issues {
+		issues[i] = fmt.Sprintf("https://github.com/org/repo/issues/%d", i+1)
+	}
+
+	service := NewService(&client.MockClient{}, Options{Concurrency: 4})
+
+	var mu sync.Mutex
+	processed := make(map[string]bool)
+	err := service.forEachIssue(context.Background(), issues, func(ctx context.Context, issueURL string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		processed[issueURL] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(processed) != len(issues) {
+		t.Errorf("expected %d issues to be processed, got %d", len(issues), len(processed))
+	}
+
+	failure := errors.New("update failed")
+	err = service.forEachIssue(context.Background(), issues, func(ctx context.Context, issueURL string) error {
+		if issueURL == issues[3] {
+			return failure
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("expected error %v, got %v", failure, err)
+	}
+}
+
+// newSyncMockClient creates a mock client for syncing the given issues from project_1 (number 824)
+// to project_2 (number 825), where every issue has the given date in the source field "start"
+func newSyncMockClient(issues []string, date time.Time) *client.MockClient {
+	return &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			if projectInfo.ProjectNumber == 824 {
+				return "project_1", nil
+			}
+			return "project_2", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
+			return []github.ProjectFieldConfig{
+					{ID: "1", Name: "start", Type: "ProjectV2Field", DataType: "DATE"},
+				},
+				[]github.ProjectFieldConfig{
+					{ID: "2", Name: "Start date", Type: "ProjectV2Field", DataType: "DATE"},
+				},
+				issues,
+				issues,
+				nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			if projectID == "project_1" {
+				return []github.ProjectField{
+					{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &date}},
+				}, nil
+			}
+			return []github.ProjectField{}, nil
+		},
+		GetIssueTitleFunc: func(ctx context.Context, issueURL string) (string, error) {
+			return "Test Issue", nil
+		},
+	}
+}
+
+func TestSyncFieldsReportsOrphans(t *testing.T) {
+	common := "https://github.com/org/repo/issues/1"
+	sourceOnly := "https://github.com/org/repo/issues/2"
+	targetOnly := "https://github.com/org/repo/issues/3"
+
+	newClient := func() *client.MockClient {
+		mockClient := newSyncMockClient(nil, time.Now())
+		getConfigs := mockClient.GetProjectFieldConfigsAndIssuesFunc
+		mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			sourceConfigs, targetConfigs, _, _, err := getConfigs(ctx, sourceProjectID, targetProjectID)
+			return sourceConfigs, targetConfigs, []string{common, sourceOnly}, []string{common, targetOnly}, err
+		}
+		return mockClient
+	}
+
+	sync := func(opts Options) (*Service, error) {
+		opts.Concurrency = 1
+		service := NewService(newClient(), opts)
+		err := service.SyncFields(
+			context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/orgs/myorg/projects/825",
+			nil,
+			[]string{"start=Start date"},
+		)
+		return service, err
+	}
+
+	service, err := sync(Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary := service.Summary(); summary.SourceOnlyIssues != nil || summary.TargetOnlyIssues != nil {
+		t.Errorf("expected no orphans unless reported, got %+v", summary)
+	}
+
+	service, err = sync(Options{ReportOrphans: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	summary := service.Summary()
+	if !reflect.DeepEqual(summary.SourceOnlyIssues, []string{sourceOnly}) {
+		t.Errorf("expected source-only issue %s, got %v", sourceOnly, summary.SourceOnlyIssues)
+	}
+	if !reflect.DeepEqual(summary.TargetOnlyIssues, []string{targetOnly}) {
+		t.Errorf("expected target-only issue %s, got %v", targetOnly, summary.TargetOnlyIssues)
+	}
+
+	service, err = sync(Options{FailOnOrphans: true})
+	if err == nil || err.Error() != "2 issues are in only one of the projects" {
+		t.Fatalf("expected the sync to fail on orphans, got %v", err)
+	}
+	if service.Summary().FieldsUpdated != 1 {
+		t.Errorf("expected the common issue to be synced before failing, got %+v", service.Summary())
+	}
+
+	// Target issues outside a server filter are not orphans
+	mockClient := newClient()
+	mockClient.ServerFilterFunc = func() string { return "status:Done" }
+	service = NewService(mockClient, Options{FailOnOrphans: true, Concurrency: 1})
+	err = service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil || err.Error() != "1 issues are in only one of the projects" {
+		t.Fatalf("expected the sync to fail on the source-only issue, got %v", err)
+	}
+	if summary := service.Summary(); summary.TargetOnlyIssues != nil {
+		t.Errorf("expected no target-only issues with a server filter, got %v", summary.TargetOnlyIssues)
+	}
+}
+
+func TestSyncFieldsAddsMissingIssues(t *testing.T) {
+	common := "https://github.com/org/repo/issues/1"
+	missing := "https://github.com/org/repo/issues/2"
+	// Draft issues cannot be added to another project, so they are skipped
+	draft := client.DraftIssuePrefix + "DI_1"
+
+	for _, dryRun := range []bool{false, true} {
+		var added, updated []string
+		mockClient := newSyncMockClient(nil, time.Now())
+		getConfigs := mockClient.GetProjectFieldConfigsAndIssuesFunc
+		mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			sourceConfigs, targetConfigs, _, _, err := getConfigs(ctx, sourceProjectID, targetProjectID)
+			return sourceConfigs, targetConfigs, []string{common, missing, draft}, []string{common}, err
+		}
+		mockClient.AddIssueToProjectFunc = func(ctx context.Context, projectID string, issueURL string, addDryRun bool) (string, error) {
+			if projectID != "project_2" || addDryRun != dryRun {
+				t.Errorf("unexpected addition of %s to %s (dry run %v)", issueURL, projectID, addDryRun)
+			}
+			added = append(added, issueURL)
+			return "item_2", nil
+		}
+		mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			updated = append(updated, issueURL)
+			return nil
+		}
+
+		service := NewService(mockClient, Options{DryRun: dryRun, AddMissingToTarget: true, Concurrency: 1})
+		err := service.SyncFields(
+			context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/orgs/myorg/projects/825",
+			nil,
+			[]string{"start=Start date"},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error (dry run %v): %v", dryRun, err)
+		}
+
+		if !reflect.DeepEqual(added, []string{missing}) {
+			t.Errorf("expected %s to be added (dry run %v), got %v", missing, dryRun, added)
+		}
+		if !reflect.DeepEqual(service.Result().AddedIssues, []string{missing}) {
+			t.Errorf("expected %s to be recorded as added (dry run %v), got %v", missing, dryRun, service.Result().AddedIssues)
+		}
+
+		// Added issues have no target item in dry run mode, so their fields are not synced
+		want := []string{common, missing}
+		if dryRun {
+			want = []string{common}
+		}
+		if !reflect.DeepEqual(updated, want) {
+			t.Errorf("expected fields of %v to be synced (dry run %v), got %v", want, dryRun, updated)
+		}
+	}
+}
+
+func TestSyncFieldsCreatesMissingFields(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+
+	for _, dryRun := range []bool{false, true} {
+		var created []github.ProjectFieldConfig
+		var updated []string
+		mockClient := newSyncMockClient(issues, time.Now())
+		mockClient.CreateProjectFieldFunc = func(ctx context.Context, projectID string, cfg github.ProjectFieldConfig) (github.ProjectFieldConfig, error) {
+			if projectID != "project_2" {
+				t.Errorf("unexpected creation of field %s in %s", cfg.Name, projectID)
+			}
+			created = append(created, cfg)
+			cfg.ID = "3"
+			return cfg, nil
+		}
+		mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			updated = append(updated, field.ID+":"+field.Name)
+			return nil
+		}
+
+		service := NewService(mockClient, Options{DryRun: dryRun, CreateMissingFields: true, Concurrency: 1})
+		err := service.SyncFields(
+			context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/orgs/myorg/projects/825",
+			nil,
+			[]string{"start=Start date", "start=Due"},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error (dry run %v): %v", dryRun, err)
+		}
+
+		if !reflect.DeepEqual(service.Result().CreatedFields, []string{"Due"}) {
+			t.Errorf("expected Due to be recorded as created (dry run %v), got %v", dryRun, service.Result().CreatedFields)
+		}
+
+		// Fields to create do not exist in dry run mode, so the mappings to them are skipped
+		wantCreated := []github.ProjectFieldConfig{{Name: "Due", Type: "ProjectV2Field", DataType: "DATE"}}
+		wantUpdated := []string{"2:Start date", "3:Due"}
+		if dryRun {
+			wantCreated, wantUpdated = nil, []string{"2:Start date"}
+		}
+		if !reflect.DeepEqual(created, wantCreated) {
+			t.Errorf("expected fields %v to be created (dry run %v), got %v", wantCreated, dryRun, created)
+		}
+		if !reflect.DeepEqual(updated, wantUpdated) {
+			t.Errorf("expected fields %v to be updated (dry run %v), got %v", wantUpdated, dryRun, updated)
+		}
+	}
+}
+
+func TestSyncFieldsPreflightFailsBeforeWriting(t *testing.T) {
+	common := "https://github.com/org/repo/issues/1"
+	missing := "https://github.com/org/repo/issues/2"
+	blocked := "Blocked"
+
+	var writes []string
+	mockClient := newSyncMockClient(nil, time.Now())
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{
+				{ID: "1", Name: "start", Type: "ProjectV2Field", DataType: "DATE"},
+				{ID: "3", Name: "Status", Type: "ProjectV2SingleSelectField", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "opt_1", Name: blocked}}},
+			},
+			[]github.ProjectFieldConfig{
+				{ID: "4", Name: "Status", Type: "ProjectV2SingleSelectField", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "opt_2", Name: "Todo"}}},
+			},
+			[]string{common, missing}, []string{common}, nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		return []github.ProjectField{{ID: "3", Name: "Status", Value: github.ProjectFieldValue{Text: &blocked}}}, nil
+	}
+	mockClient.CreateProjectFieldFunc = func(ctx context.Context, projectID string, cfg github.ProjectFieldConfig) (github.ProjectFieldConfig, error) {
+		writes = append(writes, "create "+cfg.Name)
+		return cfg, nil
+	}
+	mockClient.AddIssueToProjectFunc = func(ctx context.Context, projectID string, issueURL string, dryRun bool) (string, error) {
+		writes = append(writes, "add "+issueURL)
+		return "item_2", nil
+	}
+
+	service := NewService(mockClient, Options{CreateMissingFields: true, AddMissingToTarget: true, Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"Status=Status", "start=Start date"},
+	)
+	var unresolved *UnresolvedOptionsError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("expected an unresolved options error, got %v", err)
+	}
+	if len(writes) != 0 {
+		t.Errorf("expected nothing to be written before the preflight passed, got %v", writes)
+	}
+}
+
+func TestSyncFieldsOnlyFillEmpty(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+	sourceDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	targetDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	var updated []string
+	mockClient := newSyncMockClient(issues, sourceDate)
+	getValues := mockClient.GetProjectFieldValuesFunc
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		// The first issue has a target value already
+		if projectID == "project_2" && issueURL == issues[0] {
+			return []github.ProjectField{
+				{ID: "2", Name: "Start date", Value: github.ProjectFieldValue{Date: &targetDate}},
+			}, nil
+		}
+		return getValues(ctx, projectID, issueURL, fieldConfigs)
+	}
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updated = append(updated, issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{OnlyFillEmpty: true, Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(updated, issues[1:]) {
+		t.Errorf("expected only the empty target field of %s to be written, got %v", issues[1], updated)
+	}
+
+	summary := service.Summary()
+	if summary.FieldsKept != 1 || summary.FieldsUpdated != 1 || summary.FieldsSkipped != 0 {
+		t.Errorf("expected 1 kept and 1 updated field, got %+v", summary)
+	}
+	wantKept := []FieldChange{{IssueURL: issues[0], Title: "Test Issue", Field: "Start date", OldValue: "2024-02-01", NewValue: "2024-03-01"}}
+	if kept := service.Result().Kept; !reflect.DeepEqual(kept, wantKept) {
+		t.Errorf("expected kept fields %+v, got %+v", wantKept, kept)
+	}
+}
+
+func TestSyncFieldsFallsBackToNextMappingOfTargetField(t *testing.T) {
+	hard := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	soft := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		mappings     []string
+		sourceFields []github.ProjectField
+		want         []time.Time
+	}{
+		{
+			name:     "first mapping has a value",
+			mappings: []string{"Hard Due Date=Due", "Soft Due Date=Due"},
+			sourceFields: []github.ProjectField{
+				{Name: "Hard Due Date", Value: github.ProjectFieldValue{Date: &hard}},
+				{Name: "Soft Due Date", Value: github.ProjectFieldValue{Date: &soft}},
+			},
+			want: []time.Time{hard},
+		},
+		{
+			name:         "first source field is missing",
+			mappings:     []string{"Hard Due Date=Due", "Soft Due Date=Due"},
+			sourceFields: []github.ProjectField{{Name: "Soft Due Date", Value: github.ProjectFieldValue{Date: &soft}}},
+			want:         []time.Time{soft},
+		},
+		{
+			name:     "first source field is empty",
+			mappings: []string{"Hard Due Date=Due", "Soft Due Date=Due"},
+			sourceFields: []github.ProjectField{
+				{Name: "Hard Due Date"},
+				{Name: "Soft Due Date", Value: github.ProjectFieldValue{Date: &soft}},
+			},
+			want: []time.Time{soft},
+		},
+		{
+			name:     "priority decides the order",
+			mappings: []string{"Soft Due Date=Due@2", "Hard Due Date=Due@1"},
+			sourceFields: []github.ProjectField{
+				{Name: "Hard Due Date", Value: github.ProjectFieldValue{Date: &hard}},
+				{Name: "Soft Due Date", Value: github.ProjectFieldValue{Date: &soft}},
+			},
+			want: []time.Time{hard},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := []string{"https://github.com/org/repo/issues/1"}
+			var written []time.Time
+			mockClient := newSyncMockClient(issues, time.Now())
+			mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+				return []github.ProjectFieldConfig{
+						{ID: "1", Name: "Hard Due Date", DataType: "DATE"},
+						{ID: "2", Name: "Soft Due Date", DataType: "DATE"},
+					},
+					[]github.ProjectFieldConfig{{ID: "3", Name: "Due", DataType: "DATE"}},
+					issues, issues, nil
+			}
+			mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+				if projectID == "project_1" {
+					return tt.sourceFields, nil
+				}
+				return []github.ProjectField{}, nil
+			}
+			mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+				written = append(written, *field.Value.Date)
+				return nil
+			}
+
+			service := NewService(mockClient, Options{RequireSourceValue: true})
+			err := service.SyncFields(
+				context.Background(),
+				"https://github.com/orgs/myorg/projects/824",
+				"https://github.com/orgs/myorg/projects/825",
+				issues,
+				tt.mappings,
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(written, tt.want) {
+				t.Errorf("expected Due to be set to %v, got %v", tt.want, written)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsCapsIssues(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}
+
+	var updated []string
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updated = append(updated, issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{MaxIssues: 2, Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(updated, issues[:2]) {
+		t.Errorf("expected only the first two issues to be synced, got %v", updated)
+	}
+	if processed := service.Result().IssuesProcessed; processed != 2 {
+		t.Errorf("expected 2 processed issues, got %d", processed)
+	}
+}
+
+func TestSyncFieldsResolvesProjectIDsTogether(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+
+	var singleLookups, pairLookups int
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectIDFunc = func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+		singleLookups++
+		return "", nil
+	}
+	mockClient.GetProjectIDsFunc = func(ctx context.Context, sourceProject, targetProject *github.ProjectInfo) (string, string, error) {
+		pairLookups++
+		return "project_1", "project_2", nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pairLookups != 1 || singleLookups != 0 {
+		t.Errorf("expected the project IDs to be resolved in one lookup, got %d pair and %d single lookups", pairLookups, singleLookups)
+	}
+}
+
+func TestSyncFieldsReverseMapping(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	completed := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	mockClient := newSyncMockClient(issues, start)
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{
+				{ID: "1", Name: "start", DataType: "DATE"},
+				{ID: "3", Name: "Done", DataType: "DATE"},
+			},
+			[]github.ProjectFieldConfig{
+				{ID: "2", Name: "Start date", DataType: "DATE"},
+				{ID: "4", Name: "Completed", DataType: "DATE"},
+			},
+			issues, issues, nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &start}}}, nil
+		}
+		return []github.ProjectField{{ID: "4", Name: "Completed", Value: github.ProjectFieldValue{Date: &completed}}}, nil
+	}
+
+	updates := make(map[string]github.ProjectField)
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updates[projectID] = field
+		return nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date", "Done<-Completed:+1d"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := updates["project_2"]; got.ID != "2" || !got.Value.Date.Equal(start) {
+		t.Errorf("expected the start date to be written to the target project, got %+v", got)
+	}
+	got := updates["project_1"]
+	if got.ID != "3" || got.Name != "Done" || got.Value.Date == nil || !got.Value.Date.Equal(completed.AddDate(0, 0, 1)) {
+		t.Errorf("expected the shifted completion date to be written to the source project, got %+v", got)
+	}
+}
+
+func TestSyncFieldsFiltersDetectedIssues(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+
+	var updated []string
+	var filters []string
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectIssuesFilteredFunc = func(ctx context.Context, projectID string, filter string) ([]string, error) {
+		filters = append(filters, projectID+" "+filter)
+		return []string{"https://github.com/org/repo/issues/2", "https://github.com/org/repo/issues/3"}, nil
+	}
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updated = append(updated, issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{IssueFilter: "status:Todo label:bug"})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(filters, []string{"project_1 status:Todo label:bug"}) {
+		t.Errorf("expected the source project to be filtered, got %v", filters)
+	}
+	if !reflect.DeepEqual(updated, []string{"https://github.com/org/repo/issues/2"}) {
+		t.Errorf("expected only the matching common issue to be synced, got %v", updated)
+	}
+}
+
+func TestSyncFieldsToTargets(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	targets := []string{
+		"https://github.com/orgs/myorg/projects/825",
+		"https://github.com/orgs/myorg/projects/826",
+		"https://github.com/orgs/myorg/projects/827",
+	}
+
+	for _, failFast := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fail fast %v", failFast), func(t *testing.T) {
+			var updatedProjects []string
+			mockClient := newSyncMockClient(issues, time.Now())
+			mockClient.GetProjectIDFunc = func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+				return fmt.Sprintf("project_%d", projectInfo.ProjectNumber), nil
+			}
+			loadProjects := mockClient.GetProjectFieldConfigsAndIssuesFunc
+			mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+				if targetProjectID == "project_826" {
+					return nil, nil, nil, nil, errors.New("project not accessible")
+				}
+				return loadProjects(ctx, sourceProjectID, targetProjectID)
+			}
+			getFieldValues := mockClient.GetProjectFieldValuesFunc
+			mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+				if projectID == "project_824" {
+					projectID = "project_1"
+				}
+				return getFieldValues(ctx, projectID, issueURL, fieldConfigs)
+			}
+			mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+				updatedProjects = append(updatedProjects, projectID)
+				return nil
+			}
+
+			service := NewService(mockClient, Options{FailFast: failFast})
+			err := service.SyncFieldsToTargets(
+				context.Background(),
+				"https://github.com/orgs/myorg/projects/824",
+				targets,
+				nil,
+				[]string{"start=Start date"},
+			)
+
+			wantUpdated := []string{"project_825", "project_827"}
+			wantErr := "failed to sync 1 of 3 target projects"
+			if failFast {
+				wantUpdated = []string{"project_825"}
+				wantErr = "failed to sync target project https://github.com/orgs/myorg/projects/826"
+			}
+			if err == nil || !strings.Contains(err.Error(), wantErr) {
+				t.Fatalf("expected error containing %q, got %v", wantErr, err)
+			}
+			if !reflect.DeepEqual(updatedProjects, wantUpdated) {
+				t.Errorf("expected updates of %v, got %v", wantUpdated, updatedProjects)
+			}
+
+			summary := service.Summary()
+			if len(summary.Targets) != len(wantUpdated)+1 {
+				t.Fatalf("expected a summary per synced target project, got %+v", summary.Targets)
+			}
+			if summary.FieldsUpdated != len(wantUpdated) {
+				t.Errorf("expected %d updated fields in total, got %d", len(wantUpdated), summary.FieldsUpdated)
+			}
+			if summary.Targets[0].TargetProject != targets[0] || summary.Targets[0].FieldsUpdated != 1 || summary.Targets[0].Error != "" {
+				t.Errorf("unexpected summary of the first target project: %+v", summary.Targets[0])
+			}
+			if !strings.Contains(summary.Targets[1].Error, "project not accessible") {
+				t.Errorf("expected the error of the second target project, got %+v", summary.Targets[1])
+			}
+		})
+	}
+}
+
+func TestSyncFieldsStopsWhenAPIBudgetExhausted(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}
+	mockClient := newSyncMockClient(issues, time.Now())
+	calls := 0
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		calls++
+		if calls > 1 {
+			return fmt.Errorf("failed to update field: %w", client.ErrAPIBudgetExhausted)
+		}
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if !errors.Is(err, client.ErrAPIBudgetExhausted) {
+		t.Fatalf("expected the sync to stop with an exhausted budget, got %v", err)
+	}
+
+	result := service.Result()
+	if len(result.Changes) != 1 || result.Changes[0].IssueURL != issues[0] {
+		t.Errorf("expected the change of %s to be kept, got %v", issues[0], result.Changes)
+	}
+	if !reflect.DeepEqual(result.UnprocessedIssues, issues[1:]) {
+		t.Errorf("expected unprocessed issues %v, got %v", issues[1:], result.UnprocessedIssues)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("expected unprocessed issues not to be reported as failed, got %v", result.Errors)
+	}
+	if summary := service.Summary(); !reflect.DeepEqual(summary.UnprocessedIssues, issues[1:]) {
+		t.Errorf("expected unprocessed issues %v in the summary, got %v", issues[1:], summary.UnprocessedIssues)
+	}
+}
+
+func TestSyncFieldsAggregatesIssueErrors(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+
+	for _, failFast := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fail fast %v", failFast), func(t *testing.T) {
+			var updatedIssues []string
+			mockClient := newSyncMockClient(issues, time.Now())
+			mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+				if issueURL == issues[0] {
+					return errors.New("update failed")
+				}
+				updatedIssues = append(updatedIssues, issueURL)
+				return nil
+			}
+
+			service := NewService(mockClient, Options{FailFast: failFast})
+			err := service.SyncFields(
+				context.Background(),
+				"https://github.com/orgs/myorg/projects/824",
+				"https://github.com/orgs/myorg/projects/825",
+				issues,
+				[]string{"start=Start date"},
+			)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			result := service.Result()
+			if len(result.Errors) != 1 || result.Errors[0].IssueURL != issues[0] {
+				t.Errorf("expected a single error for %s, got %v", issues[0], result.Errors)
+			}
+
+			if failFast {
+				if len(updatedIssues) != 0 {
+					t.Errorf("expected no further updates with fail fast, got %v", updatedIssues)
+				}
+				return
+			}
+			if len(updatedIssues) != 1 || updatedIssues[0] != issues[1] {
+				t.Errorf("expected %s to still be updated, got %v", issues[1], updatedIssues)
+			}
+			if !strings.Contains(err.Error(), "failed to sync 1 of 2 issues") {
+				t.Errorf("expected summary error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsRejectsSameProject(t *testing.T) {
+	mockClient := newSyncMockClient([]string{"https://github.com/org/repo/issues/1"}, time.Now())
+	mockClient.GetProjectIDFunc = func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+		return "project_1", nil
+	}
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
+		t.Error("expected no project data to be loaded")
+		return nil, nil, nil, nil, nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/824",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil || !strings.Contains(err.Error(), "same project") {
+		t.Errorf("expected same project error, got %v", err)
+	}
+}
+
+func TestSyncFieldsDetectsSameProjectByID(t *testing.T) {
+	// Projects are compared by ID, so that different URLs resolving to one project are detected
+	newClient := func() *client.MockClient {
+		mockClient := newSyncMockClient([]string{"https://github.com/org/repo/issues/1"}, time.Now())
+		mockClient.GetProjectIDsFunc = func(ctx context.Context, sourceProject, targetProject *github.ProjectInfo) (string, string, error) {
+			return "project_1", "project_1", nil
+		}
+		return mockClient
+	}
+	sync := func(opts Options) error {
+		return NewService(newClient(), opts).SyncFields(
+			context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/users/someone/projects/7",
+			nil,
+			[]string{"start=Start date"},
+		)
+	}
+
+	if err := sync(Options{}); err == nil || !strings.Contains(err.Error(), "same project (project_1)") {
+		t.Errorf("expected same project error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	if err := sync(Options{AllowSameProject: true, DryRun: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "source and target are the same project") {
+		t.Errorf("expected a warning about the same project, got:\n%s", buf.String())
+	}
+}
+
+func TestSyncFieldsCopiesFieldWithinSameProject(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	targetDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	baselineDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var updates []github.ProjectField
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			return "project_1", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
+			configs := []github.ProjectFieldConfig{
+				{ID: "1", Name: "Target date", Type: "ProjectV2Field"},
+				{ID: "2", Name: "Baseline date", Type: "ProjectV2Field"},
+			}
+			return configs, configs, []string{issueURL}, []string{issueURL}, nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			if projectID != "project_1" {
+				t.Errorf("expected field values of project_1, got %s", projectID)
+			}
+			return []github.ProjectField{
+				{ID: "1", Name: "Target date", Value: github.ProjectFieldValue{Date: &targetDate}},
+				{ID: "2", Name: "Baseline date", Value: github.ProjectFieldValue{Date: &baselineDate}},
+			}, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			if projectID != "project_1" {
+				t.Errorf("expected update in project_1, got %s", projectID)
+			}
+			updates = append(updates, field)
+			return nil
+		},
+		GetIssueTitleFunc: func(ctx context.Context, issueURL string) (string, error) {
+			return "Test Issue", nil
+		},
+	}
+
+	service := NewService(mockClient, Options{AllowSameProject: true})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/824",
+		nil,
+		[]string{"Target date=Baseline date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(updates))
+	}
+	if updates[0].Name != "Baseline date" || !targetDate.Equal(*updates[0].Value.Date) {
+		t.Errorf("expected Baseline date to be set to %v, got %s=%v", targetDate, updates[0].Name, updates[0].Value.Date)
+	}
+
+	err = service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/824",
+		nil,
+		[]string{"Target date=Target date"},
+	)
+	if err == nil || !strings.Contains(err.Error(), "onto itself") {
+		t.Errorf("expected error for mapping a field onto itself, got %v", err)
+	}
+}
+
+func TestFindCommonIssuesComparesCanonicalURLs(t *testing.T) {
+	sourceIssues := []string{
+		"https://github.com/Org/Repo/issues/1",
+		"https://github.com/org/repo/issues/2/",
+		"https://github.com/org/repo/issues/3",
+	}
+	targetIssues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://api.github.com/repos/org/repo/issues/2",
+	}
+
+	want := []string{"https://github.com/Org/Repo/issues/1", "https://github.com/org/repo/issues/2/"}
+	if got := findCommonIssues(sourceIssues, targetIssues); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected common issues %v, got %v", want, got)
+	}
+}
+
+func TestFindTargetOnlyIssues(t *testing.T) {
+	tests := []struct {
+		name         string
+		sourceIssues []string
+		targetIssues []string
+		want         []string
+	}{
+		{
+			name:         "target only issues",
+			sourceIssues: []string{"issue/1", "issue/2"},
+			targetIssues: []string{"issue/1", "issue/3", "issue/4"},
+			want:         []string{"issue/3", "issue/4"},
+		},
+		{
+			name:         "all issues in source",
+			sourceIssues: []string{"issue/1", "issue/2"},
+			targetIssues: []string{"issue/2"},
+			want:         nil,
+		},
+		{
+			name:         "empty source",
+			sourceIssues: nil,
+			targetIssues: []string{"issue/1"},
+			want:         []string{"issue/1"},
+		},
+		{
+			name:         "URL variants",
+			sourceIssues: []string{"https://github.com/Org/Repo/issues/1", "https://github.com/org/repo/issues/2/"},
+			targetIssues: []string{"https://github.com/org/repo/issues/1", "https://github.com/org/repo/issues/2", "https://github.com/org/repo/issues/3"},
+			want:         []string{"https://github.com/org/repo/issues/3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findTargetOnlyIssues(tt.sourceIssues, tt.targetIssues)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsPrunesTargetItems(t *testing.T) {
+	common := "https://github.com/org/repo/issues/1"
+	targetOnly := "https://github.com/org/repo/issues/2"
+
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dry run %v", dryRun), func(t *testing.T) {
+			mockClient := newSyncMockClient([]string{common}, time.Now())
+			mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) (sourceConfigs []github.ProjectFieldConfig, targetConfigs []github.ProjectFieldConfig, sourceIssues []string, targetIssues []string, err error) {
+				return []github.ProjectFieldConfig{{ID: "1", Name: "start", Type: "ProjectV2Field"}},
+					[]github.ProjectFieldConfig{{ID: "2", Name: "Start date", Type: "ProjectV2Field"}},
+					[]string{common},
+					[]string{common, targetOnly},
+					nil
+			}
+			var deleted []string
+			mockClient.DeleteProjectItemFunc = func(ctx context.Context, projectID string, issueURL string) error {
+				if projectID != "project_2" {
+					t.Errorf("expected item to be deleted from project_2, got %s", projectID)
+				}
+				deleted = append(deleted, issueURL)
+				return nil
+			}
+
+			service := NewService(mockClient, Options{DryRun: dryRun, PruneTargetItems: true})
+			err := service.SyncFields(
+				context.Background(),
+				"https://github.com/orgs/myorg/projects/824",
+				"https://github.com/orgs/myorg/projects/825",
+				nil,
+				[]string{"start=Start date"},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			pruned := service.Result().PrunedIssues
+			if len(pruned) != 1 || pruned[0] != targetOnly {
+				t.Errorf("expected %s to be pruned, got %v", targetOnly, pruned)
+			}
+			if dryRun && len(deleted) != 0 {
+				t.Errorf("expected no deletions in dry run mode, got %v", deleted)
+			}
+			if !dryRun && (len(deleted) != 1 || deleted[0] != targetOnly) {
+				t.Errorf("expected %s to be deleted, got %v", targetOnly, deleted)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsRejectsPruneWithServerFilter(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.ServerFilterFunc = func() string { return "status:Done" }
+	mockClient.DeleteProjectItemFunc = func(ctx context.Context, projectID string, issueURL string) error {
+		t.Errorf("expected no deletions, got %s", issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{PruneTargetItems: true})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil || !strings.Contains(err.Error(), "server filter") {
+		t.Errorf("expected an error about the server filter, got %v", err)
+	}
+}
+
+func TestSyncFieldsAppliesMappingsInPriorityOrder(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	date := time.Now()
+	status := "In progress"
+
+	mockClient := newSyncMockClient([]string{issueURL}, date)
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{
+				{ID: "1", Name: "start", DataType: "DATE"},
+				{ID: "2", Name: "end", DataType: "DATE"},
+				{ID: "3", Name: "status", DataType: "SINGLE_SELECT"},
+			},
+			[]github.ProjectFieldConfig{
+				{ID: "4", Name: "Start date", DataType: "DATE"},
+				{ID: "5", Name: "End date", DataType: "DATE"},
+				{ID: "6", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "o1", Name: status}}},
+			},
+			[]string{issueURL},
+			[]string{issueURL},
+			nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID != "project_1" {
+			return []github.ProjectField{}, nil
+		}
+		return []github.ProjectField{
+			{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &date}},
+			{ID: "2", Name: "end", Value: github.ProjectFieldValue{Date: &date}},
+			{ID: "3", Name: "status", Value: github.ProjectFieldValue{Text: &status}},
+		}, nil
+	}
+	var order []string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		order = append(order, field.Name)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		[]string{issueURL},
+		[]string{"start=Start date", "end=End date@2", "status=Status@1"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"Status", "End date", "Start date"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("expected fields to be updated in order %v, got %v", want, order)
+	}
+}
+
+func TestSyncFieldsSummary(t *testing.T) {
+	now := time.Now()
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}
+
+	mockClient := newSyncMockClient(issues, now)
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &now}}}, nil
+		}
+		if issueURL == issues[0] {
+			return []github.ProjectField{{ID: "2", Name: "Start date", Value: github.ProjectFieldValue{Date: &now}}}, nil
+		}
+		return []github.ProjectField{}, nil
+	}
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		if issueURL == issues[1] {
+			return errors.New("update failed")
+		}
+		return nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	summary := service.Summary()
+	if summary.IssuesProcessed != 3 {
+		t.Errorf("expected 3 processed issues, got %d", summary.IssuesProcessed)
+	}
+	if summary.FieldsUpdated != 1 {
+		t.Errorf("expected 1 updated field, got %d", summary.FieldsUpdated)
+	}
+	if summary.FieldsSkipped != 1 {
+		t.Errorf("expected 1 skipped field, got %d", summary.FieldsSkipped)
+	}
+	if summary.FieldsCleared != 0 {
+		t.Errorf("expected no cleared fields, got %d", summary.FieldsCleared)
+	}
+	if len(summary.Errors) != 1 || summary.Errors[0].IssueURL != issues[1] {
+		t.Errorf("expected a single error for %s, got %v", issues[1], summary.Errors)
+	}
+}
+
+func TestSyncFieldsCountsClearedFields(t *testing.T) {
+	now := time.Now()
+	issues := []string{"https://github.com/org/repo/issues/1"}
+
+	mockClient := newSyncMockClient(issues, now)
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{
+				{ID: "1", Name: "start", DataType: "DATE"},
+				{ID: "3", Name: "end", DataType: "DATE"},
+			},
+			[]github.ProjectFieldConfig{
+				{ID: "2", Name: "Start date", DataType: "DATE"},
+				{ID: "4", Name: "End date", DataType: "DATE"},
+			},
+			issues, issues, nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{
+				{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &now}},
+				{ID: "3", Name: "end"},
+			}, nil
+		}
+		return []github.ProjectField{
+			{ID: "2", Name: "Start date", Value: github.ProjectFieldValue{Date: &now}},
+			{ID: "4", Name: "End date", Value: github.ProjectFieldValue{Date: &now}},
+		}, nil
+	}
+	var cleared []string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		if field.Value.IsEmpty() {
+			cleared = append(cleared, field.Name)
+		}
+		return nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date", "end=End date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(cleared, []string{"End date"}) {
+		t.Errorf("expected End date to be cleared, got %v", cleared)
+	}
+	summary := service.Summary()
+	if summary.FieldsCleared != 1 {
+		t.Errorf("expected 1 cleared field, got %d", summary.FieldsCleared)
+	}
+	if summary.FieldsSkipped != 1 {
+		t.Errorf("expected 1 skipped field, got %d", summary.FieldsSkipped)
+	}
+}
+
+func TestSyncFieldsAcrossOwners(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectIDFunc = func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+		switch {
+		case projectInfo.OwnerType == github.ProjectOwnerTypeOrg && projectInfo.OwnerLogin == "myorg":
+			return "project_1", nil
+		case projectInfo.OwnerType == github.ProjectOwnerTypeUser && projectInfo.OwnerLogin == "someone":
+			return "project_2", nil
+		}
+		return "", fmt.Errorf("unexpected project %+v", projectInfo)
+	}
+
+	var updatedProjects []string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updatedProjects = append(updatedProjects, projectID)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/users/someone/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updatedProjects) != 1 || updatedProjects[0] != "project_2" {
+		t.Errorf("expected the user project to be updated, got %v", updatedProjects)
+	}
+}
+
+func TestSyncFieldsShiftsDatesByOffset(t *testing.T) {
+	date := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	issues := []string{"https://github.com/org/repo/issues/1"}
+
+	for _, tt := range []struct {
+		mapping string
+		want    string
+	}{
+		{mapping: "start=Start date:+7d", want: "2024-01-17"},
+		{mapping: "start=Start date:-1w", want: "2024-01-03"},
+	} {
+		t.Run(tt.mapping, func(t *testing.T) {
+			var written []string
+			mockClient := newSyncMockClient(issues, date)
+			mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+				written = append(written, field.Value.String())
+				return nil
+			}
+
+			service := NewService(mockClient, Options{})
+			err := service.SyncFields(
+				context.Background(),
+				"https://github.com/orgs/myorg/projects/824",
+				"https://github.com/orgs/myorg/projects/825",
+				issues,
+				[]string{tt.mapping},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(written) != 1 || written[0] != tt.want {
+				t.Errorf("expected %s to be written, got %v", tt.want, written)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsRejectsDateOffsetOnNonDateField(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{{ID: "1", Name: "Status", DataType: "SINGLE_SELECT"}},
+			[]github.ProjectFieldConfig{{ID: "2", Name: "Status", DataType: "SINGLE_SELECT"}},
+			issues,
+			issues,
+			nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"Status=Status:+7d"},
+	)
+	if err == nil || !strings.Contains(err.Error(), `source field "Status" is of type SINGLE_SELECT`) {
+		t.Errorf("expected an error about the non-date field, got %v", err)
+	}
+}
+
+func TestSyncFieldsRemapsSingleSelectValues(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+	sourceValues := map[string]string{issues[0]: "WIP", issues[1]: "Blocked"}
+
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{{ID: "1", Name: "Status", DataType: "SINGLE_SELECT"}},
+			[]github.ProjectFieldConfig{{ID: "2", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{
+				{ID: "o1", Name: "In Progress"},
+				{ID: "o2", Name: "Blocked"},
+			}}},
+			issues,
+			issues,
+			nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID != "project_1" {
+			return []github.ProjectField{}, nil
+		}
+		value := sourceValues[issueURL]
+		return []github.ProjectField{{Name: "Status", Value: github.ProjectFieldValue{Text: &value}}}, nil
+	}
+
+	written := make(map[string]string)
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		written[issueURL] = field.Value.String()
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"Status=Status{WIP:In Progress,Done:Complete}"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{issues[0]: "In Progress", issues[1]: "Blocked"}
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("expected written values %v, got %v", want, written)
+	}
+}
+
+func TestSyncFieldsSetsMilestones(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+	release := "v1.0"
+
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{{ID: "1", Name: "Release", DataType: "SINGLE_SELECT"}},
+			[]github.ProjectFieldConfig{{ID: "2", Name: "Milestone", DataType: "MILESTONE"}},
+			issues,
+			issues,
+			nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{{Name: "Release", Value: github.ProjectFieldValue{Text: &release}}}, nil
+		}
+		if issueURL == issues[0] {
+			return []github.ProjectField{{Name: "Milestone", Value: github.ProjectFieldValue{Milestone: &release}}}, nil
+		}
+		return []github.ProjectField{}, nil
+	}
+
+	var updated []string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updated = append(updated, issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"Release=Milestone"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first issue is already in the milestone
+	if !reflect.DeepEqual(updated, issues[1:]) {
+		t.Errorf("expected only %v to be updated, got %v", issues[1:], updated)
+	}
+}
+
+func TestSyncFieldsAddsLabels(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+	team := "team-a"
+
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{{ID: "1", Name: "Team", DataType: "SINGLE_SELECT"}},
+			[]github.ProjectFieldConfig{{ID: "2", Name: "Labels", DataType: "LABELS"}},
+			issues,
+			issues,
+			nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{{Name: "Team", Value: github.ProjectFieldValue{Text: &team}}}, nil
+		}
+		if issueURL == issues[0] {
+			return []github.ProjectField{{Name: "Labels", Value: github.ProjectFieldValue{Labels: []string{"bug", "Team-A"}}}}, nil
+		}
+		return []github.ProjectField{{Name: "Labels", Value: github.ProjectFieldValue{Labels: []string{"bug"}}}}, nil
+	}
+
+	var updated []string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updated = append(updated, issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"Team=Labels"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first issue already carries the label
+	if !reflect.DeepEqual(updated, issues[1:]) {
+		t.Errorf("expected only %v to be updated, got %v", issues[1:], updated)
+	}
+}
+
+func TestSyncFieldsReplacesLabelsOfSourceOptions(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	frontend := "Frontend"
+
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		return []github.ProjectFieldConfig{{ID: "1", Name: "Team", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{
+				{ID: "opt_1", Name: "Backend"},
+				{ID: "opt_2", Name: frontend},
+			}}},
+			[]github.ProjectFieldConfig{{ID: "2", Name: "Labels", DataType: "LABELS"}},
+			issues,
+			issues,
+			nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{{Name: "Team", Value: github.ProjectFieldValue{Text: &frontend}}}, nil
+		}
+		return []github.ProjectField{{Name: "Labels", Value: github.ProjectFieldValue{Labels: []string{"bug", "backend"}}}}, nil
+	}
+
+	var written [][]string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		written = append(written, field.Value.Labels)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"Team=Labels"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The label of the previous option is replaced, matched ignoring case, and other labels are kept
+	if want := [][]string{{"bug", "Frontend"}}; !reflect.DeepEqual(written, want) {
+		t.Errorf("expected labels %v to be written, got %v", want, written)
+	}
+}
+
+func TestSyncFieldsPrefetchesIssueTitlesPerBatch(t *testing.T) {
+	var issues []string
+	for i := 1; i <= 15; i++ {
+		issues = append(issues, fmt.Sprintf("https://github.com/org/repo/issues/%d", i))
+	}
+
+	var prefetched [][]string
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetIssueTitlesFunc = func(ctx context.Context, issueURLs []string) (map[string]string, error) {
+		prefetched = append(prefetched, issueURLs)
+		return nil, nil
+	}
+
+	service := NewService(mockClient, Options{})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]string{issues[:10], issues[10:]}
+	if !reflect.DeepEqual(prefetched, want) {
+		t.Errorf("expected titles to be prefetched per batch %v, got %v", want, prefetched)
+	}
+}
+
+func TestSyncFieldsDryRunPrefetchesAllIssueTitles(t *testing.T) {
+	var issues []string
+	for i := 1; i <= 15; i++ {
+		issues = append(issues, fmt.Sprintf("https://github.com/org/repo/issues/%d", i))
+	}
+
+	var prefetched [][]string
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetIssueTitlesFunc = func(ctx context.Context, issueURLs []string) (map[string]string, error) {
+		prefetched = append(prefetched, issueURLs)
+		return map[string]string{issues[0]: "First issue"}, nil
+	}
+	mockClient.GetIssueTitleFunc = func(ctx context.Context, issueURL string) (string, error) {
+		if issueURL == issues[0] {
+			return "First issue", nil
+		}
+		return "", fmt.Errorf("issue %s not found in cache", issueURL)
+	}
+
+	service := NewService(mockClient, Options{DryRun: true})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(prefetched, [][]string{issues}) {
+		t.Errorf("expected all titles to be prefetched at once, got %v", prefetched)
+	}
+
+	report := service.Report()
+	if report.Issues[0].Title != "First issue" {
+		t.Errorf("expected the prefetched title in the report, got %q", report.Issues[0].Title)
+	}
+	// Titles that cannot be resolved fall back to the URL
+	if report.Issues[1].Title != issues[1] {
+		t.Errorf("expected the URL as title of an unresolved issue, got %q", report.Issues[1].Title)
+	}
+}
+
+func TestSyncFieldsStopsAtDeadline(t *testing.T) {
+	var issues []string
+	for i := 1; i <= 25; i++ {
+		issues = append(issues, fmt.Sprintf("https://github.com/org/repo/issues/%d", i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	var mu sync.Mutex
+	var updated []string
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		mu.Lock()
+		defer mu.Unlock()
+		updated = append(updated, issueURL)
+		if len(updated) == 3 {
+			// Expire the deadline in the middle of the first batch
+			cancel()
+			return context.DeadlineExceeded
+		}
+		return ctx.Err()
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		ctx,
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	if len(updated) != 3 {
+		t.Errorf("expected no updates after the deadline, got %d", len(updated))
+	}
+}
+
+func TestSyncFieldsValidatesMappingFields(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	mappings := []string{"start=Start date", "strat=Start date", "start=Due"}
+
+	t.Run("strict", func(t *testing.T) {
+		mockClient := newSyncMockClient(issues, time.Now())
+		mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			t.Errorf("expected no updates, got %s", field.Name)
+			return nil
+		}
+
+		service := NewService(mockClient, Options{})
+		err := service.SyncFields(
+			context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/orgs/myorg/projects/825",
+			issues,
+			mappings,
+		)
+		if err == nil || !strings.Contains(err.Error(), `"strat"`) || !strings.Contains(err.Error(), `"Due"`) {
+			t.Errorf("expected an error listing both unknown fields, got %v", err)
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		var updated []string
+		mockClient := newSyncMockClient(issues, time.Now())
+		mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			updated = append(updated, field.Name)
+			return nil
+		}
+
+		service := NewService(mockClient, Options{LenientMappings: true})
+		err := service.SyncFields(
+			context.Background(),
+			"https://github.com/orgs/myorg/projects/824",
+			"https://github.com/orgs/myorg/projects/825",
+			issues,
+			mappings,
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(updated) != 2 {
+			t.Errorf("expected the valid mappings to be applied, got %v", updated)
+		}
+	})
+}
+
+func TestSyncFieldsPreviewDoesNotUpdate(t *testing.T) {
+	now := time.Now()
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+
+	mockClient := newSyncMockClient(issues, now)
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_1" {
+			return []github.ProjectField{{ID: "1", Name: "start", Value: github.ProjectFieldValue{Date: &now}}}, nil
+		}
+		if issueURL == issues[0] {
+			return []github.ProjectField{{ID: "2", Name: "Start date", Value: github.ProjectFieldValue{Date: &now}}}, nil
+		}
+		return []github.ProjectField{}, nil
+	}
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		t.Errorf("unexpected update of %s in preview mode", issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Preview: true, Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := service.Result()
+	if len(result.Changes) != 1 || result.Changes[0].IssueURL != issues[1] {
+		t.Errorf("expected a single change for %s, got %v", issues[1], result.Changes)
+	}
+	if len(result.Unchanged) != 1 || result.Unchanged[0].IssueURL != issues[0] {
+		t.Errorf("expected a single unchanged field for %s, got %v", issues[0], result.Unchanged)
+	}
+	if !service.Summary().DryRun {
+		t.Error("expected a preview to be reported as a dry run")
+	}
+}
+
+func TestSyncFieldsSkipsIssuesMissingInProject(t *testing.T) {
+	now := time.Now()
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+	}
+
+	mockClient := newSyncMockClient(issues, now)
+	getValues := mockClient.GetProjectFieldValuesFunc
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		if projectID == "project_2" && issueURL == issues[0] {
+			return nil, &client.NotFoundError{Kind: client.ErrIssueNotFound, Name: issueURL}
+		}
+		return getValues(ctx, projectID, issueURL, fieldConfigs)
+	}
+
+	var updated []string
+	mockClient.UpdateProjectFieldFunc = func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+		updated = append(updated, issueURL)
+		return nil
+	}
+
+	service := NewService(mockClient, Options{Concurrency: 1})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	checkSkippedMissingIssue(t, service, err, issues, updated)
+
+	// With fail-fast, a missing issue aborts the sync
+	updated = nil
+	service = NewService(mockClient, Options{Concurrency: 1, FailFast: true})
+	err = service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"start=Start date"},
+	)
+	if !errors.Is(err, client.ErrIssueNotFound) || len(updated) != 0 {
+		t.Errorf("expected the sync to abort, got error %v and updates %v", err, updated)
+	}
+
+	// The options of single select mappings are checked before syncing, which skips issues
+	// missing in the source project as well
+	done := "Done"
+	mockClient.GetProjectFieldConfigsAndIssuesFunc = func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+		status := github.ProjectFieldConfig{Name: "Status", Type: "ProjectV2SingleSelectField", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{{ID: "opt_done", Name: done}}}
+		sourceStatus, targetStatus := status, status
+		sourceStatus.ID, targetStatus.ID = "3", "4"
+		return []github.ProjectFieldConfig{sourceStatus}, []github.ProjectFieldConfig{targetStatus}, issues, issues, nil
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		switch {
+		case projectID == "project_1" && issueURL == issues[0]:
+			return nil, &client.NotFoundError{Kind: client.ErrIssueNotFound, Name: issueURL}
+		case projectID == "project_1":
+			return []github.ProjectField{{ID: "3", Name: "Status", Value: github.ProjectFieldValue{Text: &done}}}, nil
+		default:
+			return nil, nil
+		}
+	}
+	updated = nil
+	service = NewService(mockClient, Options{Concurrency: 1})
+	err = service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		issues,
+		[]string{"Status=Status"},
+	)
+	checkSkippedMissingIssue(t, service, err, issues, updated)
+}
+
+// checkSkippedMissingIssue checks that a sync skipped the first of the issues, which is
+// missing in one of the projects, reported it once and synced the others
+func checkSkippedMissingIssue(t *testing.T, service *Service, err error, issues, updated []string) {
+	t.Helper()
+	if !errors.Is(err, client.ErrIssueNotFound) {
+		t.Fatalf("expected an issue not found error, got %v", err)
+	}
+	if !reflect.DeepEqual(updated, issues[1:]) {
+		t.Errorf("expected the other issues to be synced, got %v", updated)
+	}
+	if errs := service.Result().Errors; len(errs) != 1 || errs[0].IssueURL != issues[0] {
+		t.Errorf("expected the missing issue to be reported once, got %v", errs)
+	}
+}
+
+func TestGetFieldValuesForBatchReadsProjectsConcurrently(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1", "https://github.com/org/repo/issues/2"}
+	mockClient := newSyncMockClient(issues, time.Now())
+	getFieldValues := mockClient.GetProjectFieldValuesFunc
+
+	// Each read waits for the read of the other project, which only returns if both
+	// reads of an issue run at the same time
+	var mu sync.Mutex
+	started := make(map[string]chan struct{})
+	startedFor := func(key string) chan struct{} {
+		mu.Lock()
+		defer mu.Unlock()
+		if started[key] == nil {
+			started[key] = make(chan struct{})
+		}
+		return started[key]
+	}
+	mockClient.GetProjectFieldValuesFunc = func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+		other := "project_2"
+		if projectID == "project_2" {
+			other = "project_1"
+		}
+		close(startedFor(projectID + issueURL))
+		select {
+		case <-startedFor(other + issueURL):
+		case <-time.After(5 * time.Second):
+			return nil, fmt.Errorf("%s of %s was not read concurrently", other, issueURL)
+		}
+		return getFieldValues(ctx, projectID, issueURL, fieldConfigs)
+	}
+
+	service := NewService(mockClient, Options{DryRun: true})
+	sourceValues, targetValues, missing, err := service.getFieldValuesForBatch(context.Background(), "project_1", "project_2", issues, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(missing) > 0 {
+		t.Fatalf("unexpected missing issues: %v", missing)
+	}
+	if len(sourceValues) != 2 || len(targetValues) != 2 {
+		t.Errorf("expected values of both issues, got %d source and %d target", len(sourceValues), len(targetValues))
+	}
 }
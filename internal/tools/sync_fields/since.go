@@ -0,0 +1,55 @@
+package sync_fields
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSince parses the --since window into the time issues must have been updated after.
+// It accepts a duration before now such as 24h or 90m, a number of days such as 7d, a date
+// such as 2024-01-01 (midnight UTC) or an RFC 3339 timestamp.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("invalid number of days %q", value)
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid duration %q: must not be negative", value)
+		}
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid value %q, expected a duration such as 24h or 7d, or a date such as 2024-01-01", value)
+}
+
+// filterIssuesBySince keeps the issues updated at or after the given time
+func (s *Service) filterIssuesBySince(ctx context.Context, issues []string) ([]string, error) {
+	var filtered []string
+	for _, issueURL := range issues {
+		updatedAt, err := s.client.GetIssueUpdatedAt(ctx, issueURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get update time of %s: %w", issueURL, err)
+		}
+		if !updatedAt.Before(s.since) {
+			filtered = append(filtered, issueURL)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,103 @@
+package sync_fields
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "24h", want: time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)},
+		{value: "90m", want: time.Date(2024, 3, 10, 10, 30, 0, 0, time.UTC)},
+		{value: "7d", want: time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)},
+		{value: "2024-01-01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-01-01T08:30:00Z", want: time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC)},
+		{value: "-24h", wantErr: true},
+		{value: "xd", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsFiltersIssuesBySince(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/issues/3",
+	}
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := map[string]time.Time{
+		issues[0]: since.Add(time.Hour),
+		issues[1]: since.Add(-time.Hour),
+		issues[2]: since,
+	}
+
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetIssueUpdatedAtFunc = func(ctx context.Context, issueURL string) (time.Time, error) {
+		return updatedAt[issueURL], nil
+	}
+
+	service := NewService(mockClient, Options{DryRun: true, Since: since})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{issues[0], issues[2]}; !reflect.DeepEqual(service.Result().Issues, want) {
+		t.Errorf("expected issues %v, got %v", want, service.Result().Issues)
+	}
+}
+
+func TestSyncFieldsWithoutIssuesUpdatedSince(t *testing.T) {
+	issues := []string{"https://github.com/org/repo/issues/1"}
+	mockClient := newSyncMockClient(issues, time.Now())
+	mockClient.GetIssueUpdatedAtFunc = func(ctx context.Context, issueURL string) (time.Time, error) {
+		return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	service := NewService(mockClient, Options{DryRun: true, Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err != nil {
+		t.Fatalf("expected an incremental sync without updated issues to succeed, got: %v", err)
+	}
+	if changes := service.Result().Changes; len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
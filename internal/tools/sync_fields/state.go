@@ -0,0 +1,42 @@
+package sync_fields
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+const (
+	// IssueStateAll keeps issues regardless of their state
+	IssueStateAll = "all"
+	// IssueStateOpen keeps open issues
+	IssueStateOpen = "open"
+	// IssueStateClosed keeps closed issues, including merged pull requests
+	IssueStateClosed = "closed"
+)
+
+// validateIssueState checks that the issue state filter is known
+func validateIssueState(state string) error {
+	switch state {
+	case IssueStateAll, IssueStateOpen, IssueStateClosed:
+		return nil
+	default:
+		return fmt.Errorf("invalid issue state %q, must be %s, %s or %s", state, IssueStateOpen, IssueStateClosed, IssueStateAll)
+	}
+}
+
+// filterIssuesByState keeps the issues in the state of the issue state filter
+func (s *Service) filterIssuesByState(ctx context.Context, issues []string) ([]string, error) {
+	var filtered []string
+	for _, issueURL := range issues {
+		state, err := s.client.GetIssueState(ctx, issueURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state of %s: %w", issueURL, err)
+		}
+		if (state == client.IssueStateOpen) == (s.issueState == IssueStateOpen) {
+			filtered = append(filtered, issueURL)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,76 @@
+package sync_fields
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+func TestSyncFieldsFiltersIssuesByState(t *testing.T) {
+	issues := []string{
+		"https://github.com/org/repo/issues/1",
+		"https://github.com/org/repo/issues/2",
+		"https://github.com/org/repo/pull/3",
+	}
+	states := map[string]string{
+		issues[0]: client.IssueStateOpen,
+		issues[1]: client.IssueStateClosed,
+		issues[2]: client.IssueStateMerged,
+	}
+
+	tests := []struct {
+		state        string
+		want         []string
+		wantFiltered int
+	}{
+		{state: IssueStateOpen, want: issues[:1], wantFiltered: 2},
+		{state: IssueStateClosed, want: issues[1:], wantFiltered: 1},
+		{state: IssueStateAll, want: issues},
+		{state: "", want: issues},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			mockClient := newSyncMockClient(issues, time.Now())
+			mockClient.GetIssueStateFunc = func(ctx context.Context, issueURL string) (string, error) {
+				return states[issueURL], nil
+			}
+
+			service := NewService(mockClient, Options{DryRun: true, IssueState: tt.state})
+			err := service.SyncFields(
+				context.Background(),
+				"https://github.com/orgs/myorg/projects/824",
+				"https://github.com/orgs/myorg/projects/825",
+				nil,
+				[]string{"start=Start date"},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(service.Result().Issues, tt.want) {
+				t.Errorf("expected issues %v, got %v", tt.want, service.Result().Issues)
+			}
+			if got := service.Summary().FilteredByState; got != tt.wantFiltered {
+				t.Errorf("expected %d issues filtered by state, got %d", tt.wantFiltered, got)
+			}
+		})
+	}
+}
+
+func TestSyncFieldsRejectsInvalidIssueState(t *testing.T) {
+	service := NewService(newSyncMockClient(nil, time.Now()), Options{IssueState: "draft"})
+	err := service.SyncFields(
+		context.Background(),
+		"https://github.com/orgs/myorg/projects/824",
+		"https://github.com/orgs/myorg/projects/825",
+		nil,
+		[]string{"start=Start date"},
+	)
+	if err == nil || err.Error() != `invalid issue state "draft", must be open, closed or all` {
+		t.Errorf("expected an invalid issue state error, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package sync_fields
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+// minSuggestionSimilarity is the similarity below which field names are not suggested as a mapping
+const minSuggestionSimilarity = 0.6
+
+// SuggestedMapping is a field mapping suggested from the similarity of the field names
+type SuggestedMapping struct {
+	FieldMapping
+	// Similarity is the similarity of the field names, from 0 to 1
+	Similarity float64
+}
+
+// SuggestFieldMappings suggests field mappings between the source and target project by
+// matching field names of the same type. Nothing is written to either project.
+func (s *Service) SuggestFieldMappings(ctx context.Context, sourceProjectURL, targetProjectURL string) ([]SuggestedMapping, error) {
+	sourceProject, targetProject, _, err := s.parseInputs(sourceProjectURL, targetProjectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceProjectID, targetProjectID, err := s.getProjectIDs(ctx, sourceProject, targetProject)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceConfigs, err := s.client.GetProjectFieldConfigs(ctx, sourceProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source project fields: %w", err)
+	}
+
+	targetConfigs, err := s.client.GetProjectFieldConfigs(ctx, targetProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target project fields: %w", err)
+	}
+
+	return suggestMappings(sourceConfigs, targetConfigs), nil
+}
+
+// suggestMappings pairs each syncable source field with the most similar target field of the
+// same data type. Each field is used in at most one mapping, preferring the most similar pairs.
+func suggestMappings(sourceConfigs, targetConfigs []github.ProjectFieldConfig) []SuggestedMapping {
+	var candidates []SuggestedMapping
+	for _, source := range sourceConfigs {
+		if !isSyncableField(source) {
+			continue
+		}
+		for _, target := range targetConfigs {
+			if target.DataType != source.DataType {
+				continue
+			}
+			similarity := nameSimilarity(source.Name, target.Name)
+			if similarity < minSuggestionSimilarity {
+				continue
+			}
+			candidates = append(candidates, SuggestedMapping{
+				FieldMapping: FieldMapping{SourceField: source.Name, TargetField: target.Name},
+				Similarity:   similarity,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+
+	usedSources := make(map[string]bool)
+	usedTargets := make(map[string]bool)
+	var suggestions []SuggestedMapping
+	for _, candidate := range candidates {
+		if usedSources[candidate.SourceField] || usedTargets[candidate.TargetField] {
+			continue
+		}
+		usedSources[candidate.SourceField] = true
+		usedTargets[candidate.TargetField] = true
+		suggestions = append(suggestions, candidate)
+	}
+
+	// Keep the order of the source fields in the suggestions
+	position := make(map[string]int, len(sourceConfigs))
+	for i, source := range sourceConfigs {
+		position[source.Name] = i
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return position[suggestions[i].SourceField] < position[suggestions[j].SourceField]
+	})
+
+	return suggestions
+}
+
+// isSyncableField checks if values of the field can be synced
+func isSyncableField(config github.ProjectFieldConfig) bool {
+	switch config.DataType {
+	case "DATE", "SINGLE_SELECT", "ASSIGNEES", "MILESTONE":
+		return true
+	default:
+		return false
+	}
+}
+
+// nameSimilarity scores the similarity of two field names from 0 to 1, ignoring case,
+// whitespace and punctuation. A name contained in the other scores at least 0.5.
+func nameSimilarity(a, b string) float64 {
+	a, b = normalizeFieldName(a), normalizeFieldName(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	shorter, longer := a, b
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+
+	similarity := 1 - float64(levenshtein(a, b))/float64(len(longer))
+	if strings.Contains(longer, shorter) {
+		similarity = max(similarity, 0.5+0.5*float64(len(shorter))/float64(len(longer)))
+	}
+	return similarity
+}
+
+// normalizeFieldName lowercases a field name and strips everything but letters and digits
+func normalizeFieldName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,66 @@
+package sync_fields
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		atLeast float64
+		below   float64
+	}{
+		{a: "Start date", b: "start_date", atLeast: 1, below: 1.01},
+		{a: "Start date", b: "Start", atLeast: 0.75, below: 0.8},
+		{a: "Target date", b: "Target Dates", atLeast: 0.9, below: 1},
+		{a: "Status", b: "Priority", atLeast: 0, below: minSuggestionSimilarity},
+		{a: "", b: "Status", atLeast: 0, below: 0.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			got := nameSimilarity(tt.a, tt.b)
+			if got < tt.atLeast || got >= tt.below {
+				t.Errorf("expected similarity in [%v, %v), got %v", tt.atLeast, tt.below, got)
+			}
+		})
+	}
+}
+
+func TestSuggestMappings(t *testing.T) {
+	sourceConfigs := []github.ProjectFieldConfig{
+		{Name: "Title", DataType: "TITLE"},
+		{Name: "Start date", DataType: "DATE"},
+		{Name: "End date", DataType: "DATE"},
+		{Name: "Status", DataType: "SINGLE_SELECT"},
+		{Name: "Sprint", DataType: "ITERATION"},
+		{Name: "Estimate", DataType: "SINGLE_SELECT"},
+	}
+	targetConfigs := []github.ProjectFieldConfig{
+		{Name: "Title", DataType: "TITLE"},
+		{Name: "End", DataType: "DATE"},
+		{Name: "Start", DataType: "DATE"},
+		{Name: "Start date", DataType: "TEXT"},
+		{Name: "Status", DataType: "SINGLE_SELECT"},
+		{Name: "Sprint", DataType: "ITERATION"},
+		{Name: "Size", DataType: "SINGLE_SELECT"},
+	}
+
+	got := suggestMappings(sourceConfigs, targetConfigs)
+
+	var mappings []FieldMapping
+	for _, suggestion := range got {
+		mappings = append(mappings, suggestion.FieldMapping)
+	}
+	want := []FieldMapping{
+		{SourceField: "Start date", TargetField: "Start"},
+		{SourceField: "End date", TargetField: "End"},
+		{SourceField: "Status", TargetField: "Status"},
+	}
+	if !reflect.DeepEqual(mappings, want) {
+		t.Errorf("expected mappings %v, got %v", want, mappings)
+	}
+}
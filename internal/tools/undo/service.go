@@ -0,0 +1,185 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/tools/set_field"
+)
+
+type Service struct {
+	client client.Client
+	dryRun bool
+}
+
+func NewService(client client.Client, dryRun bool) *Service {
+	return &Service{
+		client: client,
+		dryRun: dryRun,
+	}
+}
+
+// Result lists the journal entries that were reverted and those that were skipped
+type Result struct {
+	Reverted []client.JournalEntry
+	Skipped  []SkippedEntry
+}
+
+// SkippedEntry is a journal entry that was not reverted
+type SkippedEntry struct {
+	Entry  client.JournalEntry
+	Reason string
+}
+
+// Undo restores the old values of journal entries, latest first, so that several changes
+// of the same field are undone in turn. Entries whose field no longer holds the recorded
+// new value were edited in the meantime and are skipped rather than overwritten.
+func (s *Service) Undo(ctx context.Context, entries []client.JournalEntry) (Result, error) {
+	var result Result
+
+	// The values an undone field holds in dry run mode, where the cache is not updated
+	planned := make(map[string]string)
+
+	var projectID string
+	var configs []github.ProjectFieldConfig
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+
+		// Load the project with all its items, which updates look the issue up in
+		if entry.ProjectID != projectID {
+			var err error
+			configs, _, _, _, err = s.client.GetProjectFieldConfigsAndIssues(ctx, entry.ProjectID, entry.ProjectID)
+			if err != nil {
+				return result, fmt.Errorf("failed to get project fields and issues: %w", err)
+			}
+			projectID = entry.ProjectID
+		}
+
+		reason, err := s.revert(ctx, entry, configs, planned)
+		if err != nil {
+			return result, err
+		}
+		if reason != "" {
+			slog.Warn("skipping journal entry", "issue", entry.IssueURL, "field", entry.Field, "reason", reason)
+			result.Skipped = append(result.Skipped, SkippedEntry{Entry: entry, Reason: reason})
+			continue
+		}
+		result.Reverted = append(result.Reverted, entry)
+	}
+	return result, nil
+}
+
+// revert restores the old value of a journal entry. It returns the reason if the entry
+// cannot be reverted.
+func (s *Service) revert(ctx context.Context, entry client.JournalEntry, configs []github.ProjectFieldConfig, planned map[string]string) (string, error) {
+	config, ok := findFieldConfig(configs, entry)
+	if !ok {
+		return "field no longer exists", nil
+	}
+
+	current, err := s.currentValue(ctx, entry, configs, planned)
+	if errors.Is(err, client.ErrIssueNotFound) {
+		return "issue is no longer in the project", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !sameValue(config, current, entry.NewValue) {
+		return fmt.Sprintf("value was changed to %q since", current), nil
+	}
+
+	if entry.OldValue == "" {
+		field := github.ProjectField{ID: config.ID, Name: config.Name}
+		if err := s.client.ClearProjectField(ctx, entry.ProjectID, entry.IssueURL, field, s.dryRun); err != nil {
+			return "", fmt.Errorf("failed to clear field %s of %s: %w", config.Name, entry.IssueURL, err)
+		}
+	} else {
+		value, err := set_field.ParseFieldValue(config, entry.OldValue)
+		if err != nil {
+			return fmt.Sprintf("old value %q cannot be set: %v", entry.OldValue, err), nil
+		}
+		field := github.ProjectField{ID: config.ID, Name: config.Name, Value: value}
+		if err := s.client.UpdateProjectField(ctx, entry.ProjectID, entry.IssueURL, field, s.dryRun); err != nil {
+			return "", fmt.Errorf("failed to restore field %s of %s: %w", config.Name, entry.IssueURL, err)
+		}
+	}
+
+	if s.dryRun {
+		planned[plannedKey(entry)] = entry.OldValue
+	}
+	return "", nil
+}
+
+// currentValue returns the current value of the field of a journal entry, or the value it
+// would hold after the entries undone so far in dry run mode
+func (s *Service) currentValue(ctx context.Context, entry client.JournalEntry, configs []github.ProjectFieldConfig, planned map[string]string) (string, error) {
+	if value, ok := planned[plannedKey(entry)]; ok {
+		return value, nil
+	}
+
+	fields, err := s.client.GetProjectFieldValues(ctx, entry.ProjectID, entry.IssueURL, configs)
+	if err != nil {
+		return "", fmt.Errorf("failed to get field values of %s: %w", entry.IssueURL, err)
+	}
+	for _, field := range fields {
+		if matchesEntry(entry, field.ID, field.Name) {
+			return field.Value.String(), nil
+		}
+	}
+	return "", nil
+}
+
+// sameValue checks if the current value of a field is the recorded value. Assignees and
+// labels may be listed in any order.
+func sameValue(config github.ProjectFieldConfig, current, recorded string) bool {
+	if current == recorded {
+		return true
+	}
+	if config.DataType != "ASSIGNEES" && config.DataType != "LABELS" || current == "" || recorded == "" {
+		return false
+	}
+	currentValue, err := set_field.ParseFieldValue(config, current)
+	if err != nil {
+		return false
+	}
+	recordedValue, err := set_field.ParseFieldValue(config, recorded)
+	if err != nil {
+		return false
+	}
+	if config.DataType == "LABELS" {
+		return github.SameLabels(currentValue.Labels, recordedValue.Labels)
+	}
+	return github.SameLogins(currentValue.Users, recordedValue.Users)
+}
+
+// findFieldConfig finds the field of a journal entry
+func findFieldConfig(configs []github.ProjectFieldConfig, entry client.JournalEntry) (github.ProjectFieldConfig, bool) {
+	for _, config := range configs {
+		if matchesEntry(entry, config.ID, config.Name) {
+			return config, true
+		}
+	}
+	return github.ProjectFieldConfig{}, false
+}
+
+// matchesEntry checks if a field with the given ID and name is the field of a journal entry,
+// comparing IDs if the entry has one and names otherwise
+func matchesEntry(entry client.JournalEntry, id, name string) bool {
+	if entry.FieldID != "" {
+		return entry.FieldID == id
+	}
+	return entry.Field == name
+}
+
+// plannedKey identifies the field of an issue in a project
+func plannedKey(entry client.JournalEntry) string {
+	field := entry.Field
+	if entry.FieldID != "" {
+		field = entry.FieldID
+	}
+	return entry.ProjectID + "\x00" + entry.IssueURL + "\x00" + field
+}
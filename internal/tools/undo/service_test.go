@@ -0,0 +1,132 @@
+package undo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+var testConfigs = []github.ProjectFieldConfig{
+	{ID: "f1", Name: "Start date", DataType: "DATE"},
+	{ID: "f2", Name: "Status", DataType: "SINGLE_SELECT", Options: []github.ProjectFieldOption{
+		{ID: "o1", Name: "Todo"},
+		{ID: "o2", Name: "Done"},
+	}},
+	{ID: "f3", Name: "Assignees", DataType: "ASSIGNEES"},
+}
+
+// undoMockClient returns a client whose issues hold the given values, which are updated
+// like the real client does outside of dry run mode
+func undoMockClient(values map[string]map[string]string) (*client.MockClient, *[]string) {
+	var calls []string
+	return &client.MockClient{
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			return testConfigs, testConfigs, nil, nil, nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			issueValues, ok := values[issueURL]
+			if !ok {
+				return nil, &client.NotFoundError{Kind: client.ErrIssueNotFound, Name: issueURL}
+			}
+			var fields []github.ProjectField
+			for name, value := range issueValues {
+				value := value
+				fields = append(fields, github.ProjectField{Name: name, Value: github.ProjectFieldValue{Text: &value}})
+			}
+			return fields, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			calls = append(calls, "set "+field.Name+"="+field.Value.String())
+			if !dryRun {
+				values[issueURL][field.Name] = field.Value.String()
+			}
+			return nil
+		},
+		ClearProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			calls = append(calls, "clear "+field.Name)
+			if !dryRun {
+				delete(values[issueURL], field.Name)
+			}
+			return nil
+		},
+	}, &calls
+}
+
+func TestUndo(t *testing.T) {
+	issue1 := "https://github.com/org/repo/issues/1"
+	issue2 := "https://github.com/org/repo/issues/2"
+	entries := []client.JournalEntry{
+		{ProjectID: "PVT_1", IssueURL: issue1, Field: "Status", OldValue: "", NewValue: "Todo"},
+		{ProjectID: "PVT_1", IssueURL: issue1, Field: "Status", OldValue: "Todo", NewValue: "Done"},
+		{ProjectID: "PVT_1", IssueURL: issue2, Field: "Start date", OldValue: "2024-01-01", NewValue: "2024-02-01"},
+		{ProjectID: "PVT_1", IssueURL: issue1, Field: "Assignees", OldValue: "octocat", NewValue: "hubot, octocat"},
+		{ProjectID: "PVT_1", IssueURL: "https://github.com/org/repo/issues/3", Field: "Status", OldValue: "Todo", NewValue: "Done"},
+	}
+
+	for _, dryRun := range []bool{false, true} {
+		values := map[string]map[string]string{
+			issue1: {"Status": "Done", "Assignees": "octocat, hubot"},
+			// Edited after the sync
+			issue2: {"Start date": "2024-03-01"},
+		}
+		mockClient, calls := undoMockClient(values)
+
+		result, err := NewService(mockClient, dryRun).Undo(context.Background(), entries)
+		require.NoError(t, err)
+
+		// Entries are undone latest first, including both changes of the status
+		assert.Equal(t, []string{"set Assignees=octocat", "set Status=Todo", "clear Status"}, *calls, "dry run: %v", dryRun)
+		assert.Equal(t, []client.JournalEntry{entries[3], entries[1], entries[0]}, result.Reverted)
+
+		require.Len(t, result.Skipped, 2)
+		assert.Equal(t, entries[4], result.Skipped[0].Entry)
+		assert.Equal(t, "issue is no longer in the project", result.Skipped[0].Reason)
+		assert.Equal(t, entries[2], result.Skipped[1].Entry)
+		assert.Equal(t, `value was changed to "2024-03-01" since`, result.Skipped[1].Reason)
+
+		if !dryRun {
+			assert.Equal(t, map[string]string{"Assignees": "octocat"}, values[issue1])
+		}
+	}
+}
+
+func TestUndoRestoresFieldByID(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	todo, done := "Todo", "Done"
+	options := []github.ProjectFieldOption{{ID: "o1", Name: todo}, {ID: "o2", Name: done}}
+
+	var restored []string
+	mockClient := &client.MockClient{
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID string, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			// Two fields share the name Status
+			configs := []github.ProjectFieldConfig{
+				{ID: "f2", Name: "Status", DataType: "SINGLE_SELECT", Options: options},
+				{ID: "f4", Name: "Status", DataType: "SINGLE_SELECT", Options: options},
+			}
+			return configs, configs, nil, nil, nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID string, issueURL string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			return []github.ProjectField{
+				{ID: "f2", Name: "Status", Value: github.ProjectFieldValue{Text: &todo}},
+				{ID: "f4", Name: "Status", Value: github.ProjectFieldValue{Text: &done}},
+			}, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID string, issueURL string, field github.ProjectField, dryRun bool) error {
+			restored = append(restored, field.ID+"="+field.Value.String())
+			return nil
+		},
+	}
+
+	entries := []client.JournalEntry{
+		{ProjectID: "PVT_1", IssueURL: issueURL, Field: "Status", FieldID: "f4", OldValue: todo, NewValue: done},
+	}
+	result, err := NewService(mockClient, false).Undo(context.Background(), entries)
+	require.NoError(t, err)
+	assert.Equal(t, entries, result.Reverted)
+	assert.Equal(t, []string{"f4=Todo"}, restored)
+}
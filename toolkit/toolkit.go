@@ -0,0 +1,69 @@
+// Package toolkit is the entry point for using gh-project-toolkit as a library, to sync the
+// fields of GitHub projects in-process instead of running the command line tool.
+package toolkit
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+	"github.com/naag/gh-project-toolkit/internal/tools/sync_fields"
+)
+
+type (
+	// Client is the GitHub API client a sync reads and updates projects with
+	Client = client.Client
+	// ClientOptions configures the client created by NewClient
+	ClientOptions = client.Options
+	// SyncOptions configures how fields are synced, such as the concurrency and filters
+	SyncOptions = sync_fields.Options
+	// Service holds the outcome of a sync, see its Result, Summary, Report and
+	// TargetResults methods
+	Service = sync_fields.Service
+)
+
+// Options configures a sync
+type Options struct {
+	// Client is used for all API calls, such as one created by NewClient
+	Client Client
+	// SourceProjectURL is the URL of the project to copy field values from
+	SourceProjectURL string
+	// TargetProjectURLs are the URLs of the projects to copy field values to, in turn
+	TargetProjectURLs []string
+	// Issues are the URLs of the issues to sync. If empty, the issues in both the source
+	// and a target project are synced.
+	Issues []string
+	// FieldMappings map source to target fields in the format of --field-mapping, such
+	// as "Start=Start date"
+	FieldMappings []string
+	// DryRun disables all mutations
+	DryRun bool
+	// Sync configures the sync further. Its DryRun is set if DryRun is set.
+	Sync SyncOptions
+}
+
+// NewClient creates a client for the GitHub GraphQL API authenticated with the given tokens
+func NewClient(tokens oauth2.TokenSource, opts ClientOptions) (Client, error) {
+	return client.NewGraphQLClient(tokens, opts)
+}
+
+// Sync syncs the fields of the source project to each target project and returns the
+// service holding the outcome. The service is returned even if the sync fails, so that the
+// issues synced before the failure can be inspected.
+func Sync(ctx context.Context, opts Options) (*Service, error) {
+	if opts.Client == nil {
+		return nil, fmt.Errorf("no client")
+	}
+	if len(opts.TargetProjectURLs) == 0 {
+		return nil, fmt.Errorf("no target project")
+	}
+
+	syncOpts := opts.Sync
+	syncOpts.DryRun = syncOpts.DryRun || opts.DryRun
+
+	service := sync_fields.NewService(opts.Client, syncOpts)
+	err := service.SyncFieldsToTargets(ctx, opts.SourceProjectURL, opts.TargetProjectURLs, opts.Issues, opts.FieldMappings)
+	return service, err
+}
@@ -0,0 +1,62 @@
+package toolkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/naag/gh-project-toolkit/internal/github"
+	"github.com/naag/gh-project-toolkit/internal/github/client"
+)
+
+func TestSync(t *testing.T) {
+	issueURL := "https://github.com/org/repo/issues/1"
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var dryRuns []bool
+	mockClient := &client.MockClient{
+		GetProjectIDFunc: func(ctx context.Context, projectInfo *github.ProjectInfo) (string, error) {
+			if projectInfo.ProjectNumber == 1 {
+				return "source", nil
+			}
+			return "target", nil
+		},
+		GetProjectFieldConfigsAndIssuesFunc: func(ctx context.Context, sourceProjectID, targetProjectID string) ([]github.ProjectFieldConfig, []github.ProjectFieldConfig, []string, []string, error) {
+			return []github.ProjectFieldConfig{{ID: "f1", Name: "Start", DataType: "DATE"}},
+				[]github.ProjectFieldConfig{{ID: "f2", Name: "Start date", DataType: "DATE"}},
+				[]string{issueURL}, []string{issueURL}, nil
+		},
+		GetProjectFieldValuesFunc: func(ctx context.Context, projectID, url string, fieldConfigs []github.ProjectFieldConfig) ([]github.ProjectField, error) {
+			if projectID == "source" {
+				return []github.ProjectField{{ID: "f1", Name: "Start", Value: github.ProjectFieldValue{Date: &date}}}, nil
+			}
+			return nil, nil
+		},
+		UpdateProjectFieldFunc: func(ctx context.Context, projectID, url string, field github.ProjectField, dryRun bool) error {
+			dryRuns = append(dryRuns, dryRun)
+			return nil
+		},
+	}
+
+	service, err := Sync(context.Background(), Options{
+		Client:            mockClient,
+		SourceProjectURL:  "https://github.com/orgs/myorg/projects/1",
+		TargetProjectURLs: []string{"https://github.com/orgs/myorg/projects/2"},
+		FieldMappings:     []string{"Start=Start date"},
+		DryRun:            true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, dryRuns, "expected the dry run to be passed on")
+	assert.Equal(t, 1, service.Summary().FieldsUpdated)
+}
+
+func TestSyncRequiresClientAndTarget(t *testing.T) {
+	_, err := Sync(context.Background(), Options{TargetProjectURLs: []string{"https://github.com/orgs/myorg/projects/2"}})
+	assert.EqualError(t, err, "no client")
+
+	_, err = Sync(context.Background(), Options{Client: &client.MockClient{}})
+	assert.EqualError(t, err, "no target project")
+}